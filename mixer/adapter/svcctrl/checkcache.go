@@ -0,0 +1,189 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"time"
+
+	"istio.io/istio/mixer/pkg/adapter"
+	"istio.io/istio/mixer/pkg/cache"
+)
+
+// Parameters of the 32-bit FNV-1a hash sharding consumers.
+const (
+	fnvOffset32 = 2166136261
+	fnvPrime32  = 16777619
+)
+
+type (
+	checkCacheKey struct {
+		service    string
+		consumerID string
+		operation  string
+	}
+
+	// checkConsumerKey identifies the consumer of a service, whose cached results are evicted together.
+	checkConsumerKey struct {
+		service    string
+		consumerID string
+	}
+
+	checkCacheEntry struct {
+		result adapter.CheckResult
+		// Consumer project of the check, empty if the CheckResponse has no consumer info.
+		consumerProjectID string
+		// Read from time.Now, so that expiration is measured by the monotonic clock.
+		cachedAt  time.Time
+		expiresAt time.Time
+	}
+
+	// checkCacheShard is an independently locked segment of checkCache, holding the entries
+	// and evictions of the consumers hashed to it.
+	checkCacheShard struct {
+		entries cache.ExpiringCache
+		// Times consumers were last evicted keyed by checkConsumerKey, entries cached before are
+		// evicted on lookup.
+		evictions cache.ExpiringCache
+	}
+
+	// checkCache caches successful check results by consumer and operation. Entries expire
+	// after the ValidDuration of their results, and are evicted lazily on lookup or by the LRU
+	// cache of their shard once it's full. All entries of a consumer are evicted once it's
+	// denied. Consumers are sharded by hash, so that lookups of different consumers rarely
+	// contend for the same lock.
+	checkCache struct {
+		now    func() time.Time
+		shards []checkCacheShard
+		// Whether expired entries are kept until the LRU cache evicts them, so that they can
+		// be used while Google ServiceControl is unreachable.
+		keepExpired bool
+	}
+)
+
+// shard returns the shard of a consumer of service.
+func (c *checkCache) shard(service, consumerID string) *checkCacheShard {
+	if len(c.shards) == 1 {
+		return &c.shards[0]
+	}
+	// FNV-1a of service and consumer, computed in place since lookups are on the hot path.
+	h := uint32(fnvOffset32)
+	for _, part := range []string{service, consumerID} {
+		for i := 0; i < len(part); i++ {
+			h = (h ^ uint32(part[i])) * fnvPrime32
+		}
+		h *= fnvPrime32
+	}
+	return &c.shards[h%uint32(len(c.shards))]
+}
+
+// lookup returns the entry of key, evicts it if its consumer has been evicted since it's cached.
+func (c *checkCache) lookup(key checkCacheKey) (*checkCacheEntry, bool) {
+	shard := c.shard(key.service, key.consumerID)
+	value, found := shard.entries.Get(key)
+	if !found {
+		return nil, false
+	}
+	entry := value.(*checkCacheEntry)
+	if evictedAt, found := shard.evictions.Get(checkConsumerKey{key.service, key.consumerID}); found &&
+		!entry.cachedAt.After(evictedAt.(time.Time)) {
+		shard.entries.Remove(key)
+		return nil, false
+	}
+	return entry, true
+}
+
+// get returns the cached result of key with its remaining ValidDuration, evicts the entry if
+// it's expired. Ages of hits are recorded in checkCacheHitAge.
+func (c *checkCache) get(key checkCacheKey) (adapter.CheckResult, bool) {
+	entry, found := c.lookup(key)
+	if !found {
+		return adapter.CheckResult{}, false
+	}
+	remaining := entry.expiresAt.Sub(c.now())
+	if remaining <= 0 {
+		if !c.keepExpired {
+			c.shard(key.service, key.consumerID).entries.Remove(key)
+		}
+		return adapter.CheckResult{}, false
+	}
+	result := entry.result
+	result.ValidDuration = remaining
+	checkCacheHitAge.WithLabelValues(key.service).Observe((entry.result.ValidDuration - remaining).Seconds())
+	return result, true
+}
+
+// getExpired returns the cached result of key even if it's expired, with its original
+// ValidDuration.
+func (c *checkCache) getExpired(key checkCacheKey) (adapter.CheckResult, bool) {
+	entry, found := c.lookup(key)
+	if !found {
+		return adapter.CheckResult{}, false
+	}
+	return entry.result, true
+}
+
+// consumerProjectID returns the consumer project of the unexpired cached result of key.
+func (c *checkCache) consumerProjectID(key checkCacheKey) (string, bool) {
+	entry, found := c.lookup(key)
+	if !found || entry.consumerProjectID == "" || !entry.expiresAt.After(c.now()) {
+		return "", false
+	}
+	return entry.consumerProjectID, true
+}
+
+// evictConsumer evicts cached results of all operations of a consumer, e.g. once its API key
+// is denied.
+func (c *checkCache) evictConsumer(service, consumerID string) {
+	c.shard(service, consumerID).evictions.Set(checkConsumerKey{service, consumerID}, c.now())
+}
+
+// set caches result of key with its consumer project for its ValidDuration.
+func (c *checkCache) set(key checkCacheKey, result adapter.CheckResult, consumerProjectID string) {
+	now := c.now()
+	c.shard(key.service, key.consumerID).entries.Set(key, &checkCacheEntry{
+		result:            result,
+		consumerProjectID: consumerProjectID,
+		cachedAt:          now,
+		expiresAt:         now.Add(result.ValidDuration),
+	})
+}
+
+// newCheckCache creates checkCache of up to maxEntries results split evenly among shards, which
+// defaults to 1 if it's not positive and is at most maxEntries. Returns nil if maxEntries is not
+// positive.
+func newCheckCache(maxEntries, shards int, keepExpired bool) *checkCache {
+	if maxEntries <= 0 {
+		return nil
+	}
+	if shards <= 0 {
+		shards = 1
+	} else if shards > maxEntries {
+		shards = maxEntries
+	}
+	c := &checkCache{
+		now:         time.Now,
+		shards:      make([]checkCacheShard, shards),
+		keepExpired: keepExpired,
+	}
+	shardEntries := (maxEntries + shards - 1) / shards
+	for i := range c.shards {
+		// Expired entries are evicted on lookup instead of by an evicter goroutine.
+		c.shards[i] = checkCacheShard{
+			entries:   cache.NewLRU(0, 0, shardEntries),
+			evictions: cache.NewLRU(0, 0, shardEntries),
+		}
+	}
+	return c
+}
@@ -0,0 +1,281 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/pkg/adapter"
+	"istio.io/istio/mixer/pkg/status"
+	"istio.io/istio/mixer/template/apikey"
+)
+
+func TestCheckCache(t *testing.T) {
+	now := time.Now()
+	c := newCheckCache(2, 1, false)
+	c.now = func() time.Time { return now }
+	keyA := checkCacheKey{gcpServiceName, "api_key:key_a", "echo"}
+	keyB := checkCacheKey{gcpServiceName, "api_key:key_b", "echo"}
+	keyC := checkCacheKey{gcpServiceName, "api_key:key_c", "echo"}
+	result := adapter.CheckResult{
+		Status:        status.OK,
+		ValidDuration: 10 * time.Second,
+		ValidUseCount: math.MaxInt32,
+	}
+
+	if _, found := c.get(keyA); found {
+		t.Error(`expect miss of empty cache`)
+	}
+	c.set(keyA, result, "")
+	now = now.Add(4 * time.Second)
+	hits := getHistogramSampleCount(checkCacheHitAge, gcpServiceName)
+	ages := getHistogramSampleSum(checkCacheHitAge, gcpServiceName)
+	cached, found := c.get(keyA)
+	if !found || !status.IsOK(cached.Status) || cached.ValidDuration != 6*time.Second {
+		t.Errorf(`expect cached result valid for 6s, but get %v, %v`, cached, found)
+	}
+	if getHistogramSampleCount(checkCacheHitAge, gcpServiceName) != hits+1 ||
+		getHistogramSampleSum(checkCacheHitAge, gcpServiceName) != ages+4 {
+		t.Errorf(`expect a hit of age 4s to be recorded, but get %v hits of total age %v`,
+			getHistogramSampleCount(checkCacheHitAge, gcpServiceName), getHistogramSampleSum(checkCacheHitAge, gcpServiceName))
+	}
+
+	// Expired entries are evicted on lookup.
+	now = now.Add(6 * time.Second)
+	if _, found := c.get(keyA); found {
+		t.Error(`expect miss of expired entry`)
+	}
+	if _, found := c.shards[0].entries.Get(keyA); found {
+		t.Error(`expect expired entry to be evicted`)
+	}
+
+	// The least recently used entry is evicted once the cache is full.
+	c.set(keyA, result, "")
+	c.set(keyB, result, "")
+	c.get(keyA)
+	c.set(keyC, result, "")
+	if _, found := c.get(keyB); found {
+		t.Error(`expect least recently used entry to be evicted`)
+	}
+	if _, found := c.get(keyA); !found {
+		t.Error(`expect recently used entry to be kept`)
+	}
+
+	if newCheckCache(0, 1, false) != nil {
+		t.Error(`expect nil checkCache when not configured`)
+	}
+}
+
+func TestCheckCacheShards(t *testing.T) {
+	c := newCheckCache(1000, 8, false)
+	result := adapter.CheckResult{
+		Status:        status.OK,
+		ValidDuration: 10 * time.Second,
+		ValidUseCount: math.MaxInt32,
+	}
+	shards := make(map[*checkCacheShard]bool)
+	for i := 0; i < 100; i++ {
+		consumerID := fmt.Sprintf("api_key:key_%d", i)
+		for _, operation := range []string{"echo", "other"} {
+			c.set(checkCacheKey{gcpServiceName, consumerID, operation}, result, "project_number:1")
+		}
+		shards[c.shard(gcpServiceName, consumerID)] = true
+	}
+	if len(shards) != 8 {
+		t.Errorf(`expect consumers to spread over 8 shards, but get %d`, len(shards))
+	}
+
+	// Evicting a consumer evicts all of its operations, and only its.
+	c.evictConsumer(gcpServiceName, "api_key:key_3")
+	for i := 0; i < 100; i++ {
+		consumerID := fmt.Sprintf("api_key:key_%d", i)
+		for _, operation := range []string{"echo", "other"} {
+			key := checkCacheKey{gcpServiceName, consumerID, operation}
+			if _, found := c.get(key); found == (i == 3) {
+				t.Errorf(`expect %v to be found %v`, key, i != 3)
+			}
+			if projectID, found := c.consumerProjectID(key); i != 3 && (!found || projectID != "project_number:1") {
+				t.Errorf(`expect consumer project of %v, but get %q, %v`, key, projectID, found)
+			}
+		}
+	}
+
+	// There are never more shards than entries.
+	if c := newCheckCache(2, 8, false); len(c.shards) != 2 {
+		t.Errorf(`expect 2 shards, but get %d`, len(c.shards))
+	}
+	if c := newCheckCache(10, 0, false); len(c.shards) != 1 {
+		t.Errorf(`expect 1 shard by default, but get %d`, len(c.shards))
+	}
+}
+
+// BenchmarkCheckCache measures lookups of concurrent checks, which contend for the lock of a
+// single shard.
+func BenchmarkCheckCache(b *testing.B) {
+	keys := make([]checkCacheKey, 10000)
+	for i := range keys {
+		keys[i] = checkCacheKey{gcpServiceName, fmt.Sprintf("api_key:key_%d", i), "echo"}
+	}
+	result := adapter.CheckResult{
+		Status:        status.OK,
+		ValidDuration: time.Hour,
+		ValidUseCount: math.MaxInt32,
+	}
+	for _, shards := range []int{1, 16} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			// Shards are filled unevenly, so that the cache is sized to hold every key anyway.
+			c := newCheckCache(2*len(keys), shards, false)
+			var next uint32
+			b.RunParallel(func(pb *testing.PB) {
+				i := int(atomic.AddUint32(&next, 7919))
+				for pb.Next() {
+					key := keys[i%len(keys)]
+					if _, found := c.get(key); !found {
+						c.set(key, result, "")
+					}
+					i++
+				}
+			})
+		})
+	}
+}
+
+func TestProcessCheckCache(t *testing.T) {
+	test := checkProcessorTestSetup(t)
+	now := time.Now()
+	test.checkProc.checkCache = newCheckCache(10, 1, false)
+	test.checkProc.checkCache.now = func() time.Time { return now }
+	test.mockClient.setCheckResponse(&sc.CheckResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 200,
+		},
+	})
+	instance := &apikey.Instance{
+		ApiKey:       "test_key",
+		ApiOperation: "echo",
+		Timestamp:    now,
+	}
+
+	if _, err := test.checkProc.ProcessCheck(context.Background(), instance); err != nil {
+		t.Fatalf(`ProcessCheck() failed with %v`, err)
+	}
+	if test.mockClient.checkRequest == nil {
+		t.Fatal(`expect check request on cache miss`)
+	}
+
+	// Hit.
+	test.mockClient.reset()
+	now = now.Add(time.Second)
+	result, err := test.checkProc.ProcessCheck(context.Background(), instance)
+	if err != nil || !status.IsOK(result.Status) ||
+		result.ValidDuration != test.checkProc.checkResultExpiration-time.Second {
+		t.Errorf(`expect cached result, but get %v, %v`, result, err)
+	}
+	if test.mockClient.checkRequest != nil {
+		t.Errorf(`expect no check request on cache hit, but get %v`, *test.mockClient.checkRequest)
+	}
+
+	// Another operation misses, and its denial is not cached.
+	other := *instance
+	other.ApiOperation = "other"
+	for i := 0; i < 2; i++ {
+		test.mockClient.reset()
+		test.mockClient.setCheckResponse(&sc.CheckResponse{
+			ServerResponse: googleapi.ServerResponse{
+				HTTPStatusCode: 403,
+			},
+		})
+		if result, _ := test.checkProc.ProcessCheck(context.Background(), &other); status.IsOK(result.Status) {
+			t.Errorf(`expect check of other operation to be denied, but get %v`, result)
+		}
+		if test.mockClient.checkRequest == nil {
+			t.Errorf(`expect check request %d of other operation`, i)
+		}
+	}
+
+	// Expired results are checked again.
+	test.mockClient.reset()
+	test.mockClient.setCheckResponse(&sc.CheckResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 200,
+		},
+	})
+	now = now.Add(test.checkProc.checkResultExpiration)
+	result, err = test.checkProc.ProcessCheck(context.Background(), instance)
+	if err != nil || !status.IsOK(result.Status) || result.ValidDuration != test.checkProc.checkResultExpiration {
+		t.Errorf(`expect live check result once cached result expires, but get %v, %v`, result, err)
+	}
+	if test.mockClient.checkRequest == nil {
+		t.Error(`expect check request once cached result expires`)
+	}
+}
+
+func TestProcessCheckCacheEvictsDeniedConsumer(t *testing.T) {
+	test := checkProcessorTestSetup(t)
+	now := time.Now()
+	test.checkProc.checkCache = newCheckCache(10, 1, true)
+	test.checkProc.checkCache.now = func() time.Time { return now }
+	test.mockClient.setCheckResponse(&sc.CheckResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 200,
+		},
+	})
+	echo := &apikey.Instance{
+		ApiKey:       "test_key",
+		ApiOperation: "echo",
+		Timestamp:    now,
+	}
+	other := *echo
+	other.ApiOperation = "other"
+	for _, instance := range []*apikey.Instance{echo, &other} {
+		if _, err := test.checkProc.ProcessCheck(context.Background(), instance); err != nil {
+			t.Fatalf(`ProcessCheck() failed with %v`, err)
+		}
+	}
+
+	// The API key is revoked, and the check of other operation misses the cache once expired.
+	now = now.Add(test.checkProc.checkResultExpiration)
+	test.mockClient.reset()
+	test.mockClient.setCheckResponse(&sc.CheckResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 200,
+		},
+		CheckErrors: []*sc.CheckError{{Code: "API_KEY_INVALID"}},
+	})
+	if result, _ := test.checkProc.ProcessCheck(context.Background(), &other); status.IsOK(result.Status) {
+		t.Fatalf(`expect revoked API key to be denied, but get %v`, result)
+	}
+
+	// Cached allows of the key are evicted, and aren't served even while Google ServiceControl
+	// is unreachable.
+	consumerID := generateConsumerIDFromAPIKey("test_key")
+	if _, found := test.checkProc.checkCache.getExpired(checkCacheKey{gcpServiceName, consumerID, "echo"}); found {
+		t.Error(`expect cached result of denied consumer to be evicted`)
+	}
+	now = now.Add(time.Second)
+	test.checkProc.checkCache.set(checkCacheKey{gcpServiceName, consumerID, "echo"},
+		test.checkProc.checkResult(status.OK), "")
+	if _, found := test.checkProc.checkCache.get(checkCacheKey{gcpServiceName, consumerID, "echo"}); !found {
+		t.Error(`expect results cached after eviction to be kept`)
+	}
+}
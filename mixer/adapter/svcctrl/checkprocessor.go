@@ -31,17 +31,54 @@ import (
 	"istio.io/istio/mixer/template/apikey"
 )
 
+// Check results allowed on transient errors are cached briefly, so that the check is retried soon.
+const transientErrorResultExpiration = time.Second
+
 // checkImpl implements checkProcessor interface, handles doCheck call to Google ServiceControl backend.
 type checkImpl struct {
 	env                   adapter.Env
 	checkResultExpiration time.Duration
-	runtimeConfig         *config.RuntimeConfig
-	serviceConfig         *config.GcpServiceSetting
-	client                serviceControlClient
+	// Expiration of check results which deny the request.
+	negativeResultExpiration time.Duration
+	runtimeConfig            *config.RuntimeConfig
+	serviceConfig            *config.GcpServiceSetting
+	client                   serviceControlClient
+	// Nil when adaptive fail-open is disabled.
+	failOpen *adaptiveFailOpen
+	// Check error codes treated as transient.
+	transientErrors map[string]bool
+	// Check error codes treated as advisory.
+	advisoryErrors map[string]bool
+	// Nil when API key rate limit is disabled.
+	rateLimiter *apiKeyRateLimiter
+	// Nil when per-consumer metrics are disabled.
+	consumerMetrics *consumerMetrics
+	// Nil when consumers are not filtered.
+	consumerFilter *consumerFilter
+	// Nil when check results are not cached.
+	checkCache    *checkCache
+	warningLogger *rateLimitedLogger
 }
 
 // ProcessCheck processes check call and converts CheckResponse to adapter.CheckResult.
 func (c *checkImpl) ProcessCheck(ctx context.Context, instance *apikey.Instance) (adapter.CheckResult, error) {
+	result, err := c.processCheck(instance)
+	if c.rateLimiter != nil && err == nil && status.IsOK(result.Status) {
+		// Mixer serves allows from its own cache for ValidDuration and ValidUseCount, without
+		// calling the adapter, so allows of rate limited API keys are good for one use only.
+		result.ValidUseCount = 1
+	}
+	return result, err
+}
+
+func (c *checkImpl) processCheck(instance *apikey.Instance) (adapter.CheckResult, error) {
+	if instance.ApiKey == "" && instance.ApiOperation != "" {
+		unresolvedConsumerCount.WithLabelValues(c.serviceConfig.GoogleServiceName, methodCheck).Inc()
+		if c.serviceConfig.ConsumerResolutionFailurePolicy != config.DENY {
+			return c.checkResult(status.OK), nil
+		}
+	}
+
 	if instance.ApiKey == "" || instance.ApiOperation == "" {
 		return c.checkResult(
 			status.WithInvalidArgument(
@@ -50,8 +87,49 @@ func (c *checkImpl) ProcessCheck(ctx context.Context, instance *apikey.Instance)
 	}
 
 	consumerID := generateConsumerIDFromAPIKey(instance.ApiKey)
+	if c.consumerFilter != nil {
+		if reason := c.consumerFilter.reject(consumerID); reason != "" {
+			rejectedConsumerCount.WithLabelValues(c.serviceConfig.GoogleServiceName, methodCheck, reason).Inc()
+			return c.checkResult(
+				status.WithPermissionDenied(
+					fmt.Sprintf("instance:%s, consumer is %s to call %s",
+						instance.Name, rejectReasonMessage(reason), c.serviceConfig.GoogleServiceName))), nil
+		}
+	}
+
+	if c.rateLimiter != nil && !c.rateLimiter.allow(instance.ApiKey) {
+		return adapter.CheckResult{
+			Status: status.WithResourceExhausted(
+				fmt.Sprintf("instance:%s, api key exceeds local rate limit", instance.Name)),
+			ValidDuration: rateLimitedResultExpiration,
+			ValidUseCount: math.MaxInt32,
+		}, nil
+	}
+
+	cacheKey := checkCacheKey{c.serviceConfig.GoogleServiceName, consumerID, instance.ApiOperation}
+	if c.checkCache != nil {
+		if result, found := c.checkCache.get(cacheKey); found {
+			c.recordConsumerResult(consumerID, true)
+			return result, nil
+		}
+	}
+
+	if c.failOpen != nil && c.failOpen.shouldFailOpen() {
+		failOpenCount.WithLabelValues(c.serviceConfig.GoogleServiceName, methodCheck).Inc()
+		c.warningLogger.Warningf("fail open check on %s, recent error rate is too high", instance.ApiOperation)
+		return adapter.CheckResult{
+			Status:        status.OK,
+			ValidDuration: failOpenResultExpiration,
+			ValidUseCount: math.MaxInt32,
+		}, nil
+	}
+
 	response, err := c.doCheck(consumerID, instance.ApiOperation, instance.Timestamp)
 	if err != nil {
+		c.recordConsumerResult(consumerID, false)
+		if c.runtimeConfig.NetworkFailPolicy == config.FAIL_OPEN && isUnreachable(err) {
+			return c.networkFailOpenResult(cacheKey, err), nil
+		}
 		return c.checkResult(
 			rpc.Status{
 				Code:    int32(rpc.PERMISSION_DENIED),
@@ -65,22 +143,65 @@ func (c *checkImpl) ProcessCheck(ctx context.Context, instance *apikey.Instance)
 		}
 	}
 
-	return c.responseToCheckResult(response)
+	result, err := c.responseToCheckResult(response)
+	success := err == nil && status.IsOK(result.Status)
+	if c.checkCache != nil {
+		if success {
+			c.checkCache.set(cacheKey, result, consumerProjectID(response))
+		} else if err == nil {
+			// A denied API key may have been revoked, so that allows of its other operations
+			// must not be served from the cache either.
+			c.checkCache.evictConsumer(cacheKey.service, consumerID)
+		}
+	}
+	c.recordConsumerResult(consumerID, success)
+	return result, err
 }
 
-// ResolveConsumerProjectID resolves consumer project ID from consumer ID and operation name.
-func (c *checkImpl) ResolveConsumerProjectID(consumerID, opName string) (string, error) {
-	response, err := c.doCheck(consumerID, opName, time.Now())
-	if err != nil {
-		return "", nil
+// networkFailOpenResult returns the cached result of key, even an expired one, or allows the
+// check if none is cached. The result is only cached for a short time, so that checks resume
+// as soon as Google ServiceControl is reachable.
+func (c *checkImpl) networkFailOpenResult(key checkCacheKey, err error) adapter.CheckResult {
+	failOpenCount.WithLabelValues(c.serviceConfig.GoogleServiceName, methodCheck).Inc()
+	c.warningLogger.Warningf("fail open check on %s, Google ServiceControl is unreachable: %v", key.operation, err)
+	result := c.checkResult(status.OK)
+	if c.checkCache != nil {
+		if cached, found := c.checkCache.getExpired(key); found {
+			result = cached
+		}
 	}
+	result.ValidDuration = failOpenResultExpiration
+	return result
+}
 
-	if response == nil || response.CheckInfo == nil || response.CheckInfo.ConsumerInfo == nil {
-		return "", errors.New("consumer info missing from CheckResponse")
+// recordConsumerResult counts a check result in per-consumer metrics if enabled.
+func (c *checkImpl) recordConsumerResult(consumerID string, success bool) {
+	if c.consumerMetrics != nil {
+		c.consumerMetrics.record(c.serviceConfig.GoogleServiceName, consumerID, methodCheck, success)
 	}
+}
 
-	return fmt.Sprintf("project_number:%d",
-		response.CheckInfo.ConsumerInfo.ProjectNumber), nil
+// ResolveConsumerProjectID resolves consumer project ID from the cached check result of consumer ID
+// and operation name. It never calls Check, so that reports and quotas don't make a Check call of
+// their own, and fails if no check of the consumer and operation is cached.
+func (c *checkImpl) ResolveConsumerProjectID(consumerID, opName string) (string, error) {
+	if c.checkCache == nil {
+		return "", errors.New("check cache is disabled")
+	}
+	projectID, found := c.checkCache.consumerProjectID(
+		checkCacheKey{c.serviceConfig.GoogleServiceName, consumerID, opName})
+	if !found {
+		return "", fmt.Errorf("no cached check result of %s on %s has consumer info", consumerID, opName)
+	}
+	return projectID, nil
+}
+
+// consumerProjectID returns the consumer project of a CheckResponse, empty if it has no consumer info.
+func consumerProjectID(response *sc.CheckResponse) string {
+	if response.CheckInfo == nil || response.CheckInfo.ConsumerInfo == nil {
+		return ""
+	}
+	return fmt.Sprintf("project_number:%d", response.CheckInfo.ConsumerInfo.ProjectNumber)
 }
 
 // doCheck calls Check on Google ServiceControl client.
@@ -93,7 +214,15 @@ func (c *checkImpl) doCheck(consumerID, operationName string, timestamp time.Tim
 			ConsumerId:    consumerID,
 		},
 	}
-	return c.client.Check(c.serviceConfig.GoogleServiceName, request)
+	if retry := c.runtimeConfig.RetryPolicy; c.serviceConfig.EscalateCheckImportance &&
+		retry != nil && retry.MaxAttempts > 1 {
+		request.Operation.Importance = importanceLow
+	}
+	response, err := c.client.Check(c.serviceConfig.GoogleServiceName, request)
+	if c.failOpen != nil {
+		c.failOpen.record(err != nil || response.ServerResponse.HTTPStatusCode >= 500)
+	}
+	return response, err
 }
 
 // responseToCheckResult converts ServiceControl CheckResponse to Mixer CheckerResult
@@ -105,35 +234,107 @@ func (c *checkImpl) responseToCheckResult(response *sc.CheckResponse) (adapter.C
 		result.SetStatus(status.New(code))
 	}
 
-	if len(response.CheckErrors) > 0 {
-		checkError := response.CheckErrors[0]
+	checkErrors := c.blockingErrors(response.CheckErrors)
+	if len(checkErrors) > 0 && c.isTransient(checkErrors) {
+		c.warningLogger.Warningf("allow check with transient errors: %v", checkErrors[0].Code)
+		failOpenCount.WithLabelValues(c.serviceConfig.GoogleServiceName, methodCheck).Inc()
+		result.ValidDuration = transientErrorResultExpiration
+		return result, nil
+	}
+
+	if len(checkErrors) > 0 {
+		checkError := checkErrors[0]
 		result.SetStatus(
 			status.WithMessage(serviceControlErrorToRPCCode(checkError.Code),
 				fmt.Sprintf("%s: %s", checkError.Code, checkError.Detail)))
 	}
 
+	if !status.IsOK(result.Status) {
+		result.ValidDuration = c.negativeResultExpiration
+	}
 	return result, nil
 }
 
-func (c *checkImpl) checkResult(status rpc.Status) adapter.CheckResult {
+// blockingErrors returns check errors except the advisory ones, which are logged and counted.
+func (c *checkImpl) blockingErrors(checkErrors []*sc.CheckError) []*sc.CheckError {
+	if len(c.advisoryErrors) == 0 {
+		return checkErrors
+	}
+	blocking := make([]*sc.CheckError, 0, len(checkErrors))
+	for _, checkError := range checkErrors {
+		if !c.advisoryErrors[checkError.Code] {
+			blocking = append(blocking, checkError)
+			continue
+		}
+		advisoryCheckErrorCount.WithLabelValues(c.serviceConfig.GoogleServiceName, checkError.Code).Inc()
+		c.warningLogger.Warningf("advisory check error %s: %s", checkError.Code, checkError.Detail)
+	}
+	return blocking
+}
+
+// isTransient returns true if all check errors are transient.
+func (c *checkImpl) isTransient(checkErrors []*sc.CheckError) bool {
+	for _, checkError := range checkErrors {
+		if !c.transientErrors[checkError.Code] {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *checkImpl) checkResult(st rpc.Status) adapter.CheckResult {
+	validDuration := c.checkResultExpiration
+	if !status.IsOK(st) {
+		validDuration = c.negativeResultExpiration
+	}
 	return adapter.CheckResult{
-		Status:        status,
-		ValidDuration: c.checkResultExpiration,
+		Status:        st,
+		ValidDuration: validDuration,
 		ValidUseCount: math.MaxInt32,
 	}
 }
 
 func newCheckProcessor(meshServiceName string, ctx *handlerContext) (*checkImpl, error) {
-	serviceConfig, found := ctx.serviceConfigIndex[meshServiceName]
-	if !found {
-		return nil, fmt.Errorf("unknown mesh service %v", meshServiceName)
+	serviceConfig, client, err := ctx.service(meshServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	transientErrors := make(map[string]bool, len(ctx.config.RuntimeConfig.TransientCheckErrors))
+	for _, code := range ctx.config.RuntimeConfig.TransientCheckErrors {
+		transientErrors[code] = true
+	}
+
+	advisoryErrors := make(map[string]bool, len(ctx.config.RuntimeConfig.AdvisoryCheckErrors))
+	for _, code := range ctx.config.RuntimeConfig.AdvisoryCheckErrors {
+		advisoryErrors[code] = true
+	}
+
+	checkResultExpiration := toDuration(ctx.config.RuntimeConfig.CheckResultExpiration)
+	negativeResultExpiration := checkResultExpiration
+	if ctx.config.RuntimeConfig.NegativeCheckResultExpiration != nil {
+		negativeResultExpiration = toDuration(ctx.config.RuntimeConfig.NegativeCheckResultExpiration)
+	}
+
+	consumerFilter, err := newConsumerFilter(serviceConfig)
+	if err != nil {
+		return nil, err
 	}
 
 	return &checkImpl{
 		ctx.env,
-		toDuration(ctx.config.RuntimeConfig.CheckResultExpiration),
+		checkResultExpiration,
+		negativeResultExpiration,
 		ctx.config.RuntimeConfig,
 		serviceConfig,
-		ctx.client,
+		client,
+		newAdaptiveFailOpen(ctx.config.RuntimeConfig.AdaptiveFailOpen),
+		transientErrors,
+		advisoryErrors,
+		newAPIKeyRateLimiter(serviceConfig.ApiKeyRateLimit),
+		ctx.consumerMetrics,
+		consumerFilter,
+		ctx.checkCache,
+		ctx.warningLogger,
 	}, nil
 }
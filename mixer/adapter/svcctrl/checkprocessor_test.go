@@ -47,8 +47,8 @@ type checkProcessorTest struct {
 func checkProcessorTestSetup(t *testing.T) *checkProcessorTest {
 	test := &checkProcessorTest{
 		testConfig: config.Params{
+			// Check results are not cached, so that every check calls the mock client.
 			RuntimeConfig: &config.RuntimeConfig{
-				CheckCacheSize: 10,
 				CheckResultExpiration: &pbtypes.Duration{
 					Seconds: 300,
 				},
@@ -144,8 +144,110 @@ func TestProcessCheckWithError(t *testing.T) {
 	testProcessCheck(test, response, expectedResult, t)
 }
 
+func TestProcessCheckNegativeResultExpiration(t *testing.T) {
+	test := checkProcessorTestSetup(t)
+	test.testConfig.RuntimeConfig.NegativeCheckResultExpiration = &pbtypes.Duration{Seconds: 5}
+	ctx, err := initializeHandlerContext(at.NewEnv(t), &test.testConfig, test.mockClient)
+	if err != nil {
+		t.Fatalf(`fail to initialize handleContext %v`, err)
+	}
+	test.checkProc, err = newCheckProcessor(meshServiceName, ctx)
+	if err != nil {
+		t.Fatalf(`fail to create test checkProcessor %v`, err)
+	}
+
+	// Allowed checks use the positive expiration.
+	response := &sc.CheckResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 200,
+		},
+	}
+	testProcessCheck(test, response, &adapter.CheckResult{
+		Status:        status.OK,
+		ValidDuration: 300 * time.Second,
+		ValidUseCount: math.MaxInt32,
+	}, t)
+
+	// Denials use the negative expiration.
+	response.CheckErrors = []*sc.CheckError{
+		{
+			Code:   "API_KEY_INVALID",
+			Detail: "invalid key",
+		},
+	}
+	testProcessCheck(test, response, &adapter.CheckResult{
+		Status:        status.WithInvalidArgument("API_KEY_INVALID: invalid key"),
+		ValidDuration: 5 * time.Second,
+		ValidUseCount: math.MaxInt32,
+	}, t)
+
+	// Invalid instances are denials too.
+	result, _ := test.checkProc.ProcessCheck(context.Background(), &apikey.Instance{ApiOperation: "/echo"})
+	if status.IsOK(result.Status) || result.ValidDuration != 5*time.Second {
+		t.Errorf(`expect denial cached for 5s, but get %v`, result)
+	}
+}
+
+func TestProcessCheckWithTransientError(t *testing.T) {
+	test := checkProcessorTestSetup(t)
+	test.checkProc.transientErrors = map[string]bool{
+		"NAMESPACE_LOOKUP_UNAVAILABLE": true,
+		"BILLING_STATUS_UNAVAILABLE":   true,
+	}
+	response := &sc.CheckResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 200,
+		},
+		CheckErrors: []*sc.CheckError{
+			{
+				Code:   "NAMESPACE_LOOKUP_UNAVAILABLE",
+				Detail: "namespace lookup unavailable",
+			},
+			{
+				Code:   "BILLING_STATUS_UNAVAILABLE",
+				Detail: "billing status unavailable",
+			},
+		},
+	}
+
+	expectedResult := &adapter.CheckResult{
+		Status:        status.OK,
+		ValidDuration: transientErrorResultExpiration,
+		ValidUseCount: math.MaxInt32,
+	}
+	failOpen := getCounterValue(failOpenCount, gcpServiceName, methodCheck)
+	testProcessCheck(test, response, expectedResult, t)
+	if actual := getCounterValue(failOpenCount, gcpServiceName, methodCheck); actual != failOpen+1 {
+		t.Errorf(`expect %v fail open checks, but get %v`, failOpen+1, actual)
+	}
+
+	// A non-transient error still denies the check.
+	response.CheckErrors = append(response.CheckErrors, &sc.CheckError{
+		Code:   "API_KEY_INVALID",
+		Detail: "invalid key",
+	})
+	expectedResult = &adapter.CheckResult{
+		Status:        status.WithMessage(rpc.UNKNOWN, "NAMESPACE_LOOKUP_UNAVAILABLE: namespace lookup unavailable"),
+		ValidDuration: test.checkProc.checkResultExpiration,
+		ValidUseCount: math.MaxInt32,
+	}
+	testProcessCheck(test, response, expectedResult, t)
+}
+
 func TestResolveConsumerProjectID(t *testing.T) {
 	test := checkProcessorTestSetup(t)
+	consumerID := apiKeyPrefix + "test_key"
+	if _, err := test.checkProc.ResolveConsumerProjectID(consumerID, "/echo"); err == nil {
+		t.Error(`expect resolution to fail without check cache`)
+	}
+
+	test.checkProc.checkCache = newCheckCache(10, 1, false)
+	if _, err := test.checkProc.ResolveConsumerProjectID(consumerID, "/echo"); err == nil {
+		t.Error(`expect resolution to fail without cached check result`)
+	}
+	if test.mockClient.checkRequest != nil {
+		t.Fatalf(`expect no check request on resolution, but get %v`, *test.mockClient.checkRequest)
+	}
 
 	test.mockClient.setCheckResponse(&sc.CheckResponse{
 		ServerResponse: googleapi.ServerResponse{
@@ -157,15 +259,26 @@ func TestResolveConsumerProjectID(t *testing.T) {
 			},
 		},
 	})
+	if _, err := test.checkProc.ProcessCheck(context.Background(), &apikey.Instance{
+		ApiKey:       "test_key",
+		ApiOperation: "/echo",
+		Timestamp:    time.Now(),
+	}); err != nil {
+		t.Fatalf(`ProcessCheck() failed with %v`, err)
+	}
 
-	id, err := test.checkProc.ResolveConsumerProjectID(apiKeyPrefix+"test_key", "/echo")
+	test.mockClient.reset()
+	id, err := test.checkProc.ResolveConsumerProjectID(consumerID, "/echo")
 	if err != nil {
 		t.Errorf("ResolveConsumerProjectID(...) failed with error %v", err)
 	}
-
 	if id != fmt.Sprintf("project_number:%d", gcpConsumerProjectNumber) {
 		t.Errorf(`unexpected consumer project ID:%v`, id)
 	}
+	if test.mockClient.checkRequest != nil {
+		t.Errorf(`expect consumer project to be resolved from cache, but get check request %v`,
+			*test.mockClient.checkRequest)
+	}
 }
 
 func testProcessCheck(test *checkProcessorTest, injectedResponse *sc.CheckResponse,
@@ -183,3 +296,164 @@ func testProcessCheck(test *checkProcessorTest, injectedResponse *sc.CheckRespon
 		t.Errorf(`expect to get result %v, but get %v`, *expectedResult, result)
 	}
 }
+
+func TestProcessCheckEscalateImportance(t *testing.T) {
+	test := checkProcessorTestSetup(t)
+	test.testConfig.ServiceConfigs[0].EscalateCheckImportance = true
+	test.mockClient.setCheckResponse(&sc.CheckResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 200,
+		},
+	})
+	instance := apikey.Instance{
+		ApiOperation: "/echo",
+		ApiKey:       "test_key",
+		Timestamp:    time.Now(),
+	}
+
+	// Checks aren't retried, so that there is no attempt to escalate.
+	if _, err := test.checkProc.ProcessCheck(context.Background(), &instance); err != nil {
+		t.Fatalf(`ProcessCheck() failed with %v`, err)
+	}
+	if importance := test.mockClient.checkRequest.Operation.Importance; importance != "" {
+		t.Errorf(`expect default importance without retries, but get %q`, importance)
+	}
+
+	test.testConfig.RuntimeConfig.RetryPolicy = &config.RetryPolicy{MaxAttempts: 2}
+	if _, err := test.checkProc.ProcessCheck(context.Background(), &instance); err != nil {
+		t.Fatalf(`ProcessCheck() failed with %v`, err)
+	}
+	if importance := test.mockClient.checkRequest.Operation.Importance; importance != importanceLow {
+		t.Errorf(`expect %q importance of the first attempt, but get %q`, importanceLow, importance)
+	}
+}
+
+func TestProcessCheckConsumerFilter(t *testing.T) {
+	test := checkProcessorTestSetup(t)
+	test.checkProc.consumerFilter, _ = newConsumerFilter(&config.GcpServiceSetting{
+		ConsumerDenylist: []string{"api_key:bad_key"},
+	})
+	test.mockClient.setCheckResponse(&sc.CheckResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 200,
+		},
+	})
+
+	count := getCounterValue(rejectedConsumerCount, gcpServiceName, methodCheck, rejectReasonDenied)
+	result, err := test.checkProc.ProcessCheck(context.Background(), &apikey.Instance{
+		ApiKey:       "bad_key",
+		ApiOperation: "echo",
+		Timestamp:    time.Now(),
+	})
+	if err != nil {
+		t.Fatalf(`ProcessCheck() failed with %v`, err)
+	}
+	if result.Status.Code != int32(rpc.PERMISSION_DENIED) {
+		t.Errorf(`expect denied consumer to be rejected, but get %v`, result)
+	}
+	if test.mockClient.checkRequest != nil {
+		t.Errorf(`expect no check request of denied consumer, but get %v`, *test.mockClient.checkRequest)
+	}
+	if actual := getCounterValue(rejectedConsumerCount, gcpServiceName, methodCheck, rejectReasonDenied); actual != count+1 {
+		t.Errorf(`expect %v rejected checks, but get %v`, count+1, actual)
+	}
+
+	result, err = test.checkProc.ProcessCheck(context.Background(), &apikey.Instance{
+		ApiKey:       "test_key",
+		ApiOperation: "echo",
+		Timestamp:    time.Now(),
+	})
+	if err != nil || !status.IsOK(result.Status) || test.mockClient.checkRequest == nil {
+		t.Errorf(`expect consumer not denied to be checked, but get %v, %v`, result, err)
+	}
+}
+
+func TestProcessCheckWithAdvisoryError(t *testing.T) {
+	test := checkProcessorTestSetup(t)
+	test.checkProc.advisoryErrors = map[string]bool{
+		"SERVICE_STATUS_UNAVAILABLE": true,
+	}
+	response := &sc.CheckResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 200,
+		},
+		CheckErrors: []*sc.CheckError{
+			{
+				Code:   "SERVICE_STATUS_UNAVAILABLE",
+				Detail: "service status unavailable",
+			},
+		},
+	}
+
+	count := getCounterValue(advisoryCheckErrorCount, gcpServiceName, "SERVICE_STATUS_UNAVAILABLE")
+	expectedResult := &adapter.CheckResult{
+		Status:        status.OK,
+		ValidDuration: test.checkProc.checkResultExpiration,
+		ValidUseCount: math.MaxInt32,
+	}
+	testProcessCheck(test, response, expectedResult, t)
+	if actual := getCounterValue(advisoryCheckErrorCount, gcpServiceName, "SERVICE_STATUS_UNAVAILABLE"); actual != count+1 {
+		t.Errorf(`expect %v advisory errors, but get %v`, count+1, actual)
+	}
+
+	// A blocking error still denies the check, even if it follows the advisory error.
+	response.CheckErrors = append(response.CheckErrors, &sc.CheckError{
+		Code:   "API_KEY_INVALID",
+		Detail: "invalid key",
+	})
+	expectedResult = &adapter.CheckResult{
+		Status:        status.WithMessage(rpc.INVALID_ARGUMENT, "API_KEY_INVALID: invalid key"),
+		ValidDuration: test.checkProc.checkResultExpiration,
+		ValidUseCount: math.MaxInt32,
+	}
+	testProcessCheck(test, response, expectedResult, t)
+}
+
+func TestProcessCheckNetworkFailPolicy(t *testing.T) {
+	test := checkProcessorTestSetup(t)
+	now := time.Now()
+	test.checkProc.checkCache = newCheckCache(10, 1, true)
+	test.checkProc.checkCache.now = func() time.Time { return now }
+	test.mockClient.setCheckResponse(&sc.CheckResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 200,
+		},
+	})
+	instance := &apikey.Instance{
+		ApiKey:       "test_key",
+		ApiOperation: "echo",
+		Timestamp:    now,
+	}
+	if _, err := test.checkProc.ProcessCheck(context.Background(), instance); err != nil {
+		t.Fatalf(`ProcessCheck() failed with %v`, err)
+	}
+
+	// Checks are denied by default once Google ServiceControl is unreachable.
+	now = now.Add(test.checkProc.checkResultExpiration)
+	test.mockClient.checkError = &googleapi.Error{Code: 503}
+	if result, _ := test.checkProc.ProcessCheck(context.Background(), instance); result.Status.Code !=
+		int32(rpc.PERMISSION_DENIED) {
+		t.Errorf(`expect PERMISSION_DENIED with FAIL_CLOSED, but get %v`, result)
+	}
+
+	test.checkProc.runtimeConfig.NetworkFailPolicy = config.FAIL_OPEN
+	failOpen := getCounterValue(failOpenCount, gcpServiceName, methodCheck)
+	result, err := test.checkProc.ProcessCheck(context.Background(), instance)
+	if err != nil || !status.IsOK(result.Status) || result.ValidDuration != failOpenResultExpiration {
+		t.Errorf(`expect expired cached result to allow the check briefly, but get %v, %v`, result, err)
+	}
+	other := *instance
+	other.ApiKey = "other_key"
+	if result, _ = test.checkProc.ProcessCheck(context.Background(), &other); !status.IsOK(result.Status) {
+		t.Errorf(`expect uncached check to fail open, but get %v`, result)
+	}
+	if actual := getCounterValue(failOpenCount, gcpServiceName, methodCheck); actual != failOpen+2 {
+		t.Errorf(`expect %v fail-open checks, but get %v`, failOpen+2, actual)
+	}
+
+	// Errors other than unreachable Google ServiceControl still deny checks.
+	test.mockClient.checkError = &googleapi.Error{Code: 403}
+	if result, _ = test.checkProc.ProcessCheck(context.Background(), &other); status.IsOK(result.Status) {
+		t.Errorf(`expect check rejected by Google ServiceControl to be denied, but get %v`, result)
+	}
+}
@@ -15,30 +15,120 @@
 package svcctrl
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"golang.org/x/net/context"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
 )
 
+// client calls each Google ServiceControl method on its own endpoint.
 type client struct {
-	serviceControl *sc.Service
+	check  *sc.Service
+	report *sc.Service
+	quota  *sc.Service
+}
+
+// reloadingTokenSource re-reads the credential file periodically and swaps the underlying
+// token source when the credential changes.
+type reloadingTokenSource struct {
+	ctx            context.Context
+	credentialPath string
+	reloadInterval time.Duration
+	logger         *rateLimitedLogger
+	now            func() time.Time
+
+	lock       sync.Mutex // guards fields below
+	lastReload time.Time
+	jsonKey    []byte
+	tokenSrc   oauth2.TokenSource
 }
 
 func (c *client) Check(serviceName string, request *sc.CheckRequest) (*sc.CheckResponse, error) {
-	return c.serviceControl.Services.Check(serviceName, request).Do()
+	return c.check.Services.Check(serviceName, request).Do()
 }
 
 func (c *client) Report(serviceName string, request *sc.ReportRequest) (*sc.ReportResponse, error) {
-	return c.serviceControl.Services.Report(serviceName, request).Do()
+	return c.report.Services.Report(serviceName, request).Do()
+}
+
+func (c *client) AllocateQuota(ctx context.Context, serviceName string,
+	request *sc.AllocateQuotaRequest) (*sc.AllocateQuotaResponse, error) {
+	return c.quota.Services.AllocateQuota(serviceName, request).Context(ctx).Do()
 }
 
-func (c *client) AllocateQuota(serviceName string, request *sc.AllocateQuotaRequest) (*sc.AllocateQuotaResponse, error) {
-	return c.serviceControl.Services.AllocateQuota(serviceName, request).Do()
+// Token returns a token from the token source of current credential.
+func (r *reloadingTokenSource) Token() (*oauth2.Token, error) {
+	return r.current().Token()
+}
+
+// current returns the token source of current credential, reloading the credential
+// file if reload interval has passed.
+func (r *reloadingTokenSource) current() oauth2.TokenSource {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	now := r.now()
+	if now.Sub(r.lastReload) < r.reloadInterval {
+		return r.tokenSrc
+	}
+	r.lastReload = now
+
+	jsonKey, err := getRawTokenBytes(r.credentialPath)
+	if err != nil {
+		r.logger.Warningf("fail to reload credential %s, keep using current credential: %v",
+			r.credentialPath, err)
+		return r.tokenSrc
+	}
+	if bytes.Equal(jsonKey, r.jsonKey) {
+		return r.tokenSrc
+	}
+
+	tokenSrc, err := getTokenSource(r.ctx, jsonKey)
+	if err != nil {
+		r.logger.Warningf("fail to load rotated credential %s, keep using current credential: %v",
+			r.credentialPath, err)
+		return r.tokenSrc
+	}
+	r.logger.Infof("credential %s is rotated", r.credentialPath)
+	r.jsonKey = jsonKey
+	r.tokenSrc = tokenSrc
+	return r.tokenSrc
+}
+
+func newReloadingTokenSource(ctx context.Context, credentialPath string, reloadInterval time.Duration,
+	logger *rateLimitedLogger) (*reloadingTokenSource, error) {
+	jsonKey, err := getRawTokenBytes(credentialPath)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenSrc, err := getTokenSource(ctx, jsonKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reloadingTokenSource{
+		ctx:            ctx,
+		credentialPath: credentialPath,
+		reloadInterval: reloadInterval,
+		logger:         logger,
+		now:            time.Now,
+		lastReload:     time.Now(),
+		jsonKey:        jsonKey,
+		tokenSrc:       tokenSrc,
+	}, nil
 }
 
 func getTokenSource(ctx context.Context, jsonKey []byte) (oauth2.TokenSource, error) {
@@ -54,29 +144,285 @@ func getRawTokenBytes(credential string) ([]byte, error) {
 }
 
 // Creates a service control client. The client is authenticated with service control with Oauth2.
-func newClient(credentialPath string) (serviceControlClient, error) {
-	token, err := getRawTokenBytes(credentialPath)
-	if err != nil {
-		return nil, err
-	}
-
+// When reloadInterval is positive, the credential file is re-read at that interval.
+func newClient(credentialPath string, reloadInterval time.Duration, endpoints *config.ServiceControlEndpoints,
+	logger *rateLimitedLogger) (serviceControlClient, error) {
 	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{
 		Transport: http.DefaultTransport})
 
-	tokenSrc, err := getTokenSource(ctx, token)
+	var httpClient *http.Client
+	if reloadInterval > 0 {
+		tokenSrc, err := newReloadingTokenSource(ctx, credentialPath, reloadInterval, logger)
+		if err != nil {
+			return nil, err
+		}
+		// Token sources of credentials cache tokens themselves, wrapping reloadingTokenSource with
+		// another cache would delay rotation until the cached token expires.
+		httpClient = &http.Client{
+			Transport: &oauth2.Transport{
+				Source: tokenSrc,
+				Base:   http.DefaultTransport,
+			},
+		}
+	} else {
+		token, err := getRawTokenBytes(credentialPath)
+		if err != nil {
+			return nil, err
+		}
+
+		tokenSrc, err := getTokenSource(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+
+		httpClient = oauth2.NewClient(ctx, tokenSrc)
+		if httpClient == nil {
+			return nil, nil
+		}
+	}
+
+	return newServiceControlClient(httpClient, endpoints)
+}
+
+// newDefaultClient creates the handler-level client with the credential of cfg.CredentialMode.
+// Credentials other than key files are refreshed by their token sources, regardless of
+// reloadInterval.
+func newDefaultClient(cfg *config.Params, reloadInterval time.Duration, logger *rateLimitedLogger) (
+	serviceControlClient, error) {
+	var tokenSrc oauth2.TokenSource
+	switch cfg.CredentialMode {
+	case config.APPLICATION_DEFAULT:
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{
+			Transport: http.DefaultTransport})
+		var err error
+		tokenSrc, err = google.DefaultTokenSource(ctx, sc.CloudPlatformScope, sc.ServicecontrolScope)
+		if err != nil {
+			return nil, fmt.Errorf("fail to find application default credentials: %v", err)
+		}
+	case config.METADATA_SERVER:
+		// Tokens of the default service account, whose scopes are granted to the instance.
+		if endpoint := cfg.RuntimeConfig.MetadataServerEndpoint; endpoint != "" {
+			tokenSrc = newMetadataTokenSource(endpoint)
+		} else {
+			tokenSrc = google.ComputeTokenSource("")
+		}
+	default:
+		return newClient(cfg.CredentialPath, reloadInterval, cfg.RuntimeConfig.Endpoints, logger)
+	}
+	return newServiceControlClient(&http.Client{
+		Transport: &oauth2.Transport{
+			Source: oauth2.ReuseTokenSource(nil, tokenSrc),
+			Base:   http.DefaultTransport,
+		},
+	}, cfg.RuntimeConfig.Endpoints)
+}
+
+// Path of default service account tokens on the metadata server.
+const metadataTokenPath = "/computeMetadata/v1/instance/service-accounts/default/token"
+
+// metadataTokenSource fetches tokens of the default service account from the metadata server at
+// endpoint, which google.ComputeTokenSource doesn't allow to configure.
+type metadataTokenSource struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+func (m *metadataTokenSource) Token() (*oauth2.Token, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(m.endpoint, "/")+metadataTokenPath, nil)
 	if err != nil {
 		return nil, err
 	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fail to fetch token from metadata server %s: %v", m.endpoint, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fail to fetch token from metadata server %s: status %d", m.endpoint, resp.StatusCode)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+		TokenType   string `json:"token_type"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("fail to decode token from metadata server %s: %v", m.endpoint, err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("metadata server %s returns empty token", m.endpoint)
+	}
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}, nil
+}
 
-	httpClient := oauth2.NewClient(ctx, tokenSrc)
-	if httpClient == nil {
-		return nil, nil
+func newMetadataTokenSource(endpoint string) *metadataTokenSource {
+	return &metadataTokenSource{
+		endpoint:   endpoint,
+		httpClient: &http.Client{Transport: http.DefaultTransport, Timeout: 5 * time.Second},
 	}
+}
 
-	svcClient, err := sc.New(httpClient)
-	if err != nil {
-		return nil, errors.New("fail to create ServiceControl client")
+// newServiceClients creates the handler-level client with createDefault, which is nil if every
+// service has its own credential path, and a client per distinct credential path of services with
+// create. Clients of services with their own credential path are keyed by mesh service name.
+func newServiceClients(cfg *config.Params, createDefault func() (serviceControlClient, error),
+	create func(credentialPath string) (serviceControlClient, error)) (
+	serviceControlClient, map[string]serviceControlClient, error) {
+	var defaultClient serviceControlClient
+	useDefault := len(cfg.ServiceConfigs) == 0
+	clients := make(map[string]serviceControlClient)
+	for _, setting := range cfg.ServiceConfigs {
+		if setting.CredentialPath == "" ||
+			(cfg.CredentialMode == config.JSON_KEY_FILE && setting.CredentialPath == cfg.CredentialPath) {
+			useDefault = true
+			continue
+		}
+		if _, found := clients[setting.CredentialPath]; found {
+			continue
+		}
+		c, err := create(setting.CredentialPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("fail to create client of credential %s for service %s: %v",
+				setting.CredentialPath, setting.MeshServiceName, err)
+		}
+		clients[setting.CredentialPath] = c
+	}
+	if useDefault {
+		var err error
+		if defaultClient, err = createDefault(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	serviceClients := make(map[string]serviceControlClient)
+	for _, setting := range cfg.ServiceConfigs {
+		if c, found := clients[setting.CredentialPath]; found {
+			serviceClients[setting.MeshServiceName] = c
+		}
+	}
+	return defaultClient, serviceClients, nil
+}
+
+// Methods of ServiceControl URLs, which end with "services/<service name>:<verb>".
+var payloadSizeMethods = map[string]string{
+	"check":         methodCheck,
+	"report":        methodReport,
+	"allocateQuota": methodQuota,
+}
+
+// payloadSizeTransport records sizes in bytes of bodies of ServiceControl requests and responses
+// sent with base. Requests of unknown methods are not recorded.
+type payloadSizeTransport struct {
+	base http.RoundTripper
+}
+
+// payloadMethod returns the google service name and method of a ServiceControl URL path.
+func payloadMethod(path string) (string, string, bool) {
+	i := strings.LastIndex(path, "/services/")
+	if i < 0 {
+		return "", "", false
+	}
+	call := path[i+len("/services/"):]
+	j := strings.LastIndex(call, ":")
+	if j < 0 {
+		return "", "", false
+	}
+	method, found := payloadSizeMethods[call[j+1:]]
+	return call[:j], method, found
+}
+
+func (t *payloadSizeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	service, method, known := payloadMethod(req.URL.Path)
+	if known && req.ContentLength >= 0 {
+		requestSize.WithLabelValues(service, method).Observe(float64(req.ContentLength))
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || !known {
+		return resp, err
+	}
+	if resp.ContentLength >= 0 {
+		responseSize.WithLabelValues(service, method).Observe(float64(resp.ContentLength))
+	} else {
+		// Lengths of chunked or decompressed bodies are only known once they're read.
+		resp.Body = &countingBody{ReadCloser: resp.Body, observer: responseSize.WithLabelValues(service, method)}
+	}
+	return resp, nil
+}
+
+// countingBody records the bytes read from a response body once it's closed.
+type countingBody struct {
+	io.ReadCloser
+	observer interface {
+		Observe(float64)
+	}
+	n int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	if b.observer != nil {
+		b.observer.Observe(float64(b.n))
+		b.observer = nil
+	}
+	return b.ReadCloser.Close()
+}
+
+// withPayloadSizes returns a copy of httpClient recording sizes of ServiceControl payloads.
+func withPayloadSizes(httpClient *http.Client) *http.Client {
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c := *httpClient
+	c.Transport = &payloadSizeTransport{base}
+	return &c
+}
+
+// newServiceControlClient creates a client calling methods on their endpoints with httpClient. Methods
+// on the same endpoint share a ServiceControl service. Sizes of payloads are recorded.
+func newServiceControlClient(httpClient *http.Client, endpoints *config.ServiceControlEndpoints) (*client, error) {
+	if endpoints == nil {
+		endpoints = &config.ServiceControlEndpoints{}
 	}
+	httpClient = withPayloadSizes(httpClient)
 
-	return &client{svcClient}, nil
+	services := make(map[string]*sc.Service)
+	service := func(endpoint string) (*sc.Service, error) {
+		if svc, found := services[endpoint]; found {
+			return svc, nil
+		}
+		svc, err := sc.New(httpClient)
+		if err != nil {
+			return nil, errors.New("fail to create ServiceControl client")
+		}
+		if endpoint != "" {
+			// Methods are resolved relative to the base path, which must end with a slash.
+			svc.BasePath = strings.TrimSuffix(endpoint, "/") + "/"
+		}
+		services[endpoint] = svc
+		return svc, nil
+	}
+
+	check, err := service(endpoints.Check)
+	if err != nil {
+		return nil, err
+	}
+	report, err := service(endpoints.Report)
+	if err != nil {
+		return nil, err
+	}
+	quota, err := service(endpoints.Quota)
+	if err != nil {
+		return nil, err
+	}
+	return &client{check, report, quota}, nil
 }
@@ -9,8 +9,19 @@
 
 	It has these top-level messages:
 		RuntimeConfig
+		RetryPolicy
+		CircuitBreaker
+		ServiceControlEndpoints
+		AdaptiveFailOpen
+		AdaptiveReportThrottling
+		ThrottlingExemption
 		Quota
 		GcpServiceSetting
+		LogEntryExport
+		MetricTransform
+		HeaderLabels
+		ApiKeyRateLimit
+		ConsumerAnonymization
 		Params
 */
 package config
@@ -21,8 +32,13 @@ import math "math"
 import _ "github.com/gogo/protobuf/gogoproto"
 import google_protobuf1 "github.com/gogo/protobuf/types"
 
+import strconv "strconv"
+
+import encoding_binary "encoding/binary"
+
 import strings "strings"
 import reflect "reflect"
+import github_com_gogo_protobuf_sortkeys "github.com/gogo/protobuf/sortkeys"
 
 import io "io"
 
@@ -37,16 +53,384 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
+// Handling of check and quota calls when Google ServiceControl is unreachable.
+type RuntimeConfig_NetworkFailPolicy int32
+
+const (
+	// Calls are denied.
+	FAIL_CLOSED RuntimeConfig_NetworkFailPolicy = 0
+	// Calls are allowed. Checks use cached results, even expired ones, when they're
+	// available. Results allowed by fail-open are only cached for a short time.
+	FAIL_OPEN RuntimeConfig_NetworkFailPolicy = 1
+)
+
+var RuntimeConfig_NetworkFailPolicy_name = map[int32]string{
+	0: "FAIL_CLOSED",
+	1: "FAIL_OPEN",
+}
+var RuntimeConfig_NetworkFailPolicy_value = map[string]int32{
+	"FAIL_CLOSED": 0,
+	"FAIL_OPEN":   1,
+}
+
+func (RuntimeConfig_NetworkFailPolicy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptorConfig, []int{0, 0}
+}
+
+// Modes of allocating quota.
+type Quota_AllocationMode int32
+
+const (
+	// Every quota call allocates quota from Google ServiceControl.
+	PRECISE_REMOTE Quota_AllocationMode = 0
+	// Quota calls are granted from a local token bucket per consumer and operation,
+	// which is refilled in the background by allocating prefetch_amount tokens from
+	// Google ServiceControl. Only a call finding its bucket empty waits for a refill.
+	// Buckets short of quota deny calls until they're refilled on the next reconcile
+	// interval, and prefetched tokens count as used even if they're not.
+	BEST_EFFORT_LOCAL Quota_AllocationMode = 1
+)
+
+var Quota_AllocationMode_name = map[int32]string{
+	0: "PRECISE_REMOTE",
+	1: "BEST_EFFORT_LOCAL",
+}
+var Quota_AllocationMode_value = map[string]int32{
+	"PRECISE_REMOTE":    0,
+	"BEST_EFFORT_LOCAL": 1,
+}
+
+func (Quota_AllocationMode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptorConfig, []int{7, 0}
+}
+
+// Describes how the consumer of an operation is identified.
+type GcpServiceSetting_ConsumerSource int32
+
+const (
+	// Consumer is identified by API key.
+	DEFAULT_CONSUMER GcpServiceSetting_ConsumerSource = 0
+	// Consumer is identified by API key, e.g. "api_key:<key>".
+	API_KEY GcpServiceSetting_ConsumerSource = 1
+	// Consumer is identified by the GCP project which owns the API key,
+	// e.g. "project_number:<number>". The project is read from the cached check result of
+	// the API key and operation, so runtime_config.check_cache_size must be set. When none
+	// is cached, reports fall back to the API key, and quotas follow
+	// consumer_resolution_failure_policy.
+	CONSUMER_PROJECT GcpServiceSetting_ConsumerSource = 2
+	// Consumer is the GCP project peer_identity_projects maps the mesh identity of the
+	// calling workload to, e.g. "project:<id>", since Google ServiceControl doesn't accept
+	// mesh identities as consumers. The identity is read from peer_identity_attribute.
+	// Only applies to quota and report, checks always identify consumer by API key.
+	PEER_IDENTITY GcpServiceSetting_ConsumerSource = 3
+)
+
+var GcpServiceSetting_ConsumerSource_name = map[int32]string{
+	0: "DEFAULT_CONSUMER",
+	1: "API_KEY",
+	2: "CONSUMER_PROJECT",
+	3: "PEER_IDENTITY",
+}
+var GcpServiceSetting_ConsumerSource_value = map[string]int32{
+	"DEFAULT_CONSUMER": 0,
+	"API_KEY":          1,
+	"CONSUMER_PROJECT": 2,
+	"PEER_IDENTITY":    3,
+}
+
+func (GcpServiceSetting_ConsumerSource) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptorConfig, []int{8, 0}
+}
+
+// Describes how requests are handled when no consumer ID can be derived from any
+// consumer source, e.g. the API key and peer identity are both missing.
+type GcpServiceSetting_ConsumerResolutionFailurePolicy int32
+
+const (
+	// Checks and quota calls are denied. Report operations are sent without consumer,
+	// since the usage has already happened.
+	DENY GcpServiceSetting_ConsumerResolutionFailurePolicy = 0
+	// Checks and quota calls are allowed without calling Google ServiceControl. Report
+	// operations are sent without consumer.
+	ANONYMOUS GcpServiceSetting_ConsumerResolutionFailurePolicy = 1
+	// Checks and quota calls are allowed without calling Google ServiceControl. Report
+	// operations are dropped.
+	ALLOW_NO_REPORT GcpServiceSetting_ConsumerResolutionFailurePolicy = 2
+)
+
+var GcpServiceSetting_ConsumerResolutionFailurePolicy_name = map[int32]string{
+	0: "DENY",
+	1: "ANONYMOUS",
+	2: "ALLOW_NO_REPORT",
+}
+var GcpServiceSetting_ConsumerResolutionFailurePolicy_value = map[string]int32{
+	"DENY":            0,
+	"ANONYMOUS":       1,
+	"ALLOW_NO_REPORT": 2,
+}
+
+func (GcpServiceSetting_ConsumerResolutionFailurePolicy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptorConfig, []int{8, 1}
+}
+
+// Sources of the handler-level credential.
+type Params_CredentialMode int32
+
+const (
+	// Credential is read from credential_path.
+	JSON_KEY_FILE Params_CredentialMode = 0
+	// Google Application Default Credentials, e.g. the file named by
+	// GOOGLE_APPLICATION_CREDENTIALS, or the metadata server on GCE and GKE.
+	APPLICATION_DEFAULT Params_CredentialMode = 1
+	// Credential of the service account served by the GCE or GKE metadata server,
+	// e.g. with GKE Workload Identity.
+	METADATA_SERVER Params_CredentialMode = 2
+)
+
+var Params_CredentialMode_name = map[int32]string{
+	0: "JSON_KEY_FILE",
+	1: "APPLICATION_DEFAULT",
+	2: "METADATA_SERVER",
+}
+var Params_CredentialMode_value = map[string]int32{
+	"JSON_KEY_FILE":       0,
+	"APPLICATION_DEFAULT": 1,
+	"METADATA_SERVER":     2,
+}
+
+func (Params_CredentialMode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptorConfig, []int{14, 0}
+}
+
 // Adapter runtime config paramters.
 type RuntimeConfig struct {
-	CheckCacheSize        int32                      `protobuf:"varint,1,opt,name=check_cache_size,json=checkCacheSize,proto3" json:"check_cache_size,omitempty"`
+	// Maximum number of successful check results cached by API key and operation.
+	// Check results are not cached when not set. Consumer projects of reports and quotas are
+	// resolved from cached check results, so they are unknown when not set.
+	CheckCacheSize int32 `protobuf:"varint,1,opt,name=check_cache_size,json=checkCacheSize,proto3" json:"check_cache_size,omitempty"`
+	// Expiration of check results, including cached ones.
 	CheckResultExpiration *google_protobuf1.Duration `protobuf:"bytes,2,opt,name=check_result_expiration,json=checkResultExpiration" json:"check_result_expiration,omitempty"`
+	// Maximum number of warning logs per second emitted by the adapter, excess
+	// warnings are suppressed and summarized. Defaults to 10 when not set.
+	WarningLogRate int32 `protobuf:"varint,3,opt,name=warning_log_rate,json=warningLogRate,proto3" json:"warning_log_rate,omitempty"`
+	// Allows failed checks through when Google ServiceControl is struggling. Disabled
+	// when not set.
+	AdaptiveFailOpen *AdaptiveFailOpen `protobuf:"bytes,4,opt,name=adaptive_fail_open,json=adaptiveFailOpen" json:"adaptive_fail_open,omitempty"`
+	// Backs off report calls when Google ServiceControl throttles them. Disabled when
+	// not set.
+	AdaptiveReportThrottling *AdaptiveReportThrottling `protobuf:"bytes,5,opt,name=adaptive_report_throttling,json=adaptiveReportThrottling" json:"adaptive_report_throttling,omitempty"`
+	// Interval to re-read the credential file, so rotated credentials are picked up
+	// without rebuilding the handler. Credentials are read only once when not set.
+	CredentialReloadInterval *google_protobuf1.Duration `protobuf:"bytes,6,opt,name=credential_reload_interval,json=credentialReloadInterval" json:"credential_reload_interval,omitempty"`
+	// Check error codes treated as transient backend conditions, e.g.
+	// "NAMESPACE_LOOKUP_UNAVAILABLE". A check failing only with transient errors is
+	// allowed, and its result is cached briefly so it's retried soon.
+	TransientCheckErrors []string `protobuf:"bytes,7,rep,name=transient_check_errors,json=transientCheckErrors" json:"transient_check_errors,omitempty"`
+	// Expiration of cached check denials, usually shorter than check_result_expiration
+	// so that newly valid API keys are allowed soon. Denials use
+	// check_result_expiration when not set.
+	NegativeCheckResultExpiration *google_protobuf1.Duration `protobuf:"bytes,8,opt,name=negative_check_result_expiration,json=negativeCheckResultExpiration" json:"negative_check_result_expiration,omitempty"`
+	// Uses the Mixer deduplication id of a quota call as the operation id of the
+	// AllocateQuota call, so that Google ServiceControl deduplicates retries of the same
+	// allocation. Mixer only provides deduplication ids for quota calls, check and report
+	// operations always get generated ids.
+	UseDedupIdAsOperationId bool `protobuf:"varint,9,opt,name=use_dedup_id_as_operation_id,json=useDedupIdAsOperationId,proto3" json:"use_dedup_id_as_operation_id,omitempty"`
+	// Namespace prepended to deduplication ids used as operation ids, e.g. "istio-".
+	// Requires use_dedup_id_as_operation_id.
+	OperationIdNamespace string `protobuf:"bytes,10,opt,name=operation_id_namespace,json=operationIdNamespace,proto3" json:"operation_id_namespace,omitempty"`
+	// Caps the total time of a quota call, including retries of the AllocateQuota call,
+	// which is cancelled once the deadline passes. Must be positive and at most 10s. Not
+	// capped when not set.
+	QuotaDeadline *google_protobuf1.Duration `protobuf:"bytes,11,opt,name=quota_deadline,json=quotaDeadline" json:"quota_deadline,omitempty"`
+	// Grants quota calls exceeding quota_deadline instead of denying them with
+	// DEADLINE_EXCEEDED.
+	QuotaFailOpenOnDeadline bool `protobuf:"varint,12,opt,name=quota_fail_open_on_deadline,json=quotaFailOpenOnDeadline,proto3" json:"quota_fail_open_on_deadline,omitempty"`
+	// Maximum number of consumers whose check and quota results are counted in
+	// per-consumer metrics, for per-consumer error budgets. The consumers with the most
+	// calls are kept, calls of other consumers are counted under the "other" consumer.
+	// Per-consumer metrics are disabled when not set.
+	ConsumerMetricsMaxConsumers int32 `protobuf:"varint,13,opt,name=consumer_metrics_max_consumers,json=consumerMetricsMaxConsumers,proto3" json:"consumer_metrics_max_consumers,omitempty"`
+	// Aligns start times of reported operations and metric values down to multiples of
+	// the alignment, e.g. 60s aligns them to minute boundaries, so that metrics from all
+	// Mixer instances bucket consistently. Must evenly divide an hour. Times are reported
+	// as is when not set.
+	MetricTimeAlignment *google_protobuf1.Duration `protobuf:"bytes,14,opt,name=metric_time_alignment,json=metricTimeAlignment" json:"metric_time_alignment,omitempty"`
+	// Check error codes treated as advisory, e.g. "SERVICE_STATUS_UNAVAILABLE" during a
+	// backend rollout. Advisory errors never deny a check, they're logged as warnings and
+	// counted by code in advisory_check_error_count. A check is denied by the first of its
+	// other errors, if any. Codes denying consumers, e.g. "API_KEY_INVALID" and
+	// "SERVICE_NOT_ACTIVATED", can't be advisory.
+	AdvisoryCheckErrors []string `protobuf:"bytes,15,rep,name=advisory_check_errors,json=advisoryCheckErrors" json:"advisory_check_errors,omitempty"`
+	// Overrides Google ServiceControl endpoints per method, e.g. to send reports to a
+	// local aggregator. Endpoints must be https, except http endpoints of loopback hosts,
+	// since requests carry credentials. Every method calls the default endpoint when not set.
+	Endpoints *ServiceControlEndpoints `protobuf:"bytes,16,opt,name=endpoints" json:"endpoints,omitempty"`
+	// Maximum time closing the handler waits for in-flight requests to finish. Requests
+	// arriving once closing starts are rejected. Defaults to 5s when not set.
+	CloseGracePeriod *google_protobuf1.Duration `protobuf:"bytes,17,opt,name=close_grace_period,json=closeGracePeriod" json:"close_grace_period,omitempty"`
+	// Coalesces concurrent quota allocations of the same consumer, operation and quota
+	// within the window into a single AllocateQuota call of the summed amount, which adds
+	// up to the window to quota latency. Grants are distributed in arrival order. Must be
+	// at most 1s, and can't be used with use_dedup_id_as_operation_id since coalesced
+	// allocations share one operation. Disabled when not set.
+	QuotaCoalescingWindow *google_protobuf1.Duration `protobuf:"bytes,18,opt,name=quota_coalescing_window,json=quotaCoalescingWindow" json:"quota_coalescing_window,omitempty"`
+	// Allows an empty service_configs list, e.g. while services are being onboarded. Until
+	// services are configured, the handler allows every check and quota request, and drops
+	// reports.
+	AllowEmptyServiceConfigs bool `protobuf:"varint,19,opt,name=allow_empty_service_configs,json=allowEmptyServiceConfigs,proto3" json:"allow_empty_service_configs,omitempty"`
+	// Interval of flushing buffered report operations. When set, operations of the same
+	// consumer, operation name and labels are merged, and reported in the background, so
+	// report errors are logged instead of returned. Buffered operations are flushed when
+	// the handler is closed. Operations are reported synchronously when not set.
+	ReportFlushInterval *google_protobuf1.Duration `protobuf:"bytes,20,opt,name=report_flush_interval,json=reportFlushInterval" json:"report_flush_interval,omitempty"`
+	// Maximum number of operations per report call while operations are buffered, buffered
+	// operations are flushed early once this many are buffered. Up to 10 batches are
+	// buffered, operations beyond are dropped. Defaults to 1000 when not set.
+	MaxReportBatchSize int32 `protobuf:"varint,21,opt,name=max_report_batch_size,json=maxReportBatchSize,proto3" json:"max_report_batch_size,omitempty"`
+	// Retries Google ServiceControl calls failing with 5xx or network errors. Calls are
+	// not retried when not set.
+	RetryPolicy *RetryPolicy `protobuf:"bytes,22,opt,name=retry_policy,json=retryPolicy" json:"retry_policy,omitempty"`
+	// Stops calling Google ServiceControl for a while after consecutive calls fail with
+	// 5xx or network errors, so that an outage doesn't add call latency to every request.
+	// Check, report and quota calls have breakers of their own, so that an outage of one
+	// endpoint doesn't stop the others. Disabled when not set.
+	CircuitBreaker *CircuitBreaker `protobuf:"bytes,23,opt,name=circuit_breaker,json=circuitBreaker" json:"circuit_breaker,omitempty"`
+	// Defaults to FAIL_CLOSED.
+	NetworkFailPolicy RuntimeConfig_NetworkFailPolicy `protobuf:"varint,24,opt,name=network_fail_policy,json=networkFailPolicy,proto3,enum=adapter.svcctrl.config.RuntimeConfig_NetworkFailPolicy" json:"network_fail_policy,omitempty"`
+	// Interval to re-read the service_configs_path file of the handler. Services whose
+	// config changed get new processors, others keep theirs along with the check cache.
+	// The file is read only once when not set.
+	ServiceConfigsReloadInterval *google_protobuf1.Duration `protobuf:"bytes,25,opt,name=service_configs_reload_interval,json=serviceConfigsReloadInterval" json:"service_configs_reload_interval,omitempty"`
+	// Sends reports, including exported log entries, to Google ServiceControl. Reports are
+	// dropped when not set.
+	EnableReport bool `protobuf:"varint,26,opt,name=enable_report,json=enableReport,proto3" json:"enable_report,omitempty"`
+	// Allocates quotas of service configs from Google ServiceControl. Every quota is granted
+	// when not set.
+	EnableQuota bool `protobuf:"varint,27,opt,name=enable_quota,json=enableQuota,proto3" json:"enable_quota,omitempty"`
+	// Maximum number of mesh services with processors when requests are routed by mesh
+	// service. Processors of the least recently used services are closed once it's exceeded.
+	// Defaults to 1000 when not set.
+	MaxServiceProcessors int32 `protobuf:"varint,28,opt,name=max_service_processors,json=maxServiceProcessors,proto3" json:"max_service_processors,omitempty"`
+	// Number of workers flushing buffered report operations of all services, when
+	// report_flush_interval is set. Bounds concurrent report calls however many services are
+	// routed. Defaults to 4 when not set.
+	ReportFlushWorkers int32 `protobuf:"varint,29,opt,name=report_flush_workers,json=reportFlushWorkers,proto3" json:"report_flush_workers,omitempty"`
+	// Overrides the metadata server tokens are fetched from when credential_mode is
+	// METADATA_SERVER, e.g. "http://127.0.0.1:8080" for a local metadata emulator. Must be an
+	// http or https URL without a path. Uses the GCE metadata server when not set.
+	MetadataServerEndpoint string `protobuf:"bytes,30,opt,name=metadata_server_endpoint,json=metadataServerEndpoint,proto3" json:"metadata_server_endpoint,omitempty"`
+	// Number of independently locked segments the check cache is split into by consumer, which
+	// reduces lock contention at high QPS. Each segment holds an even share of
+	// check_cache_size, and evicts its least recently used results on its own. Must be at
+	// most check_cache_size. Defaults to 1 when not set.
+	CacheShards int32 `protobuf:"varint,31,opt,name=cache_shards,json=cacheShards,proto3" json:"cache_shards,omitempty"`
+	// Byte budget of report operations buffered per service when report_flush_interval is
+	// set. Buffered operations are flushed once their estimated size exceeds it, regardless
+	// of the interval and max_report_batch_size, and dropped beyond twice the budget, e.g.
+	// when flushes fall behind. Buffered operations are bounded by count only when not set.
+	MaxReportBufferBytes int64 `protobuf:"varint,32,opt,name=max_report_buffer_bytes,json=maxReportBufferBytes,proto3" json:"max_report_buffer_bytes,omitempty"`
 }
 
 func (m *RuntimeConfig) Reset()                    { *m = RuntimeConfig{} }
 func (*RuntimeConfig) ProtoMessage()               {}
 func (*RuntimeConfig) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{0} }
 
+// Retry policy of Google ServiceControl calls. Retries back off exponentially with full
+// jitter, and are bounded by a budget so that retries don't pile onto an overloaded backend.
+type RetryPolicy struct {
+	// Maximum number of attempts of a call including the first one, in [1, 5].
+	MaxAttempts int32 `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	// Backoff before the first retry, doubled for every further retry. Defaults to 100ms
+	// when not set.
+	InitialBackoff *google_protobuf1.Duration `protobuf:"bytes,2,opt,name=initial_backoff,json=initialBackoff" json:"initial_backoff,omitempty"`
+	// Cap of backoffs, at least initial_backoff. Defaults to 1s when not set.
+	MaxBackoff *google_protobuf1.Duration `protobuf:"bytes,3,opt,name=max_backoff,json=maxBackoff" json:"max_backoff,omitempty"`
+	// Ratio in (0, 1] of retries to calls, calls are not retried once retries exceed it
+	// beyond a burst of 10 retries. Defaults to 0.1 when not set.
+	BudgetRatio float64 `protobuf:"fixed64,4,opt,name=budget_ratio,json=budgetRatio,proto3" json:"budget_ratio,omitempty"`
+}
+
+func (m *RetryPolicy) Reset()                    { *m = RetryPolicy{} }
+func (*RetryPolicy) ProtoMessage()               {}
+func (*RetryPolicy) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{1} }
+
+// Circuit breaker of Google ServiceControl calls. Once the breaker opens, calls fail
+// without calling Google ServiceControl until a single call probes whether it recovered.
+type CircuitBreaker struct {
+	// Number of consecutive failed calls which opens the breaker, must be positive.
+	FailureThreshold int32 `protobuf:"varint,1,opt,name=failure_threshold,json=failureThreshold,proto3" json:"failure_threshold,omitempty"`
+	// Time the breaker stays open before a probe, must be positive.
+	OpenDuration *google_protobuf1.Duration `protobuf:"bytes,2,opt,name=open_duration,json=openDuration" json:"open_duration,omitempty"`
+}
+
+func (m *CircuitBreaker) Reset()                    { *m = CircuitBreaker{} }
+func (*CircuitBreaker) ProtoMessage()               {}
+func (*CircuitBreaker) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{2} }
+
+// Endpoints of Google ServiceControl methods, e.g. "https://servicecontrol.googleapis.com/".
+// Methods calling the same endpoint share a client. The default endpoint is called when
+// the endpoint of a method is empty.
+type ServiceControlEndpoints struct {
+	// Endpoint of Check.
+	Check string `protobuf:"bytes,1,opt,name=check,proto3" json:"check,omitempty"`
+	// Endpoint of Report.
+	Report string `protobuf:"bytes,2,opt,name=report,proto3" json:"report,omitempty"`
+	// Endpoint of AllocateQuota.
+	Quota string `protobuf:"bytes,3,opt,name=quota,proto3" json:"quota,omitempty"`
+}
+
+func (m *ServiceControlEndpoints) Reset()                    { *m = ServiceControlEndpoints{} }
+func (*ServiceControlEndpoints) ProtoMessage()               {}
+func (*ServiceControlEndpoints) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{3} }
+
+// Adaptive fail-open policy for check. Once the error rate of recent check calls goes
+// above the threshold, checks are allowed without calling Google ServiceControl with a
+// probability equal to the error rate.
+type AdaptiveFailOpen struct {
+	// Error rate in (0, 1] above which checks start to fail open.
+	ErrorRateThreshold float64 `protobuf:"fixed64,1,opt,name=error_rate_threshold,json=errorRateThreshold,proto3" json:"error_rate_threshold,omitempty"`
+	// Time window over which the error rate is computed.
+	Window *google_protobuf1.Duration `protobuf:"bytes,2,opt,name=window" json:"window,omitempty"`
+}
+
+func (m *AdaptiveFailOpen) Reset()                    { *m = AdaptiveFailOpen{} }
+func (*AdaptiveFailOpen) ProtoMessage()               {}
+func (*AdaptiveFailOpen) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{4} }
+
+// Adaptive throttling policy for report. Every report call throttled by Google
+// ServiceControl, rejected as a whole with HTTP 429 or RESOURCE_EXHAUSTED, cuts the
+// fraction of report calls sent, and every successful call restores part of it. Quota
+// rejections of single operations aren't throttling. Operations of report calls not sent
+// or throttled are deferred to later report calls, and flushed when the handler is
+// closed. Up to 10000 operations are deferred, later ones are dropped.
+type AdaptiveReportThrottling struct {
+	// Factor in (0, 1) the sent fraction is multiplied by on a throttled call.
+	BackoffFactor float64 `protobuf:"fixed64,1,opt,name=backoff_factor,json=backoffFactor,proto3" json:"backoff_factor,omitempty"`
+	// Fraction in (0, 1] added back to the sent fraction on a successful call.
+	RecoveryStep float64 `protobuf:"fixed64,2,opt,name=recovery_step,json=recoveryStep,proto3" json:"recovery_step,omitempty"`
+	// Operations always reported even while throttled, e.g. errors or billing events.
+	Exemptions []*ThrottlingExemption `protobuf:"bytes,3,rep,name=exemptions" json:"exemptions,omitempty"`
+}
+
+func (m *AdaptiveReportThrottling) Reset()                    { *m = AdaptiveReportThrottling{} }
+func (*AdaptiveReportThrottling) ProtoMessage()               {}
+func (*AdaptiveReportThrottling) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{5} }
+
+// Exempts operations matching all the set fields from adaptive report throttling.
+// Patterns must match the whole value.
+type ThrottlingExemption struct {
+	// Pattern of operation names, e.g. "billing.*". Any operation when empty.
+	OperationPattern string `protobuf:"bytes,1,opt,name=operation_pattern,json=operationPattern,proto3" json:"operation_pattern,omitempty"`
+	// Label the operation must carry, e.g. "/error_type". Any label when empty.
+	Label string `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	// Pattern of the label value. Requires label, any value when empty.
+	LabelValuePattern string `protobuf:"bytes,3,opt,name=label_value_pattern,json=labelValuePattern,proto3" json:"label_value_pattern,omitempty"`
+}
+
+func (m *ThrottlingExemption) Reset()                    { *m = ThrottlingExemption{} }
+func (*ThrottlingExemption) ProtoMessage()               {}
+func (*ThrottlingExemption) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{6} }
+
 type Quota struct {
 	// Istio quota name.
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -54,25 +438,223 @@ type Quota struct {
 	GoogleQuotaMetricName string `protobuf:"bytes,2,opt,name=google_quota_metric_name,json=googleQuotaMetricName,proto3" json:"google_quota_metric_name,omitempty"`
 	// Quota token expiration time period.
 	Expiration *google_protobuf1.Duration `protobuf:"bytes,3,opt,name=expiration" json:"expiration,omitempty"`
+	// Overrides of expiration for consumer tiers. The first matching override is used,
+	// expiration applies when none matches.
+	ExpirationOverrides []*Quota_ExpirationOverride `protobuf:"bytes,4,rep,name=expiration_overrides,json=expirationOverrides" json:"expiration_overrides,omitempty"`
+	// Mode of allocating quota, defaults to PRECISE_REMOTE.
+	AllocationMode Quota_AllocationMode `protobuf:"varint,5,opt,name=allocation_mode,json=allocationMode,proto3,enum=adapter.svcctrl.config.Quota_AllocationMode" json:"allocation_mode,omitempty"`
+	// Tokens allocated per refill of a local token bucket in BEST_EFFORT_LOCAL mode. A
+	// refill starts once fewer than half of them are left. Defaults to 100 when not set.
+	PrefetchAmount int64 `protobuf:"varint,6,opt,name=prefetch_amount,json=prefetchAmount,proto3" json:"prefetch_amount,omitempty"`
+	// Interval of refilling local token buckets short of quota in BEST_EFFORT_LOCAL mode,
+	// buckets unused for 60 intervals are dropped. Defaults to 1s when not set.
+	ReconcileInterval *google_protobuf1.Duration `protobuf:"bytes,7,opt,name=reconcile_interval,json=reconcileInterval" json:"reconcile_interval,omitempty"`
 }
 
 func (m *Quota) Reset()                    { *m = Quota{} }
 func (*Quota) ProtoMessage()               {}
-func (*Quota) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{1} }
+func (*Quota) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{7} }
+
+// Quota token expiration for consumers matching a pattern.
+type Quota_ExpirationOverride struct {
+	// Regular expression which must match the whole consumer id, e.g.
+	// "project_number:(123|456)".
+	ConsumerPattern string                     `protobuf:"bytes,1,opt,name=consumer_pattern,json=consumerPattern,proto3" json:"consumer_pattern,omitempty"`
+	Expiration      *google_protobuf1.Duration `protobuf:"bytes,2,opt,name=expiration" json:"expiration,omitempty"`
+}
+
+func (m *Quota_ExpirationOverride) Reset()      { *m = Quota_ExpirationOverride{} }
+func (*Quota_ExpirationOverride) ProtoMessage() {}
+func (*Quota_ExpirationOverride) Descriptor() ([]byte, []int) {
+	return fileDescriptorConfig, []int{7, 0}
+}
 
 // Adapter setting for a managed GCP service.
 type GcpServiceSetting struct {
-	// Local service name on the mesh, which matches destination.service attribute.
+	// Local service name on the mesh, which matches destination.service attribute. It may
+	// be a pattern where each "*" matches a non-empty part of a name without ".", e.g.
+	// "*.payments.svc.cluster.local". Exact names take precedence over patterns, which are
+	// matched in config order.
+	//
+	// Handlers with patterns, or with service_configs_path, route each request to the
+	// mesh service named by its instance: api of apikey, api_service of svcctrlreport, and
+	// the "api_service" dimension of quota or variable of logentry instances.
 	MeshServiceName string `protobuf:"bytes,1,opt,name=mesh_service_name,json=meshServiceName,proto3" json:"mesh_service_name,omitempty"`
-	// Fully qualified GCP service name.
+	// Fully qualified GCP service name. With a mesh_service_name pattern, "{n}" is replaced
+	// with the part of the mesh service name matched by its n-th "*", e.g.
+	// "{1}.payments.example.com".
 	GoogleServiceName string `protobuf:"bytes,2,opt,name=google_service_name,json=googleServiceName,proto3" json:"google_service_name,omitempty"`
 	// Quota configs
 	Quotas []*Quota `protobuf:"bytes,3,rep,name=quotas" json:"quotas,omitempty"`
+	// Labels every report operation must carry, e.g. "/consumer_id". Operations missing
+	// any of them are dropped instead of being sent to Google ServiceControl.
+	RequiredLabels []string `protobuf:"bytes,4,rep,name=required_labels,json=requiredLabels" json:"required_labels,omitempty"`
+	// Consumer to charge quota allocation against. Must be set together with
+	// report_consumer when they differ.
+	QuotaConsumer GcpServiceSetting_ConsumerSource `protobuf:"varint,5,opt,name=quota_consumer,json=quotaConsumer,proto3,enum=adapter.svcctrl.config.GcpServiceSetting_ConsumerSource" json:"quota_consumer,omitempty"`
+	// Consumer to attribute reported usage to. Must be set together with
+	// quota_consumer when they differ.
+	ReportConsumer GcpServiceSetting_ConsumerSource `protobuf:"varint,6,opt,name=report_consumer,json=reportConsumer,proto3,enum=adapter.svcctrl.config.GcpServiceSetting_ConsumerSource" json:"report_consumer,omitempty"`
+	// Hashes API keys in labels and logs of report operations before they leave the
+	// cluster. Consumer ids of operations, and API keys in check and quota operations,
+	// keep the API key, since Google ServiceControl must resolve them. Disabled when not set.
+	ConsumerAnonymization *ConsumerAnonymization `protobuf:"bytes,7,opt,name=consumer_anonymization,json=consumerAnonymization" json:"consumer_anonymization,omitempty"`
+	// Name of the svcctrlreport attribute holding the end state of the request,
+	// "completed" or "aborted" (e.g. the client disconnected). When set, the state is
+	// reported as the "/request_state" label, "unknown" if the attribute is missing.
+	RequestStateAttribute string `protobuf:"bytes,8,opt,name=request_state_attribute,json=requestStateAttribute,proto3" json:"request_state_attribute,omitempty"`
+	// Rate limits checks of each API key locally. Checks of an API key exceeding the
+	// limit are denied without calling Google ServiceControl. Allows are good for one
+	// use, since Mixer would otherwise serve them from its cache without the limit.
+	// Disabled when not set.
+	ApiKeyRateLimit *ApiKeyRateLimit `protobuf:"bytes,9,opt,name=api_key_rate_limit,json=apiKeyRateLimit" json:"api_key_rate_limit,omitempty"`
+	// Name of the attribute holding the mTLS peer identity of the calling workload, e.g.
+	// "source.user". It's read from quota dimensions and svcctrlreport attributes, and must
+	// be set when quota_consumer or report_consumer is PEER_IDENTITY.
+	PeerIdentityAttribute string `protobuf:"bytes,10,opt,name=peer_identity_attribute,json=peerIdentityAttribute,proto3" json:"peer_identity_attribute,omitempty"`
+	// Reports svcctrlreport request headers as operation labels. Disabled when not set.
+	HeaderLabels *HeaderLabels `protobuf:"bytes,11,opt,name=header_labels,json=headerLabels" json:"header_labels,omitempty"`
+	// Name of the attribute marking requests invisible to Google ServiceControl, e.g.
+	// debug probes and synthetic monitors. When its value is true, the request's quota
+	// is granted without calling Google ServiceControl and its report is skipped. It's
+	// read from quota dimensions and svcctrlreport attributes, checks carry no
+	// attributes and are never suppressed. Must name an attribute derived from trusted
+	// sources, e.g. set by the mesh from the workload identity, rather than from request
+	// headers: anyone able to set it gets quota for free and is exempt from billing.
+	SuppressReportAttribute string `protobuf:"bytes,12,opt,name=suppress_report_attribute,json=suppressReportAttribute,proto3" json:"suppress_report_attribute,omitempty"`
+	// Patterns of consumer IDs allowed to call the service, e.g. "project_number:123" or
+	// "api_key:.*". Patterns must match the whole consumer ID. Checks and quota calls of
+	// consumers matching none of them are rejected. Any consumer is allowed when empty.
+	// Checks always match "api_key:<key>", quota calls match the consumer resolved from
+	// quota_consumer.
+	ConsumerAllowlist []string `protobuf:"bytes,13,rep,name=consumer_allowlist,json=consumerAllowlist" json:"consumer_allowlist,omitempty"`
+	// Patterns of consumer IDs denied to call the service. Patterns must match the whole
+	// consumer ID. Denylist takes precedence over consumer_allowlist.
+	ConsumerDenylist []string `protobuf:"bytes,14,rep,name=consumer_denylist,json=consumerDenylist" json:"consumer_denylist,omitempty"`
+	// Map from svcctrlreport response trailer name to the label it's reported as, e.g.
+	// "grpc-status": "/grpc_status", so that gRPC outcomes arriving in trailers are
+	// reported. Trailer names are case insensitive. Labels of missing or empty trailers
+	// are not reported.
+	TrailerLabels map[string]string `protobuf:"bytes,15,rep,name=trailer_labels,json=trailerLabels" json:"trailer_labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Names of the svcctrlreport attributes holding the source and destination workloads
+	// of the request, e.g. "source.name" mapped from the Istio attribute of the same name,
+	// or "source.app" mapped from source.labels["app"]. When set, they're reported as the
+	// "/source_workload" and "/destination_workload" labels, which are omitted if the
+	// attribute is missing or empty.
+	SourceWorkloadAttribute      string `protobuf:"bytes,16,opt,name=source_workload_attribute,json=sourceWorkloadAttribute,proto3" json:"source_workload_attribute,omitempty"`
+	DestinationWorkloadAttribute string `protobuf:"bytes,17,opt,name=destination_workload_attribute,json=destinationWorkloadAttribute,proto3" json:"destination_workload_attribute,omitempty"`
+	// Handling of requests whose consumer can't be resolved. Defaults to DENY.
+	ConsumerResolutionFailurePolicy GcpServiceSetting_ConsumerResolutionFailurePolicy `protobuf:"varint,18,opt,name=consumer_resolution_failure_policy,json=consumerResolutionFailurePolicy,proto3,enum=adapter.svcctrl.config.GcpServiceSetting_ConsumerResolutionFailurePolicy" json:"consumer_resolution_failure_policy,omitempty"`
+	// Transforms of reported metric values, applied in order to the values of each
+	// metric. Only metrics with int64 or double values, e.g. request counts, can be
+	// transformed.
+	MetricTransforms []*MetricTransform `protobuf:"bytes,19,rep,name=metric_transforms,json=metricTransforms" json:"metric_transforms,omitempty"`
+	// A path to JSON token file authenticating calls of this service, overrides the
+	// handler-level credential_path. Services with the same path share a client.
+	CredentialPath string `protobuf:"bytes,20,opt,name=credential_path,json=credentialPath,proto3" json:"credential_path,omitempty"`
+	// Exports of logentry instances as Google ServiceControl log entries, keyed by
+	// Istio logentry instance name. Log entries are reported along with the report
+	// operations of the service, instances without an export are dropped.
+	LogEntryExports map[string]*LogEntryExport `protobuf:"bytes,21,rep,name=log_entry_exports,json=logEntryExports" json:"log_entry_exports,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value"`
+	// GCP project IDs of peer identities, keyed by peer identity, e.g.
+	// "spiffe://cluster.local/ns/default/sa/client": "client-project". Must be non-empty
+	// when quota_consumer or report_consumer is PEER_IDENTITY. Consumers of peer identities
+	// not in the map are unresolved.
+	PeerIdentityProjects map[string]string `protobuf:"bytes,22,rep,name=peer_identity_projects,json=peerIdentityProjects" json:"peer_identity_projects,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Sends checks of this service with LOW importance, and escalates them to HIGH on their
+	// last attempt, which trades accuracy of early attempts for latency while the final answer
+	// is definitive. An attempt is the last once runtime_config.retry_policy allows no more
+	// attempts or its retry budget can't fund a retry, or once its failure would open the
+	// circuit breaker. Has no effect unless retry_policy allows more than one attempt.
+	EscalateCheckImportance bool `protobuf:"varint,23,opt,name=escalate_check_importance,json=escalateCheckImportance,proto3" json:"escalate_check_importance,omitempty"`
 }
 
 func (m *GcpServiceSetting) Reset()                    { *m = GcpServiceSetting{} }
 func (*GcpServiceSetting) ProtoMessage()               {}
-func (*GcpServiceSetting) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{2} }
+func (*GcpServiceSetting) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{8} }
+
+// Export of a logentry instance as a Google ServiceControl log entry. The log entry
+// gets the timestamp and severity of the instance, and variables as its labels and
+// struct payload fields.
+type LogEntryExport struct {
+	// Name of the log, which must be declared in the service config of the Google
+	// service, e.g. "access_log". Must not be empty.
+	LogName string `protobuf:"bytes,1,opt,name=log_name,json=logName,proto3" json:"log_name,omitempty"`
+	// Variables reported as labels of the log entry, other variables are reported as
+	// fields of its struct payload.
+	LabelVariables []string `protobuf:"bytes,2,rep,name=label_variables,json=labelVariables" json:"label_variables,omitempty"`
+	// Variable of the API key identifying the consumer of the log entry. Log entries
+	// are reported without a consumer when not set.
+	ApiKeyVariable string `protobuf:"bytes,3,opt,name=api_key_variable,json=apiKeyVariable,proto3" json:"api_key_variable,omitempty"`
+	// Variable of the operation name of the log entry. Defaults to log_name when not set.
+	ApiOperationVariable string `protobuf:"bytes,4,opt,name=api_operation_variable,json=apiOperationVariable,proto3" json:"api_operation_variable,omitempty"`
+}
+
+func (m *LogEntryExport) Reset()                    { *m = LogEntryExport{} }
+func (*LogEntryExport) ProtoMessage()               {}
+func (*LogEntryExport) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{9} }
+
+// Transform of the values of a reported metric.
+type MetricTransform struct {
+	// Name of the metric, e.g. "serviceruntime.googleapis.com/api/producer/request_count".
+	MetricName string `protobuf:"bytes,1,opt,name=metric_name,json=metricName,proto3" json:"metric_name,omitempty"`
+	// Name of the transformer, one of the built-in "scale", "clamp" and "bucket", or a
+	// custom transformer registered with the adapter.
+	Transformer string `protobuf:"bytes,2,opt,name=transformer,proto3" json:"transformer,omitempty"`
+	// Positive factor "scale" multiplies values by, e.g. 0.0009765625 for bytes to KiB.
+	Factor float64 `protobuf:"fixed64,3,opt,name=factor,proto3" json:"factor,omitempty"`
+	// Bounds "clamp" limits values to, min must not be above max.
+	Min float64 `protobuf:"fixed64,4,opt,name=min,proto3" json:"min,omitempty"`
+	Max float64 `protobuf:"fixed64,5,opt,name=max,proto3" json:"max,omitempty"`
+	// Strictly ascending boundaries of "bucket", which maps values to the largest
+	// boundary not above them, or the first boundary for values below all boundaries.
+	Boundaries []float64 `protobuf:"fixed64,6,rep,packed,name=boundaries" json:"boundaries,omitempty"`
+}
+
+func (m *MetricTransform) Reset()                    { *m = MetricTransform{} }
+func (*MetricTransform) ProtoMessage()               {}
+func (*MetricTransform) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{10} }
+
+// Mapping of request headers to operation labels. Only headers in the allowlist are
+// ever reported, so that sensitive headers like "authorization" don't leak.
+type HeaderLabels struct {
+	// Header names allowed to be reported, case insensitive. Must not be empty.
+	AllowedHeaders []string `protobuf:"bytes,1,rep,name=allowed_headers,json=allowedHeaders" json:"allowed_headers,omitempty"`
+	// Map from header name to the label it's reported as, e.g.
+	// "user-agent": "/user_agent". Headers not in allowed_headers are ignored, labels of
+	// missing or empty headers are not reported.
+	Labels map[string]string `protobuf:"bytes,2,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *HeaderLabels) Reset()                    { *m = HeaderLabels{} }
+func (*HeaderLabels) ProtoMessage()               {}
+func (*HeaderLabels) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{11} }
+
+// Per API key rate limit of checks, enforced with a token bucket per API key.
+type ApiKeyRateLimit struct {
+	// Sustained checks allowed per second for each API key, must be positive.
+	RequestsPerSecond float64 `protobuf:"fixed64,1,opt,name=requests_per_second,json=requestsPerSecond,proto3" json:"requests_per_second,omitempty"`
+	// Checks of an API key allowed in a burst, must be positive.
+	Burst int32 `protobuf:"varint,2,opt,name=burst,proto3" json:"burst,omitempty"`
+	// Maximum number of API keys tracked, must be positive. When exceeded, the least
+	// recently seen API keys are forgotten and start again with a full bucket.
+	MaxTrackedKeys int32 `protobuf:"varint,3,opt,name=max_tracked_keys,json=maxTrackedKeys,proto3" json:"max_tracked_keys,omitempty"`
+}
+
+func (m *ApiKeyRateLimit) Reset()                    { *m = ApiKeyRateLimit{} }
+func (*ApiKeyRateLimit) ProtoMessage()               {}
+func (*ApiKeyRateLimit) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{12} }
+
+// Anonymization of consumer identifiers. The same API key is always hashed to the
+// same value, so reported usage of a consumer can still be aggregated.
+type ConsumerAnonymization struct {
+	// Salt of the hash, must not be empty.
+	Salt string `protobuf:"bytes,1,opt,name=salt,proto3" json:"salt,omitempty"`
+}
+
+func (m *ConsumerAnonymization) Reset()                    { *m = ConsumerAnonymization{} }
+func (*ConsumerAnonymization) ProtoMessage()               {}
+func (*ConsumerAnonymization) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{13} }
 
 // Sample adapter config:
 // '''
@@ -85,6 +667,8 @@ func (*GcpServiceSetting) Descriptor() ([]byte, []int) { return fileDescriptorCo
 //   runtime_config:
 //     check_cache_size: 200
 //     check_result_expiration: 60s
+//     enable_report: true
+//     enable_quota: true
 //   credential_path: "/path/to/token.json"
 //   service_configs:
 //     - mesh_service_name: "echo.local.svc"
@@ -99,17 +683,78 @@ type Params struct {
 	// A path to JSON token file, usually mounted as Kubernetes secret on pod.
 	CredentialPath string               `protobuf:"bytes,2,opt,name=credential_path,json=credentialPath,proto3" json:"credential_path,omitempty"`
 	ServiceConfigs []*GcpServiceSetting `protobuf:"bytes,3,rep,name=service_configs,json=serviceConfigs" json:"service_configs,omitempty"`
+	// Source of the handler-level credential, credential_path must not be set unless it's
+	// JSON_KEY_FILE, and runtime_config.metadata_server_endpoint unless it's METADATA_SERVER.
+	// Services with their own credential_path still use key files. Defaults to JSON_KEY_FILE.
+	CredentialMode Params_CredentialMode `protobuf:"varint,4,opt,name=credential_mode,json=credentialMode,proto3,enum=adapter.svcctrl.config.Params_CredentialMode" json:"credential_mode,omitempty"`
+	// A path to a JSON file of service configs, e.g. {"serviceConfigs": [...]}, usually
+	// mounted from a Kubernetes ConfigMap. Only service configs of the file are used, and
+	// service_configs must not be set along with it. Reloaded services may only use
+	// credential paths of services loaded when the handler was built.
+	ServiceConfigsPath string `protobuf:"bytes,5,opt,name=service_configs_path,json=serviceConfigsPath,proto3" json:"service_configs_path,omitempty"`
 }
 
 func (m *Params) Reset()                    { *m = Params{} }
 func (*Params) ProtoMessage()               {}
-func (*Params) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{3} }
+func (*Params) Descriptor() ([]byte, []int) { return fileDescriptorConfig, []int{14} }
 
 func init() {
 	proto.RegisterType((*RuntimeConfig)(nil), "adapter.svcctrl.config.RuntimeConfig")
+	proto.RegisterType((*RetryPolicy)(nil), "adapter.svcctrl.config.RetryPolicy")
+	proto.RegisterType((*CircuitBreaker)(nil), "adapter.svcctrl.config.CircuitBreaker")
+	proto.RegisterType((*ServiceControlEndpoints)(nil), "adapter.svcctrl.config.ServiceControlEndpoints")
+	proto.RegisterType((*AdaptiveFailOpen)(nil), "adapter.svcctrl.config.AdaptiveFailOpen")
+	proto.RegisterType((*AdaptiveReportThrottling)(nil), "adapter.svcctrl.config.AdaptiveReportThrottling")
+	proto.RegisterType((*ThrottlingExemption)(nil), "adapter.svcctrl.config.ThrottlingExemption")
 	proto.RegisterType((*Quota)(nil), "adapter.svcctrl.config.Quota")
+	proto.RegisterType((*Quota_ExpirationOverride)(nil), "adapter.svcctrl.config.Quota.ExpirationOverride")
 	proto.RegisterType((*GcpServiceSetting)(nil), "adapter.svcctrl.config.GcpServiceSetting")
+	proto.RegisterType((*LogEntryExport)(nil), "adapter.svcctrl.config.LogEntryExport")
+	proto.RegisterType((*MetricTransform)(nil), "adapter.svcctrl.config.MetricTransform")
+	proto.RegisterType((*HeaderLabels)(nil), "adapter.svcctrl.config.HeaderLabels")
+	proto.RegisterType((*ApiKeyRateLimit)(nil), "adapter.svcctrl.config.ApiKeyRateLimit")
+	proto.RegisterType((*ConsumerAnonymization)(nil), "adapter.svcctrl.config.ConsumerAnonymization")
 	proto.RegisterType((*Params)(nil), "adapter.svcctrl.config.Params")
+	proto.RegisterEnum("adapter.svcctrl.config.RuntimeConfig_NetworkFailPolicy", RuntimeConfig_NetworkFailPolicy_name, RuntimeConfig_NetworkFailPolicy_value)
+	proto.RegisterEnum("adapter.svcctrl.config.Quota_AllocationMode", Quota_AllocationMode_name, Quota_AllocationMode_value)
+	proto.RegisterEnum("adapter.svcctrl.config.GcpServiceSetting_ConsumerSource", GcpServiceSetting_ConsumerSource_name, GcpServiceSetting_ConsumerSource_value)
+	proto.RegisterEnum("adapter.svcctrl.config.GcpServiceSetting_ConsumerResolutionFailurePolicy", GcpServiceSetting_ConsumerResolutionFailurePolicy_name, GcpServiceSetting_ConsumerResolutionFailurePolicy_value)
+	proto.RegisterEnum("adapter.svcctrl.config.Params_CredentialMode", Params_CredentialMode_name, Params_CredentialMode_value)
+}
+func (x RuntimeConfig_NetworkFailPolicy) String() string {
+	s, ok := RuntimeConfig_NetworkFailPolicy_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (x Quota_AllocationMode) String() string {
+	s, ok := Quota_AllocationMode_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (x GcpServiceSetting_ConsumerSource) String() string {
+	s, ok := GcpServiceSetting_ConsumerSource_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (x GcpServiceSetting_ConsumerResolutionFailurePolicy) String() string {
+	s, ok := GcpServiceSetting_ConsumerResolutionFailurePolicy_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (x Params_CredentialMode) String() string {
+	s, ok := Params_CredentialMode_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
 }
 func (m *RuntimeConfig) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -141,10 +786,306 @@ func (m *RuntimeConfig) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n1
 	}
+	if m.WarningLogRate != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.WarningLogRate))
+	}
+	if m.AdaptiveFailOpen != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.AdaptiveFailOpen.Size()))
+		n2, err := m.AdaptiveFailOpen.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if m.AdaptiveReportThrottling != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.AdaptiveReportThrottling.Size()))
+		n3, err := m.AdaptiveReportThrottling.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	if m.CredentialReloadInterval != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.CredentialReloadInterval.Size()))
+		n4, err := m.CredentialReloadInterval.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if len(m.TransientCheckErrors) > 0 {
+		for _, s := range m.TransientCheckErrors {
+			dAtA[i] = 0x3a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.NegativeCheckResultExpiration != nil {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.NegativeCheckResultExpiration.Size()))
+		n5, err := m.NegativeCheckResultExpiration.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	if m.UseDedupIdAsOperationId {
+		dAtA[i] = 0x48
+		i++
+		if m.UseDedupIdAsOperationId {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if len(m.OperationIdNamespace) > 0 {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.OperationIdNamespace)))
+		i += copy(dAtA[i:], m.OperationIdNamespace)
+	}
+	if m.QuotaDeadline != nil {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.QuotaDeadline.Size()))
+		n6, err := m.QuotaDeadline.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n6
+	}
+	if m.QuotaFailOpenOnDeadline {
+		dAtA[i] = 0x60
+		i++
+		if m.QuotaFailOpenOnDeadline {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.ConsumerMetricsMaxConsumers != 0 {
+		dAtA[i] = 0x68
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.ConsumerMetricsMaxConsumers))
+	}
+	if m.MetricTimeAlignment != nil {
+		dAtA[i] = 0x72
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.MetricTimeAlignment.Size()))
+		n7, err := m.MetricTimeAlignment.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n7
+	}
+	if len(m.AdvisoryCheckErrors) > 0 {
+		for _, s := range m.AdvisoryCheckErrors {
+			dAtA[i] = 0x7a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.Endpoints != nil {
+		dAtA[i] = 0x82
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Endpoints.Size()))
+		n8, err := m.Endpoints.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
+	if m.CloseGracePeriod != nil {
+		dAtA[i] = 0x8a
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.CloseGracePeriod.Size()))
+		n9, err := m.CloseGracePeriod.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n9
+	}
+	if m.QuotaCoalescingWindow != nil {
+		dAtA[i] = 0x92
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.QuotaCoalescingWindow.Size()))
+		n10, err := m.QuotaCoalescingWindow.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
+	}
+	if m.AllowEmptyServiceConfigs {
+		dAtA[i] = 0x98
+		i++
+		dAtA[i] = 0x1
+		i++
+		if m.AllowEmptyServiceConfigs {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.ReportFlushInterval != nil {
+		dAtA[i] = 0xa2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.ReportFlushInterval.Size()))
+		n11, err := m.ReportFlushInterval.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
+	}
+	if m.MaxReportBatchSize != 0 {
+		dAtA[i] = 0xa8
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxReportBatchSize))
+	}
+	if m.RetryPolicy != nil {
+		dAtA[i] = 0xb2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.RetryPolicy.Size()))
+		n12, err := m.RetryPolicy.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n12
+	}
+	if m.CircuitBreaker != nil {
+		dAtA[i] = 0xba
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.CircuitBreaker.Size()))
+		n13, err := m.CircuitBreaker.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n13
+	}
+	if m.NetworkFailPolicy != 0 {
+		dAtA[i] = 0xc0
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.NetworkFailPolicy))
+	}
+	if m.ServiceConfigsReloadInterval != nil {
+		dAtA[i] = 0xca
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.ServiceConfigsReloadInterval.Size()))
+		n14, err := m.ServiceConfigsReloadInterval.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n14
+	}
+	if m.EnableReport {
+		dAtA[i] = 0xd0
+		i++
+		dAtA[i] = 0x1
+		i++
+		if m.EnableReport {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.EnableQuota {
+		dAtA[i] = 0xd8
+		i++
+		dAtA[i] = 0x1
+		i++
+		if m.EnableQuota {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.MaxServiceProcessors != 0 {
+		dAtA[i] = 0xe0
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxServiceProcessors))
+	}
+	if m.ReportFlushWorkers != 0 {
+		dAtA[i] = 0xe8
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.ReportFlushWorkers))
+	}
+	if len(m.MetadataServerEndpoint) > 0 {
+		dAtA[i] = 0xf2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.MetadataServerEndpoint)))
+		i += copy(dAtA[i:], m.MetadataServerEndpoint)
+	}
+	if m.CacheShards != 0 {
+		dAtA[i] = 0xf8
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.CacheShards))
+	}
+	if m.MaxReportBufferBytes != 0 {
+		dAtA[i] = 0x80
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxReportBufferBytes))
+	}
 	return i, nil
 }
 
-func (m *Quota) Marshal() (dAtA []byte, err error) {
+func (m *RetryPolicy) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -154,37 +1095,46 @@ func (m *Quota) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Quota) MarshalTo(dAtA []byte) (int, error) {
+func (m *RetryPolicy) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
-	if len(m.Name) > 0 {
-		dAtA[i] = 0xa
+	if m.MaxAttempts != 0 {
+		dAtA[i] = 0x8
 		i++
-		i = encodeVarintConfig(dAtA, i, uint64(len(m.Name)))
-		i += copy(dAtA[i:], m.Name)
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxAttempts))
 	}
-	if len(m.GoogleQuotaMetricName) > 0 {
+	if m.InitialBackoff != nil {
 		dAtA[i] = 0x12
 		i++
-		i = encodeVarintConfig(dAtA, i, uint64(len(m.GoogleQuotaMetricName)))
-		i += copy(dAtA[i:], m.GoogleQuotaMetricName)
+		i = encodeVarintConfig(dAtA, i, uint64(m.InitialBackoff.Size()))
+		n15, err := m.InitialBackoff.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n15
 	}
-	if m.Expiration != nil {
+	if m.MaxBackoff != nil {
 		dAtA[i] = 0x1a
 		i++
-		i = encodeVarintConfig(dAtA, i, uint64(m.Expiration.Size()))
-		n2, err := m.Expiration.MarshalTo(dAtA[i:])
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxBackoff.Size()))
+		n16, err := m.MaxBackoff.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n2
+		i += n16
+	}
+	if m.BudgetRatio != 0 {
+		dAtA[i] = 0x21
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.BudgetRatio))))
+		i += 8
 	}
 	return i, nil
 }
 
-func (m *GcpServiceSetting) Marshal() (dAtA []byte, err error) {
+func (m *CircuitBreaker) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -194,39 +1144,30 @@ func (m *GcpServiceSetting) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *GcpServiceSetting) MarshalTo(dAtA []byte) (int, error) {
+func (m *CircuitBreaker) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
-	if len(m.MeshServiceName) > 0 {
-		dAtA[i] = 0xa
+	if m.FailureThreshold != 0 {
+		dAtA[i] = 0x8
 		i++
-		i = encodeVarintConfig(dAtA, i, uint64(len(m.MeshServiceName)))
-		i += copy(dAtA[i:], m.MeshServiceName)
+		i = encodeVarintConfig(dAtA, i, uint64(m.FailureThreshold))
 	}
-	if len(m.GoogleServiceName) > 0 {
+	if m.OpenDuration != nil {
 		dAtA[i] = 0x12
 		i++
-		i = encodeVarintConfig(dAtA, i, uint64(len(m.GoogleServiceName)))
-		i += copy(dAtA[i:], m.GoogleServiceName)
-	}
-	if len(m.Quotas) > 0 {
-		for _, msg := range m.Quotas {
-			dAtA[i] = 0x1a
-			i++
-			i = encodeVarintConfig(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
-		}
+		i = encodeVarintConfig(dAtA, i, uint64(m.OpenDuration.Size()))
+		n17, err := m.OpenDuration.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n17
 	}
 	return i, nil
 }
 
-func (m *Params) Marshal() (dAtA []byte, err error) {
+func (m *ServiceControlEndpoints) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -236,29 +1177,95 @@ func (m *Params) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Params) MarshalTo(dAtA []byte) (int, error) {
+func (m *ServiceControlEndpoints) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
-	if m.RuntimeConfig != nil {
+	if len(m.Check) > 0 {
 		dAtA[i] = 0xa
 		i++
-		i = encodeVarintConfig(dAtA, i, uint64(m.RuntimeConfig.Size()))
-		n3, err := m.RuntimeConfig.MarshalTo(dAtA[i:])
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Check)))
+		i += copy(dAtA[i:], m.Check)
+	}
+	if len(m.Report) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Report)))
+		i += copy(dAtA[i:], m.Report)
+	}
+	if len(m.Quota) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Quota)))
+		i += copy(dAtA[i:], m.Quota)
+	}
+	return i, nil
+}
+
+func (m *AdaptiveFailOpen) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AdaptiveFailOpen) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.ErrorRateThreshold != 0 {
+		dAtA[i] = 0x9
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.ErrorRateThreshold))))
+		i += 8
+	}
+	if m.Window != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Window.Size()))
+		n18, err := m.Window.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n3
+		i += n18
 	}
-	if len(m.CredentialPath) > 0 {
-		dAtA[i] = 0x12
+	return i, nil
+}
+
+func (m *AdaptiveReportThrottling) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AdaptiveReportThrottling) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.BackoffFactor != 0 {
+		dAtA[i] = 0x9
 		i++
-		i = encodeVarintConfig(dAtA, i, uint64(len(m.CredentialPath)))
-		i += copy(dAtA[i:], m.CredentialPath)
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.BackoffFactor))))
+		i += 8
 	}
-	if len(m.ServiceConfigs) > 0 {
-		for _, msg := range m.ServiceConfigs {
+	if m.RecoveryStep != 0 {
+		dAtA[i] = 0x11
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.RecoveryStep))))
+		i += 8
+	}
+	if len(m.Exemptions) > 0 {
+		for _, msg := range m.Exemptions {
 			dAtA[i] = 0x1a
 			i++
 			i = encodeVarintConfig(dAtA, i, uint64(msg.Size()))
@@ -272,155 +1279,4627 @@ func (m *Params) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
-func encodeVarintConfig(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *ThrottlingExemption) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return dAtA[:n], nil
 }
-func (m *RuntimeConfig) Size() (n int) {
+
+func (m *ThrottlingExemption) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if m.CheckCacheSize != 0 {
-		n += 1 + sovConfig(uint64(m.CheckCacheSize))
+	if len(m.OperationPattern) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.OperationPattern)))
+		i += copy(dAtA[i:], m.OperationPattern)
 	}
-	if m.CheckResultExpiration != nil {
-		l = m.CheckResultExpiration.Size()
-		n += 1 + l + sovConfig(uint64(l))
+	if len(m.Label) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Label)))
+		i += copy(dAtA[i:], m.Label)
 	}
-	return n
+	if len(m.LabelValuePattern) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.LabelValuePattern)))
+		i += copy(dAtA[i:], m.LabelValuePattern)
+	}
+	return i, nil
 }
 
-func (m *Quota) Size() (n int) {
+func (m *Quota) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Quota) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovConfig(uint64(l))
+	if len(m.Name) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
 	}
-	l = len(m.GoogleQuotaMetricName)
-	if l > 0 {
-		n += 1 + l + sovConfig(uint64(l))
+	if len(m.GoogleQuotaMetricName) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.GoogleQuotaMetricName)))
+		i += copy(dAtA[i:], m.GoogleQuotaMetricName)
 	}
 	if m.Expiration != nil {
-		l = m.Expiration.Size()
-		n += 1 + l + sovConfig(uint64(l))
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Expiration.Size()))
+		n19, err := m.Expiration.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n19
 	}
-	return n
+	if len(m.ExpirationOverrides) > 0 {
+		for _, msg := range m.ExpirationOverrides {
+			dAtA[i] = 0x22
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.AllocationMode != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.AllocationMode))
+	}
+	if m.PrefetchAmount != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.PrefetchAmount))
+	}
+	if m.ReconcileInterval != nil {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.ReconcileInterval.Size()))
+		n20, err := m.ReconcileInterval.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n20
+	}
+	return i, nil
 }
 
-func (m *GcpServiceSetting) Size() (n int) {
+func (m *Quota_ExpirationOverride) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Quota_ExpirationOverride) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.MeshServiceName)
-	if l > 0 {
-		n += 1 + l + sovConfig(uint64(l))
-	}
-	l = len(m.GoogleServiceName)
-	if l > 0 {
-		n += 1 + l + sovConfig(uint64(l))
+	if len(m.ConsumerPattern) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.ConsumerPattern)))
+		i += copy(dAtA[i:], m.ConsumerPattern)
 	}
-	if len(m.Quotas) > 0 {
-		for _, e := range m.Quotas {
-			l = e.Size()
-			n += 1 + l + sovConfig(uint64(l))
+	if m.Expiration != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Expiration.Size()))
+		n21, err := m.Expiration.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
 		}
+		i += n21
 	}
-	return n
+	return i, nil
 }
 
-func (m *Params) Size() (n int) {
+func (m *GcpServiceSetting) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GcpServiceSetting) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if m.RuntimeConfig != nil {
-		l = m.RuntimeConfig.Size()
-		n += 1 + l + sovConfig(uint64(l))
+	if len(m.MeshServiceName) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.MeshServiceName)))
+		i += copy(dAtA[i:], m.MeshServiceName)
 	}
-	l = len(m.CredentialPath)
-	if l > 0 {
-		n += 1 + l + sovConfig(uint64(l))
+	if len(m.GoogleServiceName) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.GoogleServiceName)))
+		i += copy(dAtA[i:], m.GoogleServiceName)
 	}
-	if len(m.ServiceConfigs) > 0 {
-		for _, e := range m.ServiceConfigs {
-			l = e.Size()
-			n += 1 + l + sovConfig(uint64(l))
+	if len(m.Quotas) > 0 {
+		for _, msg := range m.Quotas {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
 		}
 	}
-	return n
-}
-
-func sovConfig(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
+	if len(m.RequiredLabels) > 0 {
+		for _, s := range m.RequiredLabels {
+			dAtA[i] = 0x22
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
 		}
 	}
-	return n
-}
-func sozConfig(x uint64) (n int) {
-	return sovConfig(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (this *RuntimeConfig) String() string {
-	if this == nil {
-		return "nil"
+	if m.QuotaConsumer != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.QuotaConsumer))
 	}
-	s := strings.Join([]string{`&RuntimeConfig{`,
-		`CheckCacheSize:` + fmt.Sprintf("%v", this.CheckCacheSize) + `,`,
-		`CheckResultExpiration:` + strings.Replace(fmt.Sprintf("%v", this.CheckResultExpiration), "Duration", "google_protobuf1.Duration", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+	if m.ReportConsumer != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.ReportConsumer))
+	}
+	if m.ConsumerAnonymization != nil {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.ConsumerAnonymization.Size()))
+		n22, err := m.ConsumerAnonymization.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n22
+	}
+	if len(m.RequestStateAttribute) > 0 {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.RequestStateAttribute)))
+		i += copy(dAtA[i:], m.RequestStateAttribute)
+	}
+	if m.ApiKeyRateLimit != nil {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.ApiKeyRateLimit.Size()))
+		n23, err := m.ApiKeyRateLimit.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n23
+	}
+	if len(m.PeerIdentityAttribute) > 0 {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.PeerIdentityAttribute)))
+		i += copy(dAtA[i:], m.PeerIdentityAttribute)
+	}
+	if m.HeaderLabels != nil {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.HeaderLabels.Size()))
+		n24, err := m.HeaderLabels.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n24
+	}
+	if len(m.SuppressReportAttribute) > 0 {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.SuppressReportAttribute)))
+		i += copy(dAtA[i:], m.SuppressReportAttribute)
+	}
+	if len(m.ConsumerAllowlist) > 0 {
+		for _, s := range m.ConsumerAllowlist {
+			dAtA[i] = 0x6a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.ConsumerDenylist) > 0 {
+		for _, s := range m.ConsumerDenylist {
+			dAtA[i] = 0x72
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.TrailerLabels) > 0 {
+		for k, _ := range m.TrailerLabels {
+			dAtA[i] = 0x7a
+			i++
+			v := m.TrailerLabels[k]
+			mapSize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + len(v) + sovConfig(uint64(len(v)))
+			i = encodeVarintConfig(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	if len(m.SourceWorkloadAttribute) > 0 {
+		dAtA[i] = 0x82
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.SourceWorkloadAttribute)))
+		i += copy(dAtA[i:], m.SourceWorkloadAttribute)
+	}
+	if len(m.DestinationWorkloadAttribute) > 0 {
+		dAtA[i] = 0x8a
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.DestinationWorkloadAttribute)))
+		i += copy(dAtA[i:], m.DestinationWorkloadAttribute)
+	}
+	if m.ConsumerResolutionFailurePolicy != 0 {
+		dAtA[i] = 0x90
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.ConsumerResolutionFailurePolicy))
+	}
+	if len(m.MetricTransforms) > 0 {
+		for _, msg := range m.MetricTransforms {
+			dAtA[i] = 0x9a
+			i++
+			dAtA[i] = 0x1
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.CredentialPath) > 0 {
+		dAtA[i] = 0xa2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.CredentialPath)))
+		i += copy(dAtA[i:], m.CredentialPath)
+	}
+	if len(m.LogEntryExports) > 0 {
+		for k, _ := range m.LogEntryExports {
+			dAtA[i] = 0xaa
+			i++
+			dAtA[i] = 0x1
+			i++
+			v := m.LogEntryExports[k]
+			msgSize := 0
+			if v != nil {
+				msgSize = v.Size()
+				msgSize += 1 + sovConfig(uint64(msgSize))
+			}
+			mapSize := 1 + len(k) + sovConfig(uint64(len(k))) + msgSize
+			i = encodeVarintConfig(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			if v != nil {
+				dAtA[i] = 0x12
+				i++
+				i = encodeVarintConfig(dAtA, i, uint64(v.Size()))
+				n25, err := v.MarshalTo(dAtA[i:])
+				if err != nil {
+					return 0, err
+				}
+				i += n25
+			}
+		}
+	}
+	if len(m.PeerIdentityProjects) > 0 {
+		for k, _ := range m.PeerIdentityProjects {
+			dAtA[i] = 0xb2
+			i++
+			dAtA[i] = 0x1
+			i++
+			v := m.PeerIdentityProjects[k]
+			mapSize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + len(v) + sovConfig(uint64(len(v)))
+			i = encodeVarintConfig(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	if m.EscalateCheckImportance {
+		dAtA[i] = 0xb8
+		i++
+		dAtA[i] = 0x1
+		i++
+		if m.EscalateCheckImportance {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
 }
-func (this *Quota) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *LogEntryExport) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LogEntryExport) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.LogName) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.LogName)))
+		i += copy(dAtA[i:], m.LogName)
+	}
+	if len(m.LabelVariables) > 0 {
+		for _, s := range m.LabelVariables {
+			dAtA[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.ApiKeyVariable) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.ApiKeyVariable)))
+		i += copy(dAtA[i:], m.ApiKeyVariable)
+	}
+	if len(m.ApiOperationVariable) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.ApiOperationVariable)))
+		i += copy(dAtA[i:], m.ApiOperationVariable)
+	}
+	return i, nil
+}
+
+func (m *MetricTransform) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MetricTransform) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.MetricName) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.MetricName)))
+		i += copy(dAtA[i:], m.MetricName)
+	}
+	if len(m.Transformer) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Transformer)))
+		i += copy(dAtA[i:], m.Transformer)
+	}
+	if m.Factor != 0 {
+		dAtA[i] = 0x19
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Factor))))
+		i += 8
+	}
+	if m.Min != 0 {
+		dAtA[i] = 0x21
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Min))))
+		i += 8
+	}
+	if m.Max != 0 {
+		dAtA[i] = 0x29
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Max))))
+		i += 8
+	}
+	if len(m.Boundaries) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Boundaries)*8))
+		for _, num := range m.Boundaries {
+			f26 := math.Float64bits(float64(num))
+			encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(f26))
+			i += 8
+		}
+	}
+	return i, nil
+}
+
+func (m *HeaderLabels) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HeaderLabels) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.AllowedHeaders) > 0 {
+		for _, s := range m.AllowedHeaders {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.Labels) > 0 {
+		for k, _ := range m.Labels {
+			dAtA[i] = 0x12
+			i++
+			v := m.Labels[k]
+			mapSize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + len(v) + sovConfig(uint64(len(v)))
+			i = encodeVarintConfig(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	return i, nil
+}
+
+func (m *ApiKeyRateLimit) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ApiKeyRateLimit) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.RequestsPerSecond != 0 {
+		dAtA[i] = 0x9
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.RequestsPerSecond))))
+		i += 8
+	}
+	if m.Burst != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.Burst))
+	}
+	if m.MaxTrackedKeys != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxTrackedKeys))
+	}
+	return i, nil
+}
+
+func (m *ConsumerAnonymization) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ConsumerAnonymization) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Salt) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Salt)))
+		i += copy(dAtA[i:], m.Salt)
+	}
+	return i, nil
+}
+
+func (m *Params) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.RuntimeConfig != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.RuntimeConfig.Size()))
+		n27, err := m.RuntimeConfig.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n27
+	}
+	if len(m.CredentialPath) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.CredentialPath)))
+		i += copy(dAtA[i:], m.CredentialPath)
+	}
+	if len(m.ServiceConfigs) > 0 {
+		for _, msg := range m.ServiceConfigs {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintConfig(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.CredentialMode != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(m.CredentialMode))
+	}
+	if len(m.ServiceConfigsPath) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.ServiceConfigsPath)))
+		i += copy(dAtA[i:], m.ServiceConfigsPath)
+	}
+	return i, nil
+}
+
+func encodeVarintConfig(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *RuntimeConfig) Size() (n int) {
+	var l int
+	_ = l
+	if m.CheckCacheSize != 0 {
+		n += 1 + sovConfig(uint64(m.CheckCacheSize))
+	}
+	if m.CheckResultExpiration != nil {
+		l = m.CheckResultExpiration.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.WarningLogRate != 0 {
+		n += 1 + sovConfig(uint64(m.WarningLogRate))
+	}
+	if m.AdaptiveFailOpen != nil {
+		l = m.AdaptiveFailOpen.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.AdaptiveReportThrottling != nil {
+		l = m.AdaptiveReportThrottling.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.CredentialReloadInterval != nil {
+		l = m.CredentialReloadInterval.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if len(m.TransientCheckErrors) > 0 {
+		for _, s := range m.TransientCheckErrors {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if m.NegativeCheckResultExpiration != nil {
+		l = m.NegativeCheckResultExpiration.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.UseDedupIdAsOperationId {
+		n += 2
+	}
+	l = len(m.OperationIdNamespace)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.QuotaDeadline != nil {
+		l = m.QuotaDeadline.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.QuotaFailOpenOnDeadline {
+		n += 2
+	}
+	if m.ConsumerMetricsMaxConsumers != 0 {
+		n += 1 + sovConfig(uint64(m.ConsumerMetricsMaxConsumers))
+	}
+	if m.MetricTimeAlignment != nil {
+		l = m.MetricTimeAlignment.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if len(m.AdvisoryCheckErrors) > 0 {
+		for _, s := range m.AdvisoryCheckErrors {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if m.Endpoints != nil {
+		l = m.Endpoints.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.CloseGracePeriod != nil {
+		l = m.CloseGracePeriod.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.QuotaCoalescingWindow != nil {
+		l = m.QuotaCoalescingWindow.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.AllowEmptyServiceConfigs {
+		n += 3
+	}
+	if m.ReportFlushInterval != nil {
+		l = m.ReportFlushInterval.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.MaxReportBatchSize != 0 {
+		n += 2 + sovConfig(uint64(m.MaxReportBatchSize))
+	}
+	if m.RetryPolicy != nil {
+		l = m.RetryPolicy.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.CircuitBreaker != nil {
+		l = m.CircuitBreaker.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.NetworkFailPolicy != 0 {
+		n += 2 + sovConfig(uint64(m.NetworkFailPolicy))
+	}
+	if m.ServiceConfigsReloadInterval != nil {
+		l = m.ServiceConfigsReloadInterval.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.EnableReport {
+		n += 3
+	}
+	if m.EnableQuota {
+		n += 3
+	}
+	if m.MaxServiceProcessors != 0 {
+		n += 2 + sovConfig(uint64(m.MaxServiceProcessors))
+	}
+	if m.ReportFlushWorkers != 0 {
+		n += 2 + sovConfig(uint64(m.ReportFlushWorkers))
+	}
+	l = len(m.MetadataServerEndpoint)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.CacheShards != 0 {
+		n += 2 + sovConfig(uint64(m.CacheShards))
+	}
+	if m.MaxReportBufferBytes != 0 {
+		n += 2 + sovConfig(uint64(m.MaxReportBufferBytes))
+	}
+	return n
+}
+
+func (m *RetryPolicy) Size() (n int) {
+	var l int
+	_ = l
+	if m.MaxAttempts != 0 {
+		n += 1 + sovConfig(uint64(m.MaxAttempts))
+	}
+	if m.InitialBackoff != nil {
+		l = m.InitialBackoff.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.MaxBackoff != nil {
+		l = m.MaxBackoff.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.BudgetRatio != 0 {
+		n += 9
+	}
+	return n
+}
+
+func (m *CircuitBreaker) Size() (n int) {
+	var l int
+	_ = l
+	if m.FailureThreshold != 0 {
+		n += 1 + sovConfig(uint64(m.FailureThreshold))
+	}
+	if m.OpenDuration != nil {
+		l = m.OpenDuration.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *ServiceControlEndpoints) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Check)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.Report)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.Quota)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *AdaptiveFailOpen) Size() (n int) {
+	var l int
+	_ = l
+	if m.ErrorRateThreshold != 0 {
+		n += 9
+	}
+	if m.Window != nil {
+		l = m.Window.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *AdaptiveReportThrottling) Size() (n int) {
+	var l int
+	_ = l
+	if m.BackoffFactor != 0 {
+		n += 9
+	}
+	if m.RecoveryStep != 0 {
+		n += 9
+	}
+	if len(m.Exemptions) > 0 {
+		for _, e := range m.Exemptions {
+			l = e.Size()
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ThrottlingExemption) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.OperationPattern)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.Label)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.LabelValuePattern)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *Quota) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.GoogleQuotaMetricName)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.Expiration != nil {
+		l = m.Expiration.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if len(m.ExpirationOverrides) > 0 {
+		for _, e := range m.ExpirationOverrides {
+			l = e.Size()
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if m.AllocationMode != 0 {
+		n += 1 + sovConfig(uint64(m.AllocationMode))
+	}
+	if m.PrefetchAmount != 0 {
+		n += 1 + sovConfig(uint64(m.PrefetchAmount))
+	}
+	if m.ReconcileInterval != nil {
+		l = m.ReconcileInterval.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *Quota_ExpirationOverride) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.ConsumerPattern)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.Expiration != nil {
+		l = m.Expiration.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *GcpServiceSetting) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.MeshServiceName)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.GoogleServiceName)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if len(m.Quotas) > 0 {
+		for _, e := range m.Quotas {
+			l = e.Size()
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if len(m.RequiredLabels) > 0 {
+		for _, s := range m.RequiredLabels {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if m.QuotaConsumer != 0 {
+		n += 1 + sovConfig(uint64(m.QuotaConsumer))
+	}
+	if m.ReportConsumer != 0 {
+		n += 1 + sovConfig(uint64(m.ReportConsumer))
+	}
+	if m.ConsumerAnonymization != nil {
+		l = m.ConsumerAnonymization.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.RequestStateAttribute)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.ApiKeyRateLimit != nil {
+		l = m.ApiKeyRateLimit.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.PeerIdentityAttribute)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.HeaderLabels != nil {
+		l = m.HeaderLabels.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.SuppressReportAttribute)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if len(m.ConsumerAllowlist) > 0 {
+		for _, s := range m.ConsumerAllowlist {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if len(m.ConsumerDenylist) > 0 {
+		for _, s := range m.ConsumerDenylist {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if len(m.TrailerLabels) > 0 {
+		for k, v := range m.TrailerLabels {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + len(v) + sovConfig(uint64(len(v)))
+			n += mapEntrySize + 1 + sovConfig(uint64(mapEntrySize))
+		}
+	}
+	l = len(m.SourceWorkloadAttribute)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	l = len(m.DestinationWorkloadAttribute)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.ConsumerResolutionFailurePolicy != 0 {
+		n += 2 + sovConfig(uint64(m.ConsumerResolutionFailurePolicy))
+	}
+	if len(m.MetricTransforms) > 0 {
+		for _, e := range m.MetricTransforms {
+			l = e.Size()
+			n += 2 + l + sovConfig(uint64(l))
+		}
+	}
+	l = len(m.CredentialPath)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if len(m.LogEntryExports) > 0 {
+		for k, v := range m.LogEntryExports {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovConfig(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovConfig(uint64(len(k))) + l
+			n += mapEntrySize + 2 + sovConfig(uint64(mapEntrySize))
+		}
+	}
+	if len(m.PeerIdentityProjects) > 0 {
+		for k, v := range m.PeerIdentityProjects {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + len(v) + sovConfig(uint64(len(v)))
+			n += mapEntrySize + 2 + sovConfig(uint64(mapEntrySize))
+		}
+	}
+	if m.EscalateCheckImportance {
+		n += 3
+	}
+	return n
+}
+
+func (m *LogEntryExport) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.LogName)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if len(m.LabelVariables) > 0 {
+		for _, s := range m.LabelVariables {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	l = len(m.ApiKeyVariable)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.ApiOperationVariable)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *MetricTransform) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.MetricName)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.Transformer)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.Factor != 0 {
+		n += 9
+	}
+	if m.Min != 0 {
+		n += 9
+	}
+	if m.Max != 0 {
+		n += 9
+	}
+	if len(m.Boundaries) > 0 {
+		n += 1 + sovConfig(uint64(len(m.Boundaries)*8)) + len(m.Boundaries)*8
+	}
+	return n
+}
+
+func (m *HeaderLabels) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.AllowedHeaders) > 0 {
+		for _, s := range m.AllowedHeaders {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if len(m.Labels) > 0 {
+		for k, v := range m.Labels {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovConfig(uint64(len(k))) + 1 + len(v) + sovConfig(uint64(len(v)))
+			n += mapEntrySize + 1 + sovConfig(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *ApiKeyRateLimit) Size() (n int) {
+	var l int
+	_ = l
+	if m.RequestsPerSecond != 0 {
+		n += 9
+	}
+	if m.Burst != 0 {
+		n += 1 + sovConfig(uint64(m.Burst))
+	}
+	if m.MaxTrackedKeys != 0 {
+		n += 1 + sovConfig(uint64(m.MaxTrackedKeys))
+	}
+	return n
+}
+
+func (m *ConsumerAnonymization) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Salt)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *Params) Size() (n int) {
+	var l int
+	_ = l
+	if m.RuntimeConfig != nil {
+		l = m.RuntimeConfig.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.CredentialPath)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if len(m.ServiceConfigs) > 0 {
+		for _, e := range m.ServiceConfigs {
+			l = e.Size()
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if m.CredentialMode != 0 {
+		n += 1 + sovConfig(uint64(m.CredentialMode))
+	}
+	l = len(m.ServiceConfigsPath)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func sovConfig(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozConfig(x uint64) (n int) {
+	return sovConfig(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *RuntimeConfig) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&RuntimeConfig{`,
+		`CheckCacheSize:` + fmt.Sprintf("%v", this.CheckCacheSize) + `,`,
+		`CheckResultExpiration:` + strings.Replace(fmt.Sprintf("%v", this.CheckResultExpiration), "Duration", "google_protobuf1.Duration", 1) + `,`,
+		`WarningLogRate:` + fmt.Sprintf("%v", this.WarningLogRate) + `,`,
+		`AdaptiveFailOpen:` + strings.Replace(fmt.Sprintf("%v", this.AdaptiveFailOpen), "AdaptiveFailOpen", "AdaptiveFailOpen", 1) + `,`,
+		`AdaptiveReportThrottling:` + strings.Replace(fmt.Sprintf("%v", this.AdaptiveReportThrottling), "AdaptiveReportThrottling", "AdaptiveReportThrottling", 1) + `,`,
+		`CredentialReloadInterval:` + strings.Replace(fmt.Sprintf("%v", this.CredentialReloadInterval), "Duration", "google_protobuf1.Duration", 1) + `,`,
+		`TransientCheckErrors:` + fmt.Sprintf("%v", this.TransientCheckErrors) + `,`,
+		`NegativeCheckResultExpiration:` + strings.Replace(fmt.Sprintf("%v", this.NegativeCheckResultExpiration), "Duration", "google_protobuf1.Duration", 1) + `,`,
+		`UseDedupIdAsOperationId:` + fmt.Sprintf("%v", this.UseDedupIdAsOperationId) + `,`,
+		`OperationIdNamespace:` + fmt.Sprintf("%v", this.OperationIdNamespace) + `,`,
+		`QuotaDeadline:` + strings.Replace(fmt.Sprintf("%v", this.QuotaDeadline), "Duration", "google_protobuf1.Duration", 1) + `,`,
+		`QuotaFailOpenOnDeadline:` + fmt.Sprintf("%v", this.QuotaFailOpenOnDeadline) + `,`,
+		`ConsumerMetricsMaxConsumers:` + fmt.Sprintf("%v", this.ConsumerMetricsMaxConsumers) + `,`,
+		`MetricTimeAlignment:` + strings.Replace(fmt.Sprintf("%v", this.MetricTimeAlignment), "Duration", "google_protobuf1.Duration", 1) + `,`,
+		`AdvisoryCheckErrors:` + fmt.Sprintf("%v", this.AdvisoryCheckErrors) + `,`,
+		`Endpoints:` + strings.Replace(fmt.Sprintf("%v", this.Endpoints), "ServiceControlEndpoints", "ServiceControlEndpoints", 1) + `,`,
+		`CloseGracePeriod:` + strings.Replace(fmt.Sprintf("%v", this.CloseGracePeriod), "Duration", "google_protobuf1.Duration", 1) + `,`,
+		`QuotaCoalescingWindow:` + strings.Replace(fmt.Sprintf("%v", this.QuotaCoalescingWindow), "Duration", "google_protobuf1.Duration", 1) + `,`,
+		`AllowEmptyServiceConfigs:` + fmt.Sprintf("%v", this.AllowEmptyServiceConfigs) + `,`,
+		`ReportFlushInterval:` + strings.Replace(fmt.Sprintf("%v", this.ReportFlushInterval), "Duration", "google_protobuf1.Duration", 1) + `,`,
+		`MaxReportBatchSize:` + fmt.Sprintf("%v", this.MaxReportBatchSize) + `,`,
+		`RetryPolicy:` + strings.Replace(fmt.Sprintf("%v", this.RetryPolicy), "RetryPolicy", "RetryPolicy", 1) + `,`,
+		`CircuitBreaker:` + strings.Replace(fmt.Sprintf("%v", this.CircuitBreaker), "CircuitBreaker", "CircuitBreaker", 1) + `,`,
+		`NetworkFailPolicy:` + fmt.Sprintf("%v", this.NetworkFailPolicy) + `,`,
+		`ServiceConfigsReloadInterval:` + strings.Replace(fmt.Sprintf("%v", this.ServiceConfigsReloadInterval), "Duration", "google_protobuf1.Duration", 1) + `,`,
+		`EnableReport:` + fmt.Sprintf("%v", this.EnableReport) + `,`,
+		`EnableQuota:` + fmt.Sprintf("%v", this.EnableQuota) + `,`,
+		`MaxServiceProcessors:` + fmt.Sprintf("%v", this.MaxServiceProcessors) + `,`,
+		`ReportFlushWorkers:` + fmt.Sprintf("%v", this.ReportFlushWorkers) + `,`,
+		`MetadataServerEndpoint:` + fmt.Sprintf("%v", this.MetadataServerEndpoint) + `,`,
+		`CacheShards:` + fmt.Sprintf("%v", this.CacheShards) + `,`,
+		`MaxReportBufferBytes:` + fmt.Sprintf("%v", this.MaxReportBufferBytes) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *RetryPolicy) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&RetryPolicy{`,
+		`MaxAttempts:` + fmt.Sprintf("%v", this.MaxAttempts) + `,`,
+		`InitialBackoff:` + strings.Replace(fmt.Sprintf("%v", this.InitialBackoff), "Duration", "google_protobuf1.Duration", 1) + `,`,
+		`MaxBackoff:` + strings.Replace(fmt.Sprintf("%v", this.MaxBackoff), "Duration", "google_protobuf1.Duration", 1) + `,`,
+		`BudgetRatio:` + fmt.Sprintf("%v", this.BudgetRatio) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CircuitBreaker) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CircuitBreaker{`,
+		`FailureThreshold:` + fmt.Sprintf("%v", this.FailureThreshold) + `,`,
+		`OpenDuration:` + strings.Replace(fmt.Sprintf("%v", this.OpenDuration), "Duration", "google_protobuf1.Duration", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ServiceControlEndpoints) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ServiceControlEndpoints{`,
+		`Check:` + fmt.Sprintf("%v", this.Check) + `,`,
+		`Report:` + fmt.Sprintf("%v", this.Report) + `,`,
+		`Quota:` + fmt.Sprintf("%v", this.Quota) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *AdaptiveFailOpen) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&AdaptiveFailOpen{`,
+		`ErrorRateThreshold:` + fmt.Sprintf("%v", this.ErrorRateThreshold) + `,`,
+		`Window:` + strings.Replace(fmt.Sprintf("%v", this.Window), "Duration", "google_protobuf1.Duration", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *AdaptiveReportThrottling) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&AdaptiveReportThrottling{`,
+		`BackoffFactor:` + fmt.Sprintf("%v", this.BackoffFactor) + `,`,
+		`RecoveryStep:` + fmt.Sprintf("%v", this.RecoveryStep) + `,`,
+		`Exemptions:` + strings.Replace(fmt.Sprintf("%v", this.Exemptions), "ThrottlingExemption", "ThrottlingExemption", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ThrottlingExemption) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ThrottlingExemption{`,
+		`OperationPattern:` + fmt.Sprintf("%v", this.OperationPattern) + `,`,
+		`Label:` + fmt.Sprintf("%v", this.Label) + `,`,
+		`LabelValuePattern:` + fmt.Sprintf("%v", this.LabelValuePattern) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Quota) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Quota{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`GoogleQuotaMetricName:` + fmt.Sprintf("%v", this.GoogleQuotaMetricName) + `,`,
+		`Expiration:` + strings.Replace(fmt.Sprintf("%v", this.Expiration), "Duration", "google_protobuf1.Duration", 1) + `,`,
+		`ExpirationOverrides:` + strings.Replace(fmt.Sprintf("%v", this.ExpirationOverrides), "Quota_ExpirationOverride", "Quota_ExpirationOverride", 1) + `,`,
+		`AllocationMode:` + fmt.Sprintf("%v", this.AllocationMode) + `,`,
+		`PrefetchAmount:` + fmt.Sprintf("%v", this.PrefetchAmount) + `,`,
+		`ReconcileInterval:` + strings.Replace(fmt.Sprintf("%v", this.ReconcileInterval), "Duration", "google_protobuf1.Duration", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Quota_ExpirationOverride) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Quota_ExpirationOverride{`,
+		`ConsumerPattern:` + fmt.Sprintf("%v", this.ConsumerPattern) + `,`,
+		`Expiration:` + strings.Replace(fmt.Sprintf("%v", this.Expiration), "Duration", "google_protobuf1.Duration", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GcpServiceSetting) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForTrailerLabels := make([]string, 0, len(this.TrailerLabels))
+	for k, _ := range this.TrailerLabels {
+		keysForTrailerLabels = append(keysForTrailerLabels, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForTrailerLabels)
+	mapStringForTrailerLabels := "map[string]string{"
+	for _, k := range keysForTrailerLabels {
+		mapStringForTrailerLabels += fmt.Sprintf("%v: %v,", k, this.TrailerLabels[k])
+	}
+	mapStringForTrailerLabels += "}"
+	keysForLogEntryExports := make([]string, 0, len(this.LogEntryExports))
+	for k, _ := range this.LogEntryExports {
+		keysForLogEntryExports = append(keysForLogEntryExports, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForLogEntryExports)
+	mapStringForLogEntryExports := "map[string]*LogEntryExport{"
+	for _, k := range keysForLogEntryExports {
+		mapStringForLogEntryExports += fmt.Sprintf("%v: %v,", k, this.LogEntryExports[k])
+	}
+	mapStringForLogEntryExports += "}"
+	keysForPeerIdentityProjects := make([]string, 0, len(this.PeerIdentityProjects))
+	for k, _ := range this.PeerIdentityProjects {
+		keysForPeerIdentityProjects = append(keysForPeerIdentityProjects, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForPeerIdentityProjects)
+	mapStringForPeerIdentityProjects := "map[string]string{"
+	for _, k := range keysForPeerIdentityProjects {
+		mapStringForPeerIdentityProjects += fmt.Sprintf("%v: %v,", k, this.PeerIdentityProjects[k])
+	}
+	mapStringForPeerIdentityProjects += "}"
+	s := strings.Join([]string{`&GcpServiceSetting{`,
+		`MeshServiceName:` + fmt.Sprintf("%v", this.MeshServiceName) + `,`,
+		`GoogleServiceName:` + fmt.Sprintf("%v", this.GoogleServiceName) + `,`,
+		`Quotas:` + strings.Replace(fmt.Sprintf("%v", this.Quotas), "Quota", "Quota", 1) + `,`,
+		`RequiredLabels:` + fmt.Sprintf("%v", this.RequiredLabels) + `,`,
+		`QuotaConsumer:` + fmt.Sprintf("%v", this.QuotaConsumer) + `,`,
+		`ReportConsumer:` + fmt.Sprintf("%v", this.ReportConsumer) + `,`,
+		`ConsumerAnonymization:` + strings.Replace(fmt.Sprintf("%v", this.ConsumerAnonymization), "ConsumerAnonymization", "ConsumerAnonymization", 1) + `,`,
+		`RequestStateAttribute:` + fmt.Sprintf("%v", this.RequestStateAttribute) + `,`,
+		`ApiKeyRateLimit:` + strings.Replace(fmt.Sprintf("%v", this.ApiKeyRateLimit), "ApiKeyRateLimit", "ApiKeyRateLimit", 1) + `,`,
+		`PeerIdentityAttribute:` + fmt.Sprintf("%v", this.PeerIdentityAttribute) + `,`,
+		`HeaderLabels:` + strings.Replace(fmt.Sprintf("%v", this.HeaderLabels), "HeaderLabels", "HeaderLabels", 1) + `,`,
+		`SuppressReportAttribute:` + fmt.Sprintf("%v", this.SuppressReportAttribute) + `,`,
+		`ConsumerAllowlist:` + fmt.Sprintf("%v", this.ConsumerAllowlist) + `,`,
+		`ConsumerDenylist:` + fmt.Sprintf("%v", this.ConsumerDenylist) + `,`,
+		`TrailerLabels:` + mapStringForTrailerLabels + `,`,
+		`SourceWorkloadAttribute:` + fmt.Sprintf("%v", this.SourceWorkloadAttribute) + `,`,
+		`DestinationWorkloadAttribute:` + fmt.Sprintf("%v", this.DestinationWorkloadAttribute) + `,`,
+		`ConsumerResolutionFailurePolicy:` + fmt.Sprintf("%v", this.ConsumerResolutionFailurePolicy) + `,`,
+		`MetricTransforms:` + strings.Replace(fmt.Sprintf("%v", this.MetricTransforms), "MetricTransform", "MetricTransform", 1) + `,`,
+		`CredentialPath:` + fmt.Sprintf("%v", this.CredentialPath) + `,`,
+		`LogEntryExports:` + mapStringForLogEntryExports + `,`,
+		`PeerIdentityProjects:` + mapStringForPeerIdentityProjects + `,`,
+		`EscalateCheckImportance:` + fmt.Sprintf("%v", this.EscalateCheckImportance) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LogEntryExport) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LogEntryExport{`,
+		`LogName:` + fmt.Sprintf("%v", this.LogName) + `,`,
+		`LabelVariables:` + fmt.Sprintf("%v", this.LabelVariables) + `,`,
+		`ApiKeyVariable:` + fmt.Sprintf("%v", this.ApiKeyVariable) + `,`,
+		`ApiOperationVariable:` + fmt.Sprintf("%v", this.ApiOperationVariable) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *MetricTransform) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&MetricTransform{`,
+		`MetricName:` + fmt.Sprintf("%v", this.MetricName) + `,`,
+		`Transformer:` + fmt.Sprintf("%v", this.Transformer) + `,`,
+		`Factor:` + fmt.Sprintf("%v", this.Factor) + `,`,
+		`Min:` + fmt.Sprintf("%v", this.Min) + `,`,
+		`Max:` + fmt.Sprintf("%v", this.Max) + `,`,
+		`Boundaries:` + fmt.Sprintf("%v", this.Boundaries) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *HeaderLabels) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForLabels := make([]string, 0, len(this.Labels))
+	for k, _ := range this.Labels {
+		keysForLabels = append(keysForLabels, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForLabels)
+	mapStringForLabels := "map[string]string{"
+	for _, k := range keysForLabels {
+		mapStringForLabels += fmt.Sprintf("%v: %v,", k, this.Labels[k])
+	}
+	mapStringForLabels += "}"
+	s := strings.Join([]string{`&HeaderLabels{`,
+		`AllowedHeaders:` + fmt.Sprintf("%v", this.AllowedHeaders) + `,`,
+		`Labels:` + mapStringForLabels + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ApiKeyRateLimit) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ApiKeyRateLimit{`,
+		`RequestsPerSecond:` + fmt.Sprintf("%v", this.RequestsPerSecond) + `,`,
+		`Burst:` + fmt.Sprintf("%v", this.Burst) + `,`,
+		`MaxTrackedKeys:` + fmt.Sprintf("%v", this.MaxTrackedKeys) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ConsumerAnonymization) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ConsumerAnonymization{`,
+		`Salt:` + fmt.Sprintf("%v", this.Salt) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Params) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Params{`,
+		`RuntimeConfig:` + strings.Replace(fmt.Sprintf("%v", this.RuntimeConfig), "RuntimeConfig", "RuntimeConfig", 1) + `,`,
+		`CredentialPath:` + fmt.Sprintf("%v", this.CredentialPath) + `,`,
+		`ServiceConfigs:` + strings.Replace(fmt.Sprintf("%v", this.ServiceConfigs), "GcpServiceSetting", "GcpServiceSetting", 1) + `,`,
+		`CredentialMode:` + fmt.Sprintf("%v", this.CredentialMode) + `,`,
+		`ServiceConfigsPath:` + fmt.Sprintf("%v", this.ServiceConfigsPath) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringConfig(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *RuntimeConfig) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RuntimeConfig: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RuntimeConfig: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CheckCacheSize", wireType)
+			}
+			m.CheckCacheSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CheckCacheSize |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CheckResultExpiration", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CheckResultExpiration == nil {
+				m.CheckResultExpiration = &google_protobuf1.Duration{}
+			}
+			if err := m.CheckResultExpiration.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WarningLogRate", wireType)
+			}
+			m.WarningLogRate = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WarningLogRate |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AdaptiveFailOpen", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.AdaptiveFailOpen == nil {
+				m.AdaptiveFailOpen = &AdaptiveFailOpen{}
+			}
+			if err := m.AdaptiveFailOpen.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AdaptiveReportThrottling", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.AdaptiveReportThrottling == nil {
+				m.AdaptiveReportThrottling = &AdaptiveReportThrottling{}
+			}
+			if err := m.AdaptiveReportThrottling.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CredentialReloadInterval", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CredentialReloadInterval == nil {
+				m.CredentialReloadInterval = &google_protobuf1.Duration{}
+			}
+			if err := m.CredentialReloadInterval.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TransientCheckErrors", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TransientCheckErrors = append(m.TransientCheckErrors, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NegativeCheckResultExpiration", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.NegativeCheckResultExpiration == nil {
+				m.NegativeCheckResultExpiration = &google_protobuf1.Duration{}
+			}
+			if err := m.NegativeCheckResultExpiration.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UseDedupIdAsOperationId", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.UseDedupIdAsOperationId = bool(v != 0)
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperationIdNamespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OperationIdNamespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QuotaDeadline", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.QuotaDeadline == nil {
+				m.QuotaDeadline = &google_protobuf1.Duration{}
+			}
+			if err := m.QuotaDeadline.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QuotaFailOpenOnDeadline", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.QuotaFailOpenOnDeadline = bool(v != 0)
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConsumerMetricsMaxConsumers", wireType)
+			}
+			m.ConsumerMetricsMaxConsumers = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ConsumerMetricsMaxConsumers |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricTimeAlignment", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MetricTimeAlignment == nil {
+				m.MetricTimeAlignment = &google_protobuf1.Duration{}
+			}
+			if err := m.MetricTimeAlignment.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AdvisoryCheckErrors", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AdvisoryCheckErrors = append(m.AdvisoryCheckErrors, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Endpoints", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Endpoints == nil {
+				m.Endpoints = &ServiceControlEndpoints{}
+			}
+			if err := m.Endpoints.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CloseGracePeriod", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CloseGracePeriod == nil {
+				m.CloseGracePeriod = &google_protobuf1.Duration{}
+			}
+			if err := m.CloseGracePeriod.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QuotaCoalescingWindow", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.QuotaCoalescingWindow == nil {
+				m.QuotaCoalescingWindow = &google_protobuf1.Duration{}
+			}
+			if err := m.QuotaCoalescingWindow.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 19:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowEmptyServiceConfigs", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.AllowEmptyServiceConfigs = bool(v != 0)
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReportFlushInterval", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ReportFlushInterval == nil {
+				m.ReportFlushInterval = &google_protobuf1.Duration{}
+			}
+			if err := m.ReportFlushInterval.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 21:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxReportBatchSize", wireType)
+			}
+			m.MaxReportBatchSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxReportBatchSize |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 22:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RetryPolicy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RetryPolicy == nil {
+				m.RetryPolicy = &RetryPolicy{}
+			}
+			if err := m.RetryPolicy.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 23:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CircuitBreaker", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CircuitBreaker == nil {
+				m.CircuitBreaker = &CircuitBreaker{}
+			}
+			if err := m.CircuitBreaker.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 24:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NetworkFailPolicy", wireType)
+			}
+			m.NetworkFailPolicy = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.NetworkFailPolicy |= (RuntimeConfig_NetworkFailPolicy(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 25:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceConfigsReloadInterval", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ServiceConfigsReloadInterval == nil {
+				m.ServiceConfigsReloadInterval = &google_protobuf1.Duration{}
+			}
+			if err := m.ServiceConfigsReloadInterval.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 26:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EnableReport", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.EnableReport = bool(v != 0)
+		case 27:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EnableQuota", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.EnableQuota = bool(v != 0)
+		case 28:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxServiceProcessors", wireType)
+			}
+			m.MaxServiceProcessors = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxServiceProcessors |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 29:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReportFlushWorkers", wireType)
+			}
+			m.ReportFlushWorkers = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReportFlushWorkers |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 30:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetadataServerEndpoint", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MetadataServerEndpoint = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 31:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CacheShards", wireType)
+			}
+			m.CacheShards = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CacheShards |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 32:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxReportBufferBytes", wireType)
+			}
+			m.MaxReportBufferBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxReportBufferBytes |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RetryPolicy) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RetryPolicy: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RetryPolicy: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxAttempts", wireType)
+			}
+			m.MaxAttempts = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxAttempts |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InitialBackoff", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.InitialBackoff == nil {
+				m.InitialBackoff = &google_protobuf1.Duration{}
+			}
+			if err := m.InitialBackoff.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxBackoff", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MaxBackoff == nil {
+				m.MaxBackoff = &google_protobuf1.Duration{}
+			}
+			if err := m.MaxBackoff.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BudgetRatio", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.BudgetRatio = float64(math.Float64frombits(v))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CircuitBreaker) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CircuitBreaker: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CircuitBreaker: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FailureThreshold", wireType)
+			}
+			m.FailureThreshold = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FailureThreshold |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OpenDuration", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.OpenDuration == nil {
+				m.OpenDuration = &google_protobuf1.Duration{}
+			}
+			if err := m.OpenDuration.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ServiceControlEndpoints) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ServiceControlEndpoints: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ServiceControlEndpoints: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Check", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Check = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Report", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Report = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Quota", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Quota = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AdaptiveFailOpen) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AdaptiveFailOpen: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AdaptiveFailOpen: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorRateThreshold", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.ErrorRateThreshold = float64(math.Float64frombits(v))
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Window", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Window == nil {
+				m.Window = &google_protobuf1.Duration{}
+			}
+			if err := m.Window.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AdaptiveReportThrottling) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AdaptiveReportThrottling: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AdaptiveReportThrottling: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BackoffFactor", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.BackoffFactor = float64(math.Float64frombits(v))
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RecoveryStep", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.RecoveryStep = float64(math.Float64frombits(v))
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exemptions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Exemptions = append(m.Exemptions, &ThrottlingExemption{})
+			if err := m.Exemptions[len(m.Exemptions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ThrottlingExemption) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ThrottlingExemption: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ThrottlingExemption: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperationPattern", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OperationPattern = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Label", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Label = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LabelValuePattern", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LabelValuePattern = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Quota) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Quota: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Quota: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GoogleQuotaMetricName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GoogleQuotaMetricName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Expiration", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Expiration == nil {
+				m.Expiration = &google_protobuf1.Duration{}
+			}
+			if err := m.Expiration.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpirationOverrides", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExpirationOverrides = append(m.ExpirationOverrides, &Quota_ExpirationOverride{})
+			if err := m.ExpirationOverrides[len(m.ExpirationOverrides)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllocationMode", wireType)
+			}
+			m.AllocationMode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AllocationMode |= (Quota_AllocationMode(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PrefetchAmount", wireType)
+			}
+			m.PrefetchAmount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PrefetchAmount |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReconcileInterval", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ReconcileInterval == nil {
+				m.ReconcileInterval = &google_protobuf1.Duration{}
+			}
+			if err := m.ReconcileInterval.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Quota_ExpirationOverride) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExpirationOverride: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExpirationOverride: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConsumerPattern", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ConsumerPattern = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Expiration", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Expiration == nil {
+				m.Expiration = &google_protobuf1.Duration{}
+			}
+			if err := m.Expiration.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GcpServiceSetting) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GcpServiceSetting: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GcpServiceSetting: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MeshServiceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MeshServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GoogleServiceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GoogleServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Quotas", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Quotas = append(m.Quotas, &Quota{})
+			if err := m.Quotas[len(m.Quotas)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequiredLabels", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RequiredLabels = append(m.RequiredLabels, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QuotaConsumer", wireType)
+			}
+			m.QuotaConsumer = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.QuotaConsumer |= (GcpServiceSetting_ConsumerSource(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReportConsumer", wireType)
+			}
+			m.ReportConsumer = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReportConsumer |= (GcpServiceSetting_ConsumerSource(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConsumerAnonymization", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ConsumerAnonymization == nil {
+				m.ConsumerAnonymization = &ConsumerAnonymization{}
+			}
+			if err := m.ConsumerAnonymization.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestStateAttribute", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RequestStateAttribute = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ApiKeyRateLimit", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ApiKeyRateLimit == nil {
+				m.ApiKeyRateLimit = &ApiKeyRateLimit{}
+			}
+			if err := m.ApiKeyRateLimit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PeerIdentityAttribute", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PeerIdentityAttribute = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeaderLabels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.HeaderLabels == nil {
+				m.HeaderLabels = &HeaderLabels{}
+			}
+			if err := m.HeaderLabels.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SuppressReportAttribute", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SuppressReportAttribute = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConsumerAllowlist", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ConsumerAllowlist = append(m.ConsumerAllowlist, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConsumerDenylist", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ConsumerDenylist = append(m.ConsumerDenylist, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TrailerLabels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.TrailerLabels == nil {
+				m.TrailerLabels = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipConfig(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.TrailerLabels[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SourceWorkloadAttribute", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SourceWorkloadAttribute = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DestinationWorkloadAttribute", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DestinationWorkloadAttribute = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 18:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConsumerResolutionFailurePolicy", wireType)
+			}
+			m.ConsumerResolutionFailurePolicy = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ConsumerResolutionFailurePolicy |= (GcpServiceSetting_ConsumerResolutionFailurePolicy(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricTransforms", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MetricTransforms = append(m.MetricTransforms, &MetricTransform{})
+			if err := m.MetricTransforms[len(m.MetricTransforms)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CredentialPath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CredentialPath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 21:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LogEntryExports", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.LogEntryExports == nil {
+				m.LogEntryExports = make(map[string]*LogEntryExport)
+			}
+			var mapkey string
+			var mapvalue *LogEntryExport
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= (int(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if mapmsglen < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &LogEntryExport{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipConfig(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.LogEntryExports[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 22:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PeerIdentityProjects", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.PeerIdentityProjects == nil {
+				m.PeerIdentityProjects = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipConfig(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.PeerIdentityProjects[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 23:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EscalateCheckImportance", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.EscalateCheckImportance = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	s := strings.Join([]string{`&Quota{`,
-		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
-		`GoogleQuotaMetricName:` + fmt.Sprintf("%v", this.GoogleQuotaMetricName) + `,`,
-		`Expiration:` + strings.Replace(fmt.Sprintf("%v", this.Expiration), "Duration", "google_protobuf1.Duration", 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *GcpServiceSetting) String() string {
-	if this == nil {
-		return "nil"
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	s := strings.Join([]string{`&GcpServiceSetting{`,
-		`MeshServiceName:` + fmt.Sprintf("%v", this.MeshServiceName) + `,`,
-		`GoogleServiceName:` + fmt.Sprintf("%v", this.GoogleServiceName) + `,`,
-		`Quotas:` + strings.Replace(fmt.Sprintf("%v", this.Quotas), "Quota", "Quota", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+	return nil
 }
-func (this *Params) String() string {
-	if this == nil {
-		return "nil"
+func (m *LogEntryExport) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LogEntryExport: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LogEntryExport: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LogName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LogName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LabelVariables", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LabelVariables = append(m.LabelVariables, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ApiKeyVariable", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ApiKeyVariable = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ApiOperationVariable", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ApiOperationVariable = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	s := strings.Join([]string{`&Params{`,
-		`RuntimeConfig:` + strings.Replace(fmt.Sprintf("%v", this.RuntimeConfig), "RuntimeConfig", "RuntimeConfig", 1) + `,`,
-		`CredentialPath:` + fmt.Sprintf("%v", this.CredentialPath) + `,`,
-		`ServiceConfigs:` + strings.Replace(fmt.Sprintf("%v", this.ServiceConfigs), "GcpServiceSetting", "GcpServiceSetting", 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func valueToStringConfig(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	return nil
 }
-func (m *RuntimeConfig) Unmarshal(dAtA []byte) error {
+func (m *MetricTransform) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -443,17 +5922,17 @@ func (m *RuntimeConfig) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: RuntimeConfig: wiretype end group for non-group")
+			return fmt.Errorf("proto: MetricTransform: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RuntimeConfig: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: MetricTransform: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CheckCacheSize", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricName", wireType)
 			}
-			m.CheckCacheSize = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -463,16 +5942,26 @@ func (m *RuntimeConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.CheckCacheSize |= (int32(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MetricName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CheckResultExpiration", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Transformer", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -482,25 +5971,100 @@ func (m *RuntimeConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthConfig
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.CheckResultExpiration == nil {
-				m.CheckResultExpiration = &google_protobuf1.Duration{}
+			m.Transformer = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Factor", wireType)
 			}
-			if err := m.CheckResultExpiration.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Factor = float64(math.Float64frombits(v))
+		case 4:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Min", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Min = float64(math.Float64frombits(v))
+		case 5:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Max", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Max = float64(math.Float64frombits(v))
+		case 6:
+			if wireType == 1 {
+				var v uint64
+				if (iNdEx + 8) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+				iNdEx += 8
+				v2 := float64(math.Float64frombits(v))
+				m.Boundaries = append(m.Boundaries, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthConfig
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					if (iNdEx + 8) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+					iNdEx += 8
+					v2 := float64(math.Float64frombits(v))
+					m.Boundaries = append(m.Boundaries, v2)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Boundaries", wireType)
 			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
@@ -522,7 +6086,7 @@ func (m *RuntimeConfig) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Quota) Unmarshal(dAtA []byte) error {
+func (m *HeaderLabels) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -545,15 +6109,15 @@ func (m *Quota) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Quota: wiretype end group for non-group")
+			return fmt.Errorf("proto: HeaderLabels: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Quota: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: HeaderLabels: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowedHeaders", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -578,40 +6142,11 @@ func (m *Quota) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			m.AllowedHeaders = append(m.AllowedHeaders, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GoogleQuotaMetricName", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowConfig
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthConfig
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.GoogleQuotaMetricName = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Expiration", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -635,12 +6170,97 @@ func (m *Quota) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Expiration == nil {
-				m.Expiration = &google_protobuf1.Duration{}
+			if m.Labels == nil {
+				m.Labels = make(map[string]string)
 			}
-			if err := m.Expiration.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthConfig
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipConfig(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthConfig
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
 			}
+			m.Labels[mapkey] = mapvalue
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -663,7 +6283,7 @@ func (m *Quota) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *GcpServiceSetting) Unmarshal(dAtA []byte) error {
+func (m *ApiKeyRateLimit) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -686,17 +6306,28 @@ func (m *GcpServiceSetting) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: GcpServiceSetting: wiretype end group for non-group")
+			return fmt.Errorf("proto: ApiKeyRateLimit: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GcpServiceSetting: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ApiKeyRateLimit: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MeshServiceName", wireType)
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestsPerSecond", wireType)
 			}
-			var stringLen uint64
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.RequestsPerSecond = float64(math.Float64frombits(v))
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Burst", wireType)
+			}
+			m.Burst = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -706,26 +6337,16 @@ func (m *GcpServiceSetting) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				m.Burst |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthConfig
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.MeshServiceName = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GoogleServiceName", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxTrackedKeys", wireType)
 			}
-			var stringLen uint64
+			m.MaxTrackedKeys = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -735,26 +6356,66 @@ func (m *GcpServiceSetting) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				m.MaxTrackedKeys |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
 				return ErrInvalidLengthConfig
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.GoogleServiceName = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 3:
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ConsumerAnonymization) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ConsumerAnonymization: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ConsumerAnonymization: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Quotas", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Salt", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -764,22 +6425,20 @@ func (m *GcpServiceSetting) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthConfig
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Quotas = append(m.Quotas, &Quota{})
-			if err := m.Quotas[len(m.Quotas)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Salt = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -924,6 +6583,54 @@ func (m *Params) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CredentialMode", wireType)
+			}
+			m.CredentialMode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CredentialMode |= (Params_CredentialMode(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceConfigsPath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceConfigsPath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
@@ -1053,36 +6760,182 @@ var (
 func init() { proto.RegisterFile("mixer/adapter/svcctrl/config/config.proto", fileDescriptorConfig) }
 
 var fileDescriptorConfig = []byte{
-	// 485 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x92, 0xb1, 0x6e, 0x13, 0x31,
-	0x18, 0xc7, 0xcf, 0x0d, 0x8d, 0x84, 0xa3, 0x26, 0xc4, 0x50, 0x08, 0x95, 0xb0, 0xa2, 0x48, 0x88,
-	0x94, 0xc1, 0x27, 0x05, 0x21, 0x60, 0xa5, 0x20, 0x16, 0x40, 0xad, 0xb3, 0xb1, 0x58, 0xae, 0xe3,
-	0xde, 0x59, 0xe4, 0xce, 0x87, 0xcf, 0x57, 0x55, 0x9d, 0x78, 0x00, 0x24, 0x78, 0x0a, 0xc4, 0xa3,
-	0x74, 0xac, 0xc4, 0xc2, 0x48, 0x8e, 0x85, 0xb1, 0x8f, 0x80, 0xce, 0x76, 0x20, 0x11, 0x54, 0x4c,
-	0x71, 0xbe, 0xef, 0xf7, 0xfd, 0xff, 0xff, 0xef, 0x6c, 0xb8, 0x9b, 0xa9, 0x13, 0x69, 0x62, 0x3e,
-	0xe3, 0x85, 0x95, 0x26, 0x2e, 0x8f, 0x85, 0xb0, 0x66, 0x1e, 0x0b, 0x9d, 0x1f, 0xa9, 0x24, 0xfc,
-	0x90, 0xc2, 0x68, 0xab, 0xd1, 0xcd, 0x00, 0x91, 0x00, 0x11, 0xdf, 0xdd, 0xb9, 0x91, 0xe8, 0x44,
-	0x3b, 0x24, 0x6e, 0x4e, 0x9e, 0xde, 0xc1, 0x89, 0xd6, 0xc9, 0x5c, 0xc6, 0xee, 0xdf, 0x61, 0x75,
-	0x14, 0xcf, 0x2a, 0xc3, 0xad, 0xd2, 0xb9, 0xef, 0x8f, 0x3e, 0x00, 0xb8, 0x45, 0xab, 0xdc, 0xaa,
-	0x4c, 0xee, 0x39, 0x1d, 0x34, 0x86, 0xd7, 0x44, 0x2a, 0xc5, 0x5b, 0x26, 0xb8, 0x48, 0x25, 0x2b,
-	0xd5, 0xa9, 0x1c, 0x80, 0x21, 0x18, 0x6f, 0xd2, 0xae, 0xab, 0xef, 0x35, 0xe5, 0xa9, 0x3a, 0x95,
-	0xe8, 0x00, 0xde, 0xf2, 0xa4, 0x91, 0x65, 0x35, 0xb7, 0x4c, 0x9e, 0x14, 0xca, 0x8b, 0x0f, 0x36,
-	0x86, 0x60, 0xdc, 0x99, 0xdc, 0x26, 0xde, 0x9d, 0x2c, 0xdd, 0xc9, 0xb3, 0xe0, 0x4e, 0xb7, 0xdd,
-	0x24, 0x75, 0x83, 0xcf, 0x7f, 0xcf, 0x8d, 0x3e, 0x02, 0xb8, 0x79, 0x50, 0x69, 0xcb, 0x11, 0x82,
-	0x57, 0x72, 0x9e, 0x79, 0xeb, 0xab, 0xd4, 0x9d, 0xd1, 0x23, 0x38, 0xf0, 0x82, 0xec, 0x5d, 0xc3,
-	0xb0, 0x4c, 0x5a, 0xa3, 0x04, 0x73, 0xdc, 0x86, 0xe3, 0xb6, 0x7d, 0xdf, 0x49, 0xbc, 0x72, 0xdd,
-	0xd7, 0xcd, 0xe0, 0x13, 0x08, 0x57, 0xc2, 0xb5, 0xfe, 0x17, 0x6e, 0x05, 0x1e, 0x7d, 0x06, 0xb0,
-	0xff, 0x42, 0x14, 0x53, 0x69, 0x8e, 0x95, 0x90, 0x53, 0x69, 0xad, 0xca, 0x13, 0x74, 0x1f, 0xf6,
-	0x33, 0x59, 0xa6, 0xac, 0xf4, 0x65, 0xb6, 0x12, 0xb5, 0xd7, 0x34, 0x02, 0xee, 0xcc, 0x09, 0xbc,
-	0x1e, 0x52, 0xaf, 0xd1, 0x3e, 0x70, 0xdf, 0xb7, 0x56, 0xf9, 0x87, 0xb0, 0xed, 0xd6, 0x2b, 0x07,
-	0xad, 0x61, 0x6b, 0xdc, 0x99, 0xdc, 0x21, 0xff, 0xbe, 0x71, 0xe2, 0xb6, 0xa4, 0x01, 0x1e, 0x7d,
-	0x05, 0xb0, 0xbd, 0xcf, 0x0d, 0xcf, 0x4a, 0xf4, 0x12, 0x76, 0x8d, 0xbf, 0x53, 0xe6, 0x51, 0x17,
-	0xad, 0x33, 0xb9, 0x7b, 0x99, 0xd2, 0xda, 0x0b, 0xa0, 0x5b, 0x66, 0xed, 0x41, 0xdc, 0x83, 0x3d,
-	0x61, 0xe4, 0x4c, 0xe6, 0x56, 0xf1, 0x39, 0x2b, 0xb8, 0x4d, 0x43, 0xf6, 0xee, 0x9f, 0xf2, 0x3e,
-	0xb7, 0x29, 0xa2, 0xb0, 0xb7, 0xdc, 0xd0, 0xeb, 0x2e, 0x37, 0xd8, 0xbd, 0xcc, 0xf7, 0xaf, 0x0f,
-	0x4b, 0xbb, 0x41, 0xc1, 0x7b, 0x97, 0x4f, 0x1f, 0x9f, 0x2d, 0x70, 0x74, 0xbe, 0xc0, 0xd1, 0xb7,
-	0x05, 0x8e, 0x2e, 0x16, 0x38, 0x7a, 0x5f, 0x63, 0xf0, 0xa5, 0xc6, 0xd1, 0x59, 0x8d, 0xc1, 0x79,
-	0x8d, 0xc1, 0xf7, 0x1a, 0x83, 0x9f, 0x35, 0x8e, 0x2e, 0x6a, 0x0c, 0x3e, 0xfd, 0xc0, 0xd1, 0x9b,
-	0xb6, 0xd7, 0x3e, 0x6c, 0xbb, 0x7b, 0x7d, 0xf0, 0x2b, 0x00, 0x00, 0xff, 0xff, 0x82, 0xc3, 0xab,
-	0x63, 0x5b, 0x03, 0x00, 0x00,
+	// 2817 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x19, 0x4d, 0x6f, 0x1b, 0xc7,
+	0x55, 0x2b, 0x4a, 0xb2, 0x35, 0x94, 0xf8, 0x31, 0xfa, 0x5a, 0xcb, 0x0e, 0xad, 0x30, 0x4d, 0xa3,
+	0x34, 0x0d, 0x95, 0x28, 0x4d, 0xe3, 0xa4, 0x49, 0x10, 0x8a, 0xa2, 0x1c, 0xc6, 0x94, 0xc8, 0x2c,
+	0x69, 0x1b, 0x2e, 0x5a, 0x4c, 0x47, 0xbb, 0x23, 0x6a, 0xa3, 0xe5, 0xee, 0x66, 0x76, 0x28, 0x8b,
+	0x06, 0x0a, 0xb4, 0xb7, 0x1e, 0x7b, 0xea, 0x6f, 0xe8, 0x31, 0x40, 0x0b, 0xf4, 0xd2, 0x1f, 0x90,
+	0x63, 0x80, 0x5e, 0x7a, 0xac, 0xd5, 0x4b, 0xd1, 0x53, 0x6e, 0xbd, 0x16, 0xf3, 0x66, 0x76, 0x49,
+	0x4a, 0xa2, 0x68, 0xb7, 0x27, 0xee, 0xbc, 0xcf, 0x79, 0xf3, 0xde, 0xbc, 0x8f, 0x21, 0x7a, 0xb3,
+	0xeb, 0x9e, 0x31, 0xbe, 0x45, 0x1d, 0x1a, 0x0a, 0xc6, 0xb7, 0xa2, 0x53, 0xdb, 0x16, 0xdc, 0xdb,
+	0xb2, 0x03, 0xff, 0xc8, 0xed, 0xe8, 0x9f, 0x52, 0xc8, 0x03, 0x11, 0xe0, 0x55, 0x4d, 0x54, 0xd2,
+	0x44, 0x25, 0x85, 0x5d, 0x5f, 0xee, 0x04, 0x9d, 0x00, 0x48, 0xb6, 0xe4, 0x97, 0xa2, 0x5e, 0x2f,
+	0x74, 0x82, 0xa0, 0xe3, 0xb1, 0x2d, 0x58, 0x1d, 0xf6, 0x8e, 0xb6, 0x9c, 0x1e, 0xa7, 0xc2, 0x0d,
+	0x7c, 0x85, 0x2f, 0xfe, 0x25, 0x8f, 0x16, 0xad, 0x9e, 0x2f, 0xdc, 0x2e, 0xab, 0x80, 0x1c, 0xbc,
+	0x89, 0x72, 0xf6, 0x31, 0xb3, 0x4f, 0x88, 0x4d, 0xed, 0x63, 0x46, 0x22, 0xf7, 0x19, 0x33, 0x8d,
+	0x0d, 0x63, 0x73, 0xd6, 0xca, 0x00, 0xbc, 0x22, 0xc1, 0x2d, 0xf7, 0x19, 0xc3, 0x5f, 0xa2, 0x35,
+	0x45, 0xc9, 0x59, 0xd4, 0xf3, 0x04, 0x61, 0x67, 0xa1, 0xab, 0x84, 0x9b, 0xd3, 0x1b, 0xc6, 0x66,
+	0x7a, 0xfb, 0x56, 0x49, 0x69, 0x2f, 0xc5, 0xda, 0x4b, 0xbb, 0x5a, 0xbb, 0xb5, 0x02, 0x9c, 0x16,
+	0x30, 0x56, 0x13, 0x3e, 0xa9, 0xfc, 0x29, 0xe5, 0xbe, 0xeb, 0x77, 0x88, 0x17, 0x74, 0x08, 0xa7,
+	0x82, 0x99, 0x29, 0xa5, 0x5c, 0xc3, 0xeb, 0x41, 0xc7, 0xa2, 0x82, 0xe1, 0x47, 0x08, 0xc3, 0x41,
+	0xb8, 0xa7, 0x8c, 0x1c, 0x51, 0xd7, 0x23, 0x41, 0xc8, 0x7c, 0x73, 0x06, 0xf4, 0x6e, 0x96, 0xae,
+	0x3e, 0xa3, 0x52, 0x59, 0x73, 0xec, 0x51, 0xd7, 0x6b, 0x84, 0xcc, 0xb7, 0x72, 0xf4, 0x02, 0x04,
+	0xfb, 0x68, 0x3d, 0x91, 0xcb, 0x59, 0x18, 0x70, 0x41, 0xc4, 0x31, 0x0f, 0x84, 0xf0, 0x5c, 0xbf,
+	0x63, 0xce, 0x82, 0xfc, 0x77, 0x26, 0xc9, 0xb7, 0x80, 0xb1, 0x9d, 0xf0, 0x59, 0x26, 0x1d, 0x83,
+	0xc1, 0x8f, 0xd1, 0xba, 0xcd, 0x99, 0xc3, 0x7c, 0xe1, 0x52, 0x8f, 0x70, 0xe6, 0x05, 0xd4, 0x21,
+	0xae, 0x2f, 0x18, 0x3f, 0xa5, 0x9e, 0x39, 0x37, 0xe9, 0x1c, 0xcd, 0x01, 0xb3, 0x05, 0xbc, 0x35,
+	0xcd, 0x8a, 0x7f, 0x82, 0x56, 0x05, 0xa7, 0x7e, 0xe4, 0x32, 0x5f, 0x10, 0xe5, 0x27, 0xc6, 0x79,
+	0xc0, 0x23, 0xf3, 0xc6, 0x46, 0x6a, 0x73, 0xde, 0x5a, 0x4e, 0xb0, 0x15, 0x89, 0xac, 0x02, 0x0e,
+	0x1f, 0xa2, 0x0d, 0x9f, 0x75, 0x28, 0x98, 0x3f, 0xce, 0xb9, 0x37, 0x27, 0x6d, 0xea, 0x95, 0x58,
+	0x44, 0xe5, 0x4a, 0x27, 0x7f, 0x82, 0xee, 0xf4, 0x22, 0x46, 0x1c, 0xe6, 0xf4, 0x42, 0xe2, 0x3a,
+	0x84, 0x46, 0xd2, 0x79, 0x0a, 0x49, 0x5c, 0xc7, 0x9c, 0xdf, 0x30, 0x36, 0x6f, 0x5a, 0x6b, 0xbd,
+	0x88, 0xed, 0x4a, 0x92, 0x9a, 0x53, 0x8e, 0x1a, 0x31, 0xbe, 0xe6, 0x48, 0xc3, 0x86, 0xc9, 0x89,
+	0x4f, 0xbb, 0x2c, 0x0a, 0xa9, 0xcd, 0x4c, 0xb4, 0x61, 0x48, 0xc3, 0x82, 0x01, 0xf1, 0x41, 0x8c,
+	0xc3, 0x9f, 0xa1, 0xcc, 0xd7, 0xbd, 0x40, 0x50, 0xe2, 0x30, 0xea, 0x78, 0xae, 0xcf, 0xcc, 0xf4,
+	0x24, 0x33, 0x16, 0x81, 0x61, 0x57, 0xd3, 0xe3, 0x8f, 0xd1, 0x6d, 0x25, 0x21, 0x09, 0x37, 0x12,
+	0xf8, 0x03, 0x71, 0x0b, 0x6a, 0xd7, 0x40, 0x12, 0x47, 0x53, 0xc3, 0x4f, 0xb8, 0x2b, 0xa8, 0x60,
+	0x07, 0x7e, 0xd4, 0xeb, 0x32, 0x4e, 0xba, 0x4c, 0x70, 0xd7, 0x8e, 0x48, 0x97, 0x9e, 0x91, 0x18,
+	0x18, 0x99, 0x8b, 0x10, 0xe7, 0xb7, 0x63, 0xc0, 0xbe, 0x22, 0xda, 0xa7, 0x67, 0x95, 0x98, 0x04,
+	0xef, 0xa3, 0x15, 0xc5, 0x4b, 0xe4, 0x85, 0x25, 0xd4, 0x73, 0x3b, 0x7e, 0x97, 0xf9, 0xc2, 0xcc,
+	0x4c, 0xb2, 0x65, 0x49, 0xf1, 0xb5, 0xdd, 0x2e, 0x2b, 0xc7, 0x5c, 0x78, 0x1b, 0xad, 0x50, 0xe7,
+	0xd4, 0x8d, 0x02, 0xde, 0x1f, 0x8d, 0x90, 0x2c, 0x44, 0xc8, 0x52, 0x8c, 0x1c, 0x0e, 0x90, 0x7d,
+	0x34, 0xcf, 0x7c, 0x27, 0x0c, 0x5c, 0x5f, 0x44, 0x66, 0x0e, 0xd4, 0x6e, 0x8d, 0xbb, 0x0e, 0x2d,
+	0xc6, 0x4f, 0x5d, 0x5b, 0x26, 0x16, 0xc1, 0x03, 0xaf, 0x1a, 0xb3, 0x59, 0x03, 0x09, 0xf8, 0x3e,
+	0xc2, 0xb6, 0x17, 0x44, 0x8c, 0x74, 0x38, 0xb5, 0x19, 0x09, 0x19, 0x77, 0x03, 0xc7, 0xcc, 0x4f,
+	0x32, 0x27, 0x07, 0x4c, 0xf7, 0x25, 0x4f, 0x13, 0x58, 0x64, 0x32, 0x52, 0xde, 0xb1, 0x03, 0xea,
+	0xb1, 0xc8, 0x96, 0x29, 0xe4, 0xa9, 0xeb, 0x3b, 0xc1, 0x53, 0x13, 0x4f, 0x4c, 0x46, 0xc0, 0x59,
+	0x49, 0x18, 0x1f, 0x03, 0x1f, 0xfe, 0x04, 0xdd, 0xa6, 0x9e, 0x17, 0x3c, 0x25, 0xac, 0x1b, 0x8a,
+	0x3e, 0x89, 0x94, 0x35, 0x44, 0x19, 0x17, 0x99, 0x4b, 0xe0, 0x70, 0x13, 0x48, 0xaa, 0x92, 0x62,
+	0x60, 0xae, 0xc4, 0x4b, 0x67, 0xe9, 0x04, 0x72, 0xe4, 0xf5, 0xa2, 0xe3, 0xc1, 0xa5, 0x5e, 0x9e,
+	0xe8, 0x2c, 0xc5, 0xb7, 0x27, 0xd9, 0x92, 0xfb, 0xfc, 0x2e, 0x5a, 0x91, 0xf1, 0xa2, 0x45, 0x1e,
+	0x52, 0x61, 0x1f, 0xab, 0xe4, 0xbc, 0x02, 0x71, 0x83, 0xbb, 0xf4, 0x4c, 0x25, 0x97, 0x1d, 0x89,
+	0x82, 0x04, 0xbd, 0x87, 0x16, 0x38, 0x13, 0xbc, 0x4f, 0xc2, 0xc0, 0x73, 0xed, 0xbe, 0xb9, 0x0a,
+	0x8a, 0x5f, 0x1b, 0xe7, 0x2e, 0x4b, 0xd2, 0x36, 0x81, 0xd4, 0x4a, 0xf3, 0xc1, 0x02, 0x37, 0x50,
+	0xd6, 0x76, 0xb9, 0xdd, 0x73, 0x05, 0x39, 0xe4, 0x8c, 0x9e, 0x30, 0x6e, 0xae, 0x81, 0xa8, 0x1f,
+	0x8e, 0x13, 0x55, 0x51, 0xe4, 0x3b, 0x8a, 0xda, 0xca, 0xd8, 0x23, 0x6b, 0xdc, 0x41, 0x4b, 0x3e,
+	0x13, 0x4f, 0x03, 0x7e, 0xa2, 0x2e, 0x93, 0xde, 0x9f, 0xb9, 0x61, 0x6c, 0x66, 0xb6, 0x3f, 0x18,
+	0xbb, 0xbf, 0xe1, 0x3a, 0x55, 0x3a, 0x50, 0x02, 0xe4, 0x55, 0xd3, 0x7b, 0xce, 0xfb, 0x17, 0x41,
+	0xf8, 0x57, 0xe8, 0xee, 0x05, 0xb7, 0x5d, 0x4a, 0xb1, 0xb7, 0x26, 0x79, 0xe3, 0x4e, 0x34, 0xe2,
+	0xd7, 0x0b, 0x69, 0xf6, 0x35, 0xb4, 0xc8, 0x7c, 0x7a, 0xe8, 0xc5, 0xd5, 0xc2, 0x5c, 0x87, 0xb0,
+	0x58, 0x50, 0x40, 0xe5, 0x11, 0xfc, 0x2a, 0xd2, 0x6b, 0x02, 0x91, 0x66, 0xde, 0x06, 0x9a, 0xb4,
+	0x82, 0x7d, 0x29, 0x41, 0x32, 0xab, 0x49, 0xf7, 0xc6, 0xbb, 0x0d, 0x79, 0x60, 0xb3, 0x28, 0x92,
+	0x97, 0xf1, 0x0e, 0xf8, 0x77, 0xb9, 0x4b, 0xcf, 0x74, 0x80, 0x35, 0x13, 0x1c, 0x7e, 0x07, 0x2d,
+	0x8f, 0xc4, 0x98, 0x34, 0x5f, 0xe6, 0x92, 0x57, 0x54, 0x4c, 0x0c, 0xc5, 0xd1, 0x63, 0x85, 0xc1,
+	0xf7, 0x90, 0xd9, 0x65, 0x82, 0x3a, 0x54, 0x50, 0x50, 0xc6, 0x38, 0x89, 0x6f, 0xa3, 0x59, 0x80,
+	0xfc, 0xb9, 0x1a, 0xe3, 0x5b, 0x80, 0x8e, 0xaf, 0xad, 0x34, 0x42, 0xb7, 0x04, 0xc7, 0x94, 0x3b,
+	0x91, 0x79, 0x17, 0x74, 0xa4, 0x01, 0xd6, 0x02, 0x10, 0x7e, 0x1f, 0xad, 0x0d, 0xc7, 0x68, 0xef,
+	0xe8, 0x88, 0x71, 0x72, 0xd8, 0x17, 0x2c, 0x32, 0x37, 0x36, 0x8c, 0xcd, 0x14, 0x58, 0xa1, 0xa3,
+	0x14, 0x90, 0x3b, 0x12, 0x57, 0x7c, 0x0f, 0xe5, 0x2f, 0x79, 0x13, 0x67, 0x51, 0x7a, 0xaf, 0x5c,
+	0xab, 0x93, 0x4a, 0xbd, 0xd1, 0xaa, 0xee, 0xe6, 0xa6, 0xf0, 0x22, 0x9a, 0x07, 0x40, 0xa3, 0x59,
+	0x3d, 0xc8, 0x19, 0xc5, 0xbf, 0x19, 0x28, 0x3d, 0x14, 0xb1, 0x72, 0x7b, 0x52, 0x37, 0x15, 0x42,
+	0x5e, 0xd7, 0x48, 0xf7, 0x2c, 0xe9, 0x2e, 0x3d, 0x2b, 0x6b, 0x10, 0xde, 0x41, 0x59, 0xd7, 0x77,
+	0xa1, 0xd0, 0x1e, 0x52, 0xfb, 0x24, 0x38, 0x3a, 0x9a, 0xdc, 0xa8, 0x64, 0x34, 0xc7, 0x8e, 0x62,
+	0xc0, 0x1f, 0x21, 0x29, 0x32, 0xe1, 0x4f, 0x4d, 0xe2, 0x47, 0x5d, 0x7a, 0x16, 0xf3, 0xbe, 0x8a,
+	0x16, 0x0e, 0x7b, 0x4e, 0x87, 0x09, 0x02, 0x48, 0xe8, 0x56, 0x0c, 0x2b, 0xad, 0x60, 0x96, 0x04,
+	0x15, 0x7f, 0x8d, 0x32, 0xa3, 0x77, 0x07, 0xbf, 0x85, 0xf2, 0xf2, 0x8e, 0xf4, 0x38, 0x93, 0x8d,
+	0x08, 0x8b, 0x8e, 0x03, 0xcf, 0xd1, 0xc6, 0xe5, 0x34, 0xa2, 0x1d, 0xc3, 0xf1, 0xa7, 0x68, 0x11,
+	0x0a, 0x53, 0xdc, 0xe5, 0x4d, 0xb6, 0x6f, 0x41, 0xd2, 0xc7, 0xab, 0xe2, 0x2f, 0xd1, 0xda, 0x98,
+	0xa4, 0x8d, 0x97, 0xd1, 0x2c, 0xd4, 0x08, 0xd0, 0x3d, 0x6f, 0xa9, 0x05, 0x5e, 0x45, 0x73, 0x3a,
+	0xee, 0xa7, 0x01, 0xac, 0x57, 0x92, 0x5a, 0x85, 0x7a, 0x4a, 0x51, 0xc3, 0xa2, 0xf8, 0x14, 0xe5,
+	0x2e, 0xb6, 0x60, 0x32, 0x84, 0xa1, 0xea, 0x40, 0xb3, 0x77, 0xc1, 0x44, 0xc3, 0xc2, 0x80, 0x93,
+	0x1d, 0xdf, 0xc0, 0xc8, 0x77, 0xd1, 0x9c, 0xce, 0xec, 0x13, 0xad, 0xd3, 0x84, 0xc5, 0x3f, 0x19,
+	0xc8, 0x1c, 0xd7, 0x9c, 0xe1, 0xd7, 0x51, 0x46, 0xbb, 0x93, 0x1c, 0x51, 0x5b, 0x04, 0x5c, 0xeb,
+	0x5e, 0xd4, 0xd0, 0x3d, 0x00, 0xca, 0x9b, 0xce, 0x99, 0x1d, 0x9c, 0x32, 0xde, 0x27, 0x91, 0x60,
+	0x21, 0x68, 0x37, 0xac, 0x85, 0x18, 0xd8, 0x12, 0x2c, 0xc4, 0x0f, 0x10, 0x62, 0x67, 0x32, 0xda,
+	0xdc, 0xc0, 0x8f, 0xcc, 0xd4, 0x46, 0x6a, 0x33, 0xbd, 0xfd, 0xd6, 0xb8, 0x84, 0x36, 0xd8, 0x43,
+	0x35, 0xe6, 0xb1, 0x86, 0xd8, 0x8b, 0xbf, 0x33, 0xd0, 0xd2, 0x15, 0x34, 0x32, 0x24, 0x06, 0x1d,
+	0x50, 0x28, 0x23, 0x9e, 0xfb, 0xda, 0x2d, 0xb9, 0x04, 0xd1, 0x54, 0x70, 0xe9, 0x09, 0x8f, 0x1e,
+	0x32, 0x4f, 0x3b, 0x48, 0x2d, 0x70, 0x09, 0x2d, 0xc1, 0x07, 0x39, 0xa5, 0x5e, 0x8f, 0x25, 0x42,
+	0x94, 0xb7, 0xf2, 0x80, 0x7a, 0x24, 0x31, 0x5a, 0x4a, 0xf1, 0x3f, 0x33, 0x68, 0x56, 0x25, 0x2a,
+	0x8c, 0x66, 0x64, 0xc7, 0xa5, 0xf5, 0xc1, 0x37, 0xfe, 0x00, 0x99, 0xca, 0x05, 0x2a, 0xbf, 0xe9,
+	0x06, 0x07, 0x3a, 0x33, 0xad, 0x76, 0x45, 0xe1, 0x41, 0x84, 0xea, 0x6c, 0x64, 0x6b, 0x86, 0x3f,
+	0x94, 0xc7, 0x95, 0x34, 0x96, 0x93, 0x2f, 0xd3, 0x80, 0x18, 0xdb, 0x68, 0x79, 0xb0, 0x22, 0xd2,
+	0x03, 0xdc, 0x75, 0x58, 0x64, 0xce, 0x6c, 0xa4, 0xae, 0x6b, 0xd1, 0x61, 0x07, 0xa5, 0x41, 0x37,
+	0xda, 0xd0, 0x8c, 0xd6, 0x12, 0xbb, 0x04, 0x8b, 0xf0, 0x43, 0x94, 0x95, 0xf5, 0xdd, 0x56, 0x4a,
+	0xba, 0x81, 0xc3, 0x60, 0x04, 0xc8, 0x6c, 0xff, 0xf8, 0x7a, 0xf9, 0xe5, 0x84, 0x69, 0x3f, 0x70,
+	0x98, 0x95, 0xa1, 0x23, 0x6b, 0xfc, 0x06, 0xca, 0x86, 0x9c, 0x1d, 0x31, 0x59, 0xc3, 0x69, 0x37,
+	0xe8, 0xf9, 0x02, 0x3a, 0xfd, 0x94, 0x95, 0x89, 0xc1, 0x65, 0x80, 0xe2, 0xcf, 0x11, 0x96, 0xe1,
+	0xe5, 0xdb, 0xae, 0xc7, 0x06, 0x25, 0xeb, 0xc6, 0xa4, 0x73, 0xca, 0x27, 0x4c, 0x71, 0x9d, 0x5a,
+	0x7f, 0x86, 0xf0, 0x65, 0xa3, 0xf1, 0x9b, 0x28, 0x97, 0x74, 0xa5, 0xa3, 0x81, 0x94, 0x8d, 0xe1,
+	0x71, 0x1c, 0x8d, 0xba, 0x6a, 0xfa, 0x25, 0x5c, 0x55, 0xfc, 0x19, 0xca, 0x8c, 0x1e, 0x08, 0xc6,
+	0x28, 0xd3, 0xb4, 0xaa, 0x95, 0x5a, 0xab, 0x4a, 0xac, 0xea, 0x7e, 0xa3, 0x5d, 0xcd, 0x4d, 0xe1,
+	0x15, 0x94, 0xdf, 0xa9, 0xb6, 0xda, 0xa4, 0xba, 0xb7, 0xd7, 0xb0, 0xda, 0xa4, 0xde, 0xa8, 0x94,
+	0xeb, 0x39, 0xa3, 0xf8, 0xef, 0x1c, 0xca, 0xdf, 0xb7, 0x43, 0x9d, 0x96, 0x5a, 0x4c, 0x08, 0x79,
+	0x67, 0x7f, 0x84, 0xf2, 0x5d, 0x16, 0x1d, 0x27, 0xf5, 0x72, 0x28, 0x24, 0xb3, 0x12, 0xa1, 0xc9,
+	0x21, 0xc8, 0x4a, 0x68, 0x49, 0x47, 0xe7, 0x08, 0xb5, 0x0a, 0xcc, 0xbc, 0x42, 0x0d, 0xd3, 0xbf,
+	0x8f, 0xe6, 0x20, 0x8c, 0xe3, 0xfb, 0xfb, 0xca, 0xb5, 0xbe, 0xb6, 0x34, 0xb1, 0x74, 0x2a, 0x67,
+	0x5f, 0xf7, 0x5c, 0xce, 0x1c, 0x02, 0x17, 0x48, 0xc5, 0xe2, 0xbc, 0x95, 0x89, 0xc1, 0x75, 0x80,
+	0x62, 0x12, 0x8f, 0x22, 0xf1, 0x11, 0xeb, 0x98, 0xba, 0x37, 0x4e, 0xcf, 0x25, 0xf3, 0x4b, 0xf1,
+	0x48, 0xd0, 0x0a, 0x7a, 0xdc, 0x66, 0x7a, 0x52, 0x89, 0x81, 0x98, 0xca, 0x9d, 0x40, 0x09, 0x4e,
+	0x34, 0xcc, 0xfd, 0x9f, 0x1a, 0x32, 0x4a, 0x60, 0xa2, 0xc2, 0x41, 0xab, 0x49, 0xe0, 0x50, 0x3f,
+	0xf0, 0xfb, 0x5d, 0xf7, 0x99, 0x8a, 0x0c, 0x15, 0x9c, 0x6f, 0x8f, 0xed, 0x0c, 0x35, 0x57, 0x79,
+	0x98, 0xc9, 0x5a, 0xb1, 0xaf, 0x02, 0xe3, 0x9f, 0xa2, 0x35, 0x79, 0x76, 0x2c, 0x12, 0x24, 0x12,
+	0xb2, 0x3c, 0x50, 0x21, 0xb8, 0x7b, 0xd8, 0x13, 0x0c, 0x86, 0xd0, 0x79, 0x6b, 0x45, 0xa3, 0x5b,
+	0x12, 0x5b, 0x8e, 0x91, 0xb8, 0x8d, 0x30, 0x0d, 0x5d, 0x72, 0xc2, 0xfa, 0xaa, 0xaa, 0x78, 0x6e,
+	0xd7, 0x15, 0x30, 0x57, 0xa6, 0xb7, 0xdf, 0x18, 0x3b, 0xbc, 0x87, 0xee, 0x03, 0xd6, 0x97, 0xa5,
+	0xa6, 0x2e, 0xc9, 0xad, 0x2c, 0x1d, 0x05, 0xc8, 0xdd, 0x84, 0x8c, 0x71, 0xe2, 0xc2, 0xc0, 0x2d,
+	0xfa, 0x43, 0xbb, 0x51, 0x93, 0xe7, 0x8a, 0x44, 0xd7, 0x34, 0x76, 0xb0, 0x9b, 0x1a, 0x5a, 0x3c,
+	0x66, 0xd4, 0x61, 0x3c, 0x0e, 0x0b, 0x35, 0x79, 0xfe, 0x60, 0xdc, 0x46, 0x3e, 0x07, 0x62, 0x15,
+	0x2c, 0xd6, 0xc2, 0xf1, 0xd0, 0x0a, 0x7f, 0x84, 0x6e, 0x45, 0xbd, 0x30, 0xe4, 0x2c, 0x8a, 0xe2,
+	0x2e, 0x6b, 0xb0, 0x89, 0x05, 0xd8, 0xc4, 0x5a, 0x4c, 0xa0, 0xea, 0xdc, 0x60, 0x1b, 0x6f, 0x23,
+	0x3c, 0x70, 0x99, 0x1c, 0x5a, 0x3c, 0x37, 0x12, 0xe6, 0x22, 0x84, 0x68, 0x3e, 0x39, 0xff, 0x18,
+	0x21, 0x8b, 0x4c, 0x42, 0xee, 0x30, 0xbf, 0x0f, 0xd4, 0x19, 0xa0, 0x4e, 0x72, 0xc6, 0xae, 0x86,
+	0x63, 0x1b, 0x65, 0x04, 0xa7, 0xae, 0x37, 0xb0, 0x31, 0x0b, 0x57, 0xe7, 0xe3, 0x17, 0x0f, 0xb8,
+	0xb6, 0xe2, 0x57, 0x86, 0x56, 0x7d, 0xc1, 0xfb, 0xd6, 0xa2, 0x18, 0x86, 0x81, 0xf1, 0x10, 0x8d,
+	0xd0, 0xe6, 0x42, 0x17, 0x3f, 0x30, 0x3e, 0xa7, 0x8d, 0x07, 0x82, 0xc7, 0x1a, 0x3f, 0x30, 0x7e,
+	0x17, 0x15, 0x1c, 0x16, 0x09, 0xd7, 0x57, 0x99, 0xfc, 0x0a, 0x01, 0x79, 0x10, 0x70, 0x67, 0x88,
+	0xea, 0xb2, 0x94, 0x3f, 0x18, 0xa8, 0x98, 0x1c, 0x0a, 0x67, 0x51, 0xe0, 0xf5, 0x40, 0x5c, 0xdc,
+	0xa0, 0xe9, 0x39, 0x06, 0xc3, 0x65, 0xab, 0xbd, 0xfc, 0x65, 0xb3, 0x12, 0x91, 0x7b, 0x4a, 0xa2,
+	0x9e, 0x6c, 0xee, 0xda, 0xd7, 0x13, 0xe0, 0xb6, 0x4c, 0x87, 0xea, 0x61, 0x80, 0x53, 0x3f, 0x3a,
+	0x0a, 0x78, 0x57, 0x0e, 0xa8, 0xa9, 0xeb, 0xe2, 0x5d, 0x95, 0xe1, 0x76, 0x4c, 0x6f, 0xe5, 0xba,
+	0xa3, 0x00, 0xc8, 0x68, 0x43, 0x6f, 0x53, 0x21, 0x15, 0xc7, 0x30, 0xbb, 0xce, 0x5b, 0x99, 0x01,
+	0xb8, 0x49, 0xc5, 0x31, 0xfe, 0x0a, 0xe5, 0xe5, 0x73, 0x1d, 0x93, 0x5e, 0x93, 0x2f, 0x45, 0x01,
+	0x17, 0x91, 0xb9, 0x02, 0xea, 0x3f, 0x7d, 0xf1, 0x53, 0xa8, 0x07, 0x1d, 0xf0, 0x7b, 0x55, 0x09,
+	0x80, 0x6f, 0x2b, 0xeb, 0x8d, 0x42, 0x71, 0x1f, 0xad, 0x8e, 0xde, 0xc2, 0x90, 0x07, 0x5f, 0x31,
+	0x5b, 0x44, 0xe6, 0x2a, 0x28, 0xac, 0xbc, 0xb8, 0xc2, 0xe6, 0xd0, 0x75, 0x6d, 0x6a, 0x29, 0x4a,
+	0xeb, 0x72, 0x78, 0x05, 0x4a, 0x06, 0x20, 0x8b, 0x6c, 0xea, 0xc9, 0x94, 0xa2, 0xde, 0x4b, 0xdc,
+	0xae, 0xdc, 0x14, 0xf5, 0x6d, 0x06, 0x13, 0xf1, 0x4d, 0x6b, 0x2d, 0x26, 0x80, 0x37, 0x93, 0x5a,
+	0x82, 0x5e, 0xff, 0x0c, 0xe1, 0xcb, 0x11, 0x8e, 0x73, 0x28, 0x75, 0xc2, 0xfa, 0xba, 0x70, 0xc9,
+	0x4f, 0xd9, 0xae, 0x41, 0x4b, 0x16, 0xb7, 0x6b, 0xb0, 0xf8, 0x68, 0xfa, 0x9e, 0xb1, 0xfe, 0x15,
+	0x5a, 0xbe, 0xea, 0x84, 0xae, 0x90, 0xf1, 0xf1, 0xb0, 0x8c, 0x6b, 0xa6, 0xf4, 0x51, 0x71, 0xc3,
+	0xba, 0xee, 0xa3, 0x5b, 0x63, 0x0f, 0xe7, 0x65, 0x36, 0x5d, 0xfc, 0x05, 0xca, 0x8c, 0x56, 0x12,
+	0xbc, 0x8c, 0x72, 0xbb, 0xd5, 0xbd, 0xf2, 0xc3, 0x7a, 0x9b, 0x54, 0x1a, 0x07, 0xad, 0x87, 0xfb,
+	0x55, 0x2b, 0x37, 0x85, 0xd3, 0xe8, 0x46, 0xb9, 0x59, 0x23, 0x0f, 0xaa, 0x4f, 0x72, 0x86, 0x24,
+	0x89, 0x51, 0xa4, 0x69, 0x35, 0xbe, 0xa8, 0x56, 0xda, 0xb9, 0x69, 0x9c, 0x47, 0x8b, 0xcd, 0x6a,
+	0xd5, 0x22, 0xb5, 0xdd, 0xea, 0x41, 0xbb, 0xd6, 0x7e, 0x92, 0x4b, 0x15, 0x1b, 0xe8, 0xee, 0x84,
+	0xab, 0x83, 0x6f, 0xa2, 0x99, 0xdd, 0xea, 0xc1, 0x13, 0x35, 0x3f, 0x96, 0x0f, 0x1a, 0x07, 0x4f,
+	0xf6, 0x1b, 0x0f, 0x5b, 0x39, 0x03, 0x2f, 0xa1, 0x6c, 0xb9, 0x5e, 0x6f, 0x3c, 0x26, 0x07, 0x0d,
+	0x62, 0x55, 0x9b, 0x0d, 0xab, 0x9d, 0x9b, 0x2e, 0xfe, 0xd9, 0x40, 0x99, 0xd1, 0x53, 0xc1, 0xb7,
+	0xd0, 0x4d, 0x19, 0xdb, 0x43, 0x0d, 0xc6, 0x0d, 0x2f, 0xe8, 0x40, 0xa3, 0xf0, 0x06, 0xca, 0xc6,
+	0x4d, 0x34, 0x77, 0xe5, 0x28, 0x1f, 0x99, 0xd3, 0xaa, 0xe2, 0xeb, 0x06, 0x5a, 0x43, 0xe5, 0xb3,
+	0x76, 0x5c, 0x8f, 0x62, 0x52, 0xdd, 0x6a, 0x67, 0x54, 0x91, 0x89, 0x49, 0xe5, 0x33, 0x80, 0xa4,
+	0x1c, 0xb4, 0xf7, 0x09, 0xfd, 0x8c, 0x7a, 0xdc, 0xa4, 0xa1, 0x9b, 0x3c, 0x86, 0xc6, 0x5c, 0xc5,
+	0x6f, 0x0c, 0x94, 0xbd, 0x70, 0x9d, 0xf1, 0x5d, 0x94, 0x1e, 0x6e, 0xc3, 0xd5, 0xd6, 0x51, 0x77,
+	0xd0, 0x7b, 0x6f, 0xa0, 0x74, 0x92, 0x2c, 0x18, 0xd7, 0xae, 0x1b, 0x06, 0xc9, 0xe1, 0x4e, 0x0f,
+	0x44, 0x29, 0x18, 0x75, 0xf4, 0x4a, 0x06, 0x40, 0xd7, 0xf5, 0xf5, 0xf8, 0x2a, 0x3f, 0x01, 0x42,
+	0xcf, 0xcc, 0x59, 0x0d, 0xa1, 0x67, 0xb8, 0x80, 0xd0, 0x61, 0xd0, 0xf3, 0x1d, 0xca, 0x5d, 0x16,
+	0x99, 0x73, 0x1b, 0xa9, 0x4d, 0xc3, 0x1a, 0x82, 0x14, 0xff, 0x6a, 0xa0, 0x85, 0xe1, 0x42, 0x27,
+	0x0f, 0x13, 0xaa, 0x12, 0x73, 0x88, 0x2a, 0x79, 0x72, 0x84, 0x87, 0xc3, 0xd4, 0x60, 0x45, 0x1d,
+	0xe1, 0xcf, 0xd1, 0x9c, 0xae, 0x31, 0xd3, 0xd7, 0xb7, 0xfa, 0xc3, 0xe2, 0x4b, 0xc3, 0x75, 0x45,
+	0xf3, 0xaf, 0x7f, 0x88, 0xd2, 0xff, 0xe3, 0x65, 0x2c, 0xfe, 0xd6, 0x40, 0xd9, 0x0b, 0x0d, 0x83,
+	0xec, 0x33, 0x75, 0x3b, 0x12, 0xc9, 0x87, 0x4c, 0x12, 0xc9, 0x26, 0x3c, 0x1e, 0x64, 0xf3, 0x31,
+	0xaa, 0xc9, 0x78, 0x0b, 0x10, 0x52, 0xfa, 0x61, 0x8f, 0x47, 0x6a, 0x74, 0x9e, 0xb5, 0xd4, 0x42,
+	0xc6, 0x8a, 0x7c, 0x60, 0x10, 0x9c, 0xda, 0x27, 0xcc, 0x91, 0x31, 0x13, 0xc5, 0x7f, 0x81, 0x74,
+	0xe9, 0x59, 0x5b, 0x81, 0x1f, 0xb0, 0x7e, 0x54, 0x7c, 0x0b, 0xad, 0x5c, 0xd9, 0x4d, 0xc9, 0x11,
+	0x2d, 0xa2, 0x9e, 0x88, 0x47, 0x34, 0xf9, 0x5d, 0xfc, 0x26, 0x85, 0xe6, 0x9a, 0x94, 0xd3, 0x6e,
+	0x84, 0xeb, 0x28, 0xc3, 0xd5, 0x53, 0x9a, 0x7e, 0x14, 0x03, 0xc2, 0xf4, 0xf6, 0xeb, 0x2f, 0xf4,
+	0xf0, 0x66, 0x2d, 0xf2, 0xe1, 0xe5, 0x55, 0x45, 0x62, 0xfa, 0xca, 0x22, 0x61, 0xa1, 0xec, 0xc5,
+	0x27, 0x54, 0xd5, 0x5f, 0xbf, 0xf9, 0xc2, 0x19, 0xdb, 0xca, 0x8c, 0xbe, 0xc5, 0xe1, 0x47, 0x23,
+	0xca, 0x61, 0x3e, 0x9b, 0x81, 0xe2, 0x3b, 0xb6, 0xff, 0x54, 0x67, 0x50, 0xaa, 0x24, 0x5c, 0x6a,
+	0x40, 0xb3, 0x47, 0xd6, 0xf2, 0x51, 0xe2, 0xe2, 0xbb, 0x21, 0x58, 0x36, 0x0b, 0x96, 0xe1, 0xd1,
+	0x5d, 0x48, 0xeb, 0x8a, 0x5f, 0xa2, 0xcc, 0xa8, 0x4c, 0x99, 0xaf, 0xbe, 0x68, 0x35, 0x0e, 0x64,
+	0x4e, 0x23, 0x7b, 0xb5, 0xba, 0x1c, 0x71, 0xd6, 0xd0, 0x52, 0xb9, 0xd9, 0xac, 0xd7, 0x2a, 0xe5,
+	0x76, 0xad, 0x71, 0x40, 0x74, 0x1e, 0x54, 0xc9, 0x68, 0xbf, 0xda, 0x2e, 0xef, 0x96, 0xdb, 0x65,
+	0xd2, 0xaa, 0x5a, 0x8f, 0xaa, 0x56, 0x6e, 0x7a, 0xe7, 0xde, 0xb7, 0xcf, 0x0b, 0x53, 0xdf, 0x3d,
+	0x2f, 0x4c, 0xfd, 0xfd, 0x79, 0x61, 0xea, 0xfb, 0xe7, 0x85, 0xa9, 0xdf, 0x9c, 0x17, 0x8c, 0x3f,
+	0x9e, 0x17, 0xa6, 0xbe, 0x3d, 0x2f, 0x18, 0xdf, 0x9d, 0x17, 0x8c, 0x7f, 0x9c, 0x17, 0x8c, 0x7f,
+	0x9d, 0x17, 0xa6, 0xbe, 0x3f, 0x2f, 0x18, 0xbf, 0xff, 0x67, 0x61, 0xea, 0xe7, 0x73, 0x6a, 0xb3,
+	0x87, 0x73, 0x30, 0x8f, 0xbd, 0xf7, 0xdf, 0x01, 0x00, 0x21, 0x7e, 0xce, 0x48, 0x57, 0x1c, 0x00,
+	0x00,
 }
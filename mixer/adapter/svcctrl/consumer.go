@@ -0,0 +1,148 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+)
+
+// resolveConsumerID derives the consumer ID of an operation from API key or peer identity according
+// to consumer source. Peer identities are resolved to the projects of peerIdentityProjects.
+func resolveConsumerID(source config.GcpServiceSetting_ConsumerSource, apiKey, peerIdentity, opName string,
+	peerIdentityProjects map[string]string, resolver consumerProjectIDResolver) (string, error) {
+	if source == config.PEER_IDENTITY {
+		if peerIdentity == "" {
+			return "", errors.New("peer identity is empty")
+		}
+		projectID, found := peerIdentityProjects[peerIdentity]
+		if !found {
+			return "", fmt.Errorf("peer identity %s has no project", peerIdentity)
+		}
+		return generateConsumerIDFromProject(projectID), nil
+	}
+
+	if apiKey == "" {
+		return "", errors.New("API key is empty")
+	}
+
+	consumerID := generateConsumerIDFromAPIKey(apiKey)
+	if source == config.CONSUMER_PROJECT {
+		return resolver.ResolveConsumerProjectID(consumerID, opName)
+	}
+	return consumerID, nil
+}
+
+// consumerSource returns the effective source of a consumer source, where DEFAULT_CONSUMER is API_KEY.
+func consumerSource(source config.GcpServiceSetting_ConsumerSource) config.GcpServiceSetting_ConsumerSource {
+	if source == config.DEFAULT_CONSUMER {
+		return config.API_KEY
+	}
+	return source
+}
+
+// compileConsumerPattern compiles a consumer pattern, which must match the whole consumer ID.
+func compileConsumerPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// consumerFilter decides whether consumers are allowed to call a service from allowlist and denylist
+// patterns.
+type consumerFilter struct {
+	allowlist []*regexp.Regexp
+	denylist  []*regexp.Regexp
+}
+
+// reject returns the reason to reject consumerID, or an empty string if it's allowed.
+func (f *consumerFilter) reject(consumerID string) string {
+	for _, pattern := range f.denylist {
+		if pattern.MatchString(consumerID) {
+			return rejectReasonDenied
+		}
+	}
+	if len(f.allowlist) == 0 {
+		return ""
+	}
+	for _, pattern := range f.allowlist {
+		if pattern.MatchString(consumerID) {
+			return ""
+		}
+	}
+	return rejectReasonNotAllowed
+}
+
+// newConsumerFilter creates consumerFilter from service config, returns nil if neither allowlist nor
+// denylist is configured.
+func newConsumerFilter(serviceConfig *config.GcpServiceSetting) (*consumerFilter, error) {
+	if len(serviceConfig.ConsumerAllowlist) == 0 && len(serviceConfig.ConsumerDenylist) == 0 {
+		return nil, nil
+	}
+	allowlist, err := compileConsumerPatterns(serviceConfig.ConsumerAllowlist)
+	if err != nil {
+		return nil, err
+	}
+	denylist, err := compileConsumerPatterns(serviceConfig.ConsumerDenylist)
+	if err != nil {
+		return nil, err
+	}
+	return &consumerFilter{allowlist, denylist}, nil
+}
+
+// rejectReasonMessage describes a reason of rejecting consumer in status messages.
+func rejectReasonMessage(reason string) string {
+	if reason == rejectReasonDenied {
+		return "denied"
+	}
+	return "not allowed"
+}
+
+func compileConsumerPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		regex, err := compileConsumerPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, regex)
+	}
+	return compiled, nil
+}
+
+// consumerAnonymizer hashes consumer identifiers with a salt. Identical identifiers are hashed to
+// identical values.
+type consumerAnonymizer struct {
+	salt []byte
+}
+
+// anonymize returns the salted hash of id.
+func (a *consumerAnonymizer) anonymize(id string) string {
+	mac := hmac.New(sha256.New, a.salt)
+	_, _ = mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newConsumerAnonymizer creates consumerAnonymizer from config, returns nil if it's not configured.
+func newConsumerAnonymizer(cfg *config.ConsumerAnonymization) *consumerAnonymizer {
+	if cfg == nil {
+		return nil
+	}
+	return &consumerAnonymizer{[]byte(cfg.Salt)}
+}
@@ -0,0 +1,145 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rpc "github.com/googleapis/googleapis/google/rpc"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+	"istio.io/istio/mixer/adapter/svcctrl/template/svcctrlreport"
+	"istio.io/istio/mixer/pkg/adapter"
+	"istio.io/istio/mixer/pkg/status"
+	"istio.io/istio/mixer/template/apikey"
+	"istio.io/istio/mixer/template/quota"
+)
+
+func TestConsumerFilter(t *testing.T) {
+	filter, err := newConsumerFilter(&config.GcpServiceSetting{
+		ConsumerAllowlist: []string{"project_number:1.*", "api_key:.*"},
+		ConsumerDenylist:  []string{"project_number:123", "api_key:bad_key"},
+	})
+	if err != nil {
+		t.Fatalf(`newConsumerFilter() failed with %v`, err)
+	}
+
+	testCases := []struct {
+		consumerID string
+		expected   string
+	}{
+		{"project_number:1", ""},
+		{"project_number:100", ""},
+		{"api_key:test_key", ""},
+		{"project_number:123", rejectReasonDenied},
+		{"api_key:bad_key", rejectReasonDenied},
+		{"project_number:2", rejectReasonNotAllowed},
+		// Patterns must match the whole consumer ID.
+		{"project_number:21", rejectReasonNotAllowed},
+		{"project:client", rejectReasonNotAllowed},
+	}
+	for _, tc := range testCases {
+		if actual := filter.reject(tc.consumerID); actual != tc.expected {
+			t.Errorf(`expect %s to be rejected with "%s", but get "%s"`, tc.consumerID, tc.expected, actual)
+		}
+	}
+
+	// Any consumer not denied is allowed without allowlist.
+	filter, _ = newConsumerFilter(&config.GcpServiceSetting{ConsumerDenylist: []string{"project_number:123"}})
+	if actual := filter.reject("project_number:2"); actual != "" {
+		t.Errorf(`expect consumer not in denylist to be allowed, but get "%s"`, actual)
+	}
+
+	if filter, _ := newConsumerFilter(&config.GcpServiceSetting{}); filter != nil {
+		t.Error(`expect nil consumerFilter when not configured`)
+	}
+	if _, err := newConsumerFilter(&config.GcpServiceSetting{ConsumerAllowlist: []string{"("}}); err == nil {
+		t.Error(`expect error on invalid pattern`)
+	}
+}
+
+func TestConsumerResolutionFailurePolicy(t *testing.T) {
+	testCases := []struct {
+		policy          config.GcpServiceSetting_ConsumerResolutionFailurePolicy
+		expectedAllowed bool
+		expectedReport  bool
+	}{
+		{config.DENY, false, true},
+		{config.ANONYMOUS, true, true},
+		{config.ALLOW_NO_REPORT, true, false},
+	}
+
+	for _, tc := range testCases {
+		checkTest := checkProcessorTestSetup(t)
+		checkTest.checkProc.serviceConfig.ConsumerResolutionFailurePolicy = tc.policy
+		checks := getCounterValue(unresolvedConsumerCount, gcpServiceName, methodCheck)
+		checkResult, err := checkTest.checkProc.ProcessCheck(context.Background(), &apikey.Instance{
+			ApiOperation: "echo",
+			Timestamp:    time.Now(),
+		})
+		if err != nil {
+			t.Fatalf(`ProcessCheck() failed with %v`, err)
+		}
+		if allowed := status.IsOK(checkResult.Status); allowed != tc.expectedAllowed {
+			t.Errorf(`expect check allowed to be %v with policy %v, but get %v`, tc.expectedAllowed, tc.policy, checkResult)
+		}
+		if !tc.expectedAllowed && checkResult.Status.Code != int32(rpc.INVALID_ARGUMENT) {
+			t.Errorf(`expect INVALID_ARGUMENT with policy %v, but get %v`, tc.policy, checkResult)
+		}
+		if checkTest.mockClient.checkRequest != nil {
+			t.Errorf(`expect no check request with policy %v`, tc.policy)
+		}
+		if actual := getCounterValue(unresolvedConsumerCount, gcpServiceName, methodCheck); actual != checks+1 {
+			t.Errorf(`expect %v unresolved check consumers, but get %v`, checks+1, actual)
+		}
+
+		quotaTest := quotaProcessorTestSetup(t)
+		quotaTest.quotaProc.serviceConfig.ConsumerResolutionFailurePolicy = tc.policy
+		quotaResult, err := quotaTest.quotaProc.ProcessQuota(context.Background(), &quota.Instance{
+			Name:       testQuotaName,
+			Dimensions: map[string]interface{}{apiOperationDimension: "echo"},
+		}, adapter.QuotaArgs{QuotaAmount: 10})
+		if err != nil {
+			t.Fatalf(`ProcessQuota() failed with %v`, err)
+		}
+		if allowed := status.IsOK(quotaResult.Status) && quotaResult.Amount == 10; allowed != tc.expectedAllowed {
+			t.Errorf(`expect quota allowed to be %v with policy %v, but get %v`, tc.expectedAllowed, tc.policy, quotaResult)
+		}
+		if quotaTest.mockClient.allocateQuotaRequest != nil {
+			t.Errorf(`expect no quota request with policy %v`, tc.policy)
+		}
+
+		reportTest := reportProcessorTestSetup(t)
+		reportTest.reportProc.serviceConfig.ConsumerResolutionFailurePolicy = tc.policy
+		reports := getCounterValue(unresolvedConsumerCount, gcpServiceName, methodReport)
+		instance := getTestReportInstance()
+		instance.ApiKey = ""
+		if err := reportTest.reportProc.ProcessReport(context.Background(),
+			[]*svcctrlreport.Instance{instance}); err != nil {
+			t.Fatalf(`ProcessReport() failed with %v`, err)
+		}
+		if reported := reportTest.mockClient.reportRequest != nil; reported != tc.expectedReport {
+			t.Errorf(`expect operation reported to be %v with policy %v`, tc.expectedReport, tc.policy)
+		}
+		if tc.expectedReport && reportTest.mockClient.reportRequest.Operations[0].ConsumerId != "" {
+			t.Errorf(`expect operation without consumer, but get %v`, reportTest.mockClient.reportRequest.Operations[0])
+		}
+		if actual := getCounterValue(unresolvedConsumerCount, gcpServiceName, methodReport); actual != reports+1 {
+			t.Errorf(`expect %v unresolved report consumers, but get %v`, reports+1, actual)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+)
+
+const (
+	// Results of calls counted per consumer.
+	consumerResultSuccess = "success"
+	consumerResultError   = "error"
+
+	// Consumer label of calls from consumers outside the top consumers.
+	consumerOther = "other"
+
+	// Number of hex digits of hashed API keys in consumer labels.
+	consumerKeyHashLength = 16
+
+	// Volumes of up to this many times maxConsumers consumers are tracked, so that consumers
+	// growing into the top consumers are noticed.
+	consumerCandidateFactor = 4
+)
+
+type (
+	consumerKey struct {
+		service  string
+		consumer string
+	}
+
+	// consumerMetrics counts check and quota results of the top maxConsumers consumers by call
+	// volume, calls of other consumers are counted as consumerOther. A consumer displaced from
+	// the top consumers keeps its series, which stop growing, so that counters never reset.
+	// Volumes are estimated with the space-saving algorithm over a bounded set of candidates.
+	consumerMetrics struct {
+		lock         sync.Mutex // guards fields below
+		maxConsumers int
+		volumes      map[consumerKey]int64
+		top          map[consumerKey]bool
+	}
+)
+
+// sharedConsumerMetrics is shared by all handlers, since they count into the same consumerCallCount.
+var sharedConsumerMetrics = newConsumerTracker()
+
+// record counts the result of a call from consumer.
+func (m *consumerMetrics) record(service, consumerID, method string, success bool) {
+	key := consumerKey{service, consumerMetricLabel(consumerID)}
+	result := consumerResultSuccess
+	if !success {
+		result = consumerResultError
+	}
+
+	m.lock.Lock()
+	volume := m.countCall(key)
+	for len(m.top) > m.maxConsumers {
+		lowest, _ := m.lowestTop()
+		delete(m.top, lowest)
+	}
+	if !m.top[key] {
+		if lowest, lowestVolume := m.lowestTop(); len(m.top) < m.maxConsumers || volume > lowestVolume {
+			if len(m.top) >= m.maxConsumers {
+				delete(m.top, lowest)
+			}
+			m.top[key] = true
+		}
+	}
+	label := key.consumer
+	if !m.top[key] {
+		label = consumerOther
+	}
+	m.lock.Unlock()
+	consumerCallCount.WithLabelValues(key.service, label, method, result).Inc()
+}
+
+// countCall counts a call of key and returns its estimated volume. Once candidates are full, the
+// candidate with the lowest volume is replaced and its volume inherited by key.
+func (m *consumerMetrics) countCall(key consumerKey) int64 {
+	if volume, found := m.volumes[key]; found {
+		m.volumes[key] = volume + 1
+		return volume + 1
+	}
+	var volume int64
+	if len(m.volumes) >= consumerCandidateFactor*m.maxConsumers {
+		var lowest consumerKey
+		volume = -1
+		for candidate, v := range m.volumes {
+			if volume < 0 || v < volume {
+				lowest, volume = candidate, v
+			}
+		}
+		delete(m.volumes, lowest)
+	}
+	m.volumes[key] = volume + 1
+	return volume + 1
+}
+
+// lowestTop returns the top consumer with the lowest volume.
+func (m *consumerMetrics) lowestTop() (consumerKey, int64) {
+	var lowest consumerKey
+	var lowestVolume int64 = -1
+	for key := range m.top {
+		if v := m.volumes[key]; lowestVolume < 0 || v < lowestVolume {
+			lowest, lowestVolume = key, v
+		}
+	}
+	return lowest, lowestVolume
+}
+
+// consumerMetricLabel returns the consumer ID with API key hashed, so that metrics never carry API keys.
+func consumerMetricLabel(consumerID string) string {
+	if !strings.HasPrefix(consumerID, apiKeyPrefix) {
+		return consumerID
+	}
+	hash := sha256.Sum256([]byte(strings.TrimPrefix(consumerID, apiKeyPrefix)))
+	return apiKeyPrefix + hex.EncodeToString(hash[:])[:consumerKeyHashLength]
+}
+
+// newConsumerTracker creates consumerMetrics tracking no consumers until maxConsumers is set.
+func newConsumerTracker() *consumerMetrics {
+	return &consumerMetrics{
+		volumes: make(map[consumerKey]int64),
+		top:     make(map[consumerKey]bool),
+	}
+}
+
+// newConsumerMetrics returns sharedConsumerMetrics tracking up to maxConsumers consumers, returns
+// nil if maxConsumers is not positive. The latest maxConsumers applies to all handlers.
+func newConsumerMetrics(maxConsumers int) *consumerMetrics {
+	if maxConsumers <= 0 {
+		return nil
+	}
+	sharedConsumerMetrics.lock.Lock()
+	sharedConsumerMetrics.maxConsumers = maxConsumers
+	sharedConsumerMetrics.lock.Unlock()
+	return sharedConsumerMetrics
+}
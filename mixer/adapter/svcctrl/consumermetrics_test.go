@@ -0,0 +1,151 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/pkg/adapter"
+	"istio.io/istio/mixer/template/apikey"
+)
+
+func TestConsumerMetrics(t *testing.T) {
+	service := "consumer-metrics.googleapis.com"
+	metrics := newConsumerTracker()
+	metrics.maxConsumers = 2
+	count := func(consumer, method, result string) float64 {
+		return getCounterValue(consumerCallCount, service, consumer, method, result)
+	}
+	before := map[string]float64{
+		"1":     count("project_number:1", methodCheck, consumerResultSuccess),
+		"1/err": count("project_number:1", methodCheck, consumerResultError),
+		"2":     count("project_number:2", methodQuota, consumerResultError),
+		"3":     count("project_number:3", methodCheck, consumerResultSuccess),
+		"other": count(consumerOther, methodCheck, consumerResultSuccess),
+	}
+
+	metrics.record(service, "project_number:1", methodCheck, true)
+	metrics.record(service, "project_number:1", methodCheck, false)
+	metrics.record(service, "project_number:1", methodCheck, true)
+	metrics.record(service, "project_number:2", methodQuota, false)
+	// Up to maxConsumers consumers are tracked, others are counted as consumerOther until
+	// they outgrow the smallest top consumer.
+	metrics.record(service, "project_number:3", methodCheck, true)
+	metrics.record(service, "project_number:3", methodCheck, true)
+	metrics.record(service, "project_number:2", methodCheck, true)
+
+	testCases := []struct {
+		name, consumer, method, result string
+		delta                          float64
+	}{
+		{"1", "project_number:1", methodCheck, consumerResultSuccess, 2},
+		{"1/err", "project_number:1", methodCheck, consumerResultError, 1},
+		{"2", "project_number:2", methodQuota, consumerResultError, 1},
+		{"3", "project_number:3", methodCheck, consumerResultSuccess, 1},
+		{"other", consumerOther, methodCheck, consumerResultSuccess, 2},
+	}
+	for _, tc := range testCases {
+		if actual := count(tc.consumer, tc.method, tc.result) - before[tc.name]; actual != tc.delta {
+			t.Errorf(`expect %v %s %s calls of %s, but get %v`, tc.delta, tc.method, tc.result, tc.consumer, actual)
+		}
+	}
+	if !metrics.top[consumerKey{service, "project_number:1"}] || !metrics.top[consumerKey{service, "project_number:3"}] {
+		t.Errorf(`expect consumers with the most calls to be tracked, but get %v`, metrics.top)
+	}
+
+	// Displaced consumers keep their series.
+	if !consumerCallCount.DeleteLabelValues(service, "project_number:2", methodQuota, consumerResultError) {
+		t.Error(`expect series of displaced consumer to be kept`)
+	}
+
+	if newConsumerMetrics(0) != nil {
+		t.Error(`expect nil consumerMetrics when not configured`)
+	}
+	if newConsumerMetrics(10) != newConsumerMetrics(20) || sharedConsumerMetrics.maxConsumers != 20 {
+		t.Error(`expect consumerMetrics to be shared by handlers`)
+	}
+}
+
+func TestConsumerMetricsCandidates(t *testing.T) {
+	metrics := newConsumerTracker()
+	metrics.maxConsumers = 1
+	for i := 0; i < 100; i++ {
+		metrics.record("candidates.googleapis.com", fmt.Sprintf("project_number:%d", i), methodCheck, true)
+	}
+	if len(metrics.volumes) != consumerCandidateFactor || len(metrics.top) != 1 {
+		t.Errorf(`expect bounded candidates, but get %d candidates and %d top consumers`,
+			len(metrics.volumes), len(metrics.top))
+	}
+}
+
+func TestConsumerMetricLabel(t *testing.T) {
+	label := consumerMetricLabel("api_key:test_key")
+	if !strings.HasPrefix(label, apiKeyPrefix) || strings.Contains(label, "test_key") ||
+		len(label) != len(apiKeyPrefix)+consumerKeyHashLength {
+		t.Errorf(`expect hashed API key, but get %v`, label)
+	}
+	if label != consumerMetricLabel("api_key:test_key") {
+		t.Error(`expect API key to be hashed consistently`)
+	}
+	if actual := consumerMetricLabel("project_number:1"); actual != "project_number:1" {
+		t.Errorf(`expect consumer project as is, but get %v`, actual)
+	}
+}
+
+func TestProcessCheckAndQuotaConsumerMetrics(t *testing.T) {
+	checkTest := checkProcessorTestSetup(t)
+	checkTest.checkProc.consumerMetrics = newConsumerMetrics(10)
+	checkTest.mockClient.setCheckResponse(&sc.CheckResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 200,
+		},
+		CheckErrors: []*sc.CheckError{
+			{
+				Code: "API_KEY_INVALID",
+			},
+		},
+	})
+	consumer := consumerMetricLabel("api_key:metrics_key")
+	denied := getCounterValue(consumerCallCount, gcpServiceName, consumer, methodCheck, consumerResultError)
+	if _, err := checkTest.checkProc.ProcessCheck(context.Background(), &apikey.Instance{
+		ApiKey:       "metrics_key",
+		ApiOperation: "echo",
+		Timestamp:    time.Now(),
+	}); err != nil {
+		t.Fatalf(`ProcessCheck() failed with %v`, err)
+	}
+	if actual := getCounterValue(consumerCallCount, gcpServiceName, consumer, methodCheck, consumerResultError); actual != denied+1 {
+		t.Errorf(`expect %v failed checks, but get %v`, denied+1, actual)
+	}
+
+	quotaTest := quotaProcessorTestSetup(t)
+	quotaTest.quotaProc.consumerMetrics = newConsumerMetrics(10)
+	consumer = consumerMetricLabel("api_key:test_key")
+	granted := getCounterValue(consumerCallCount, gcpServiceName, consumer, methodQuota, consumerResultSuccess)
+	if _, err := quotaTest.quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(),
+		adapter.QuotaArgs{QuotaAmount: 1}); err != nil {
+		t.Fatalf(`ProcessQuota() failed with %v`, err)
+	}
+	if actual := getCounterValue(consumerCallCount, gcpServiceName, consumer, methodQuota, consumerResultSuccess); actual != granted+1 {
+		t.Errorf(`expect %v granted quota calls, but get %v`, granted+1, actual)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+)
+
+const (
+	// Number of buckets a error rate window is divided into.
+	errorRateBuckets = 10
+	// Check results allowed by fail-open are only cached for a short time, so that
+	// checks resume as soon as Google ServiceControl recovers.
+	failOpenResultExpiration = time.Second
+)
+
+type (
+	rateBucket struct {
+		start  time.Time
+		total  int64
+		errors int64
+	}
+
+	// errorRateTracker tracks the error rate of calls within a sliding time window.
+	errorRateTracker struct {
+		bucketSize time.Duration
+		now        func() time.Time
+
+		lock    sync.Mutex // guards buckets
+		buckets [errorRateBuckets]rateBucket
+	}
+
+	// adaptiveFailOpen decides whether a check fails open based on recent error rate.
+	adaptiveFailOpen struct {
+		threshold float64
+		tracker   *errorRateTracker
+		rand      func() float64
+	}
+)
+
+// record records outcome of a call.
+func (t *errorRateTracker) record(failed bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	start := t.now().Truncate(t.bucketSize)
+	bucket := &t.buckets[(start.UnixNano()/int64(t.bucketSize))%errorRateBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = rateBucket{start: start}
+	}
+	bucket.total++
+	if failed {
+		bucket.errors++
+	}
+}
+
+// errorRate returns the error rate of calls within the window.
+func (t *errorRateTracker) errorRate() float64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	windowStart := t.now().Truncate(t.bucketSize).Add(-t.bucketSize * (errorRateBuckets - 1))
+	var total, errors int64
+	for _, bucket := range t.buckets {
+		if !bucket.start.Before(windowStart) {
+			total += bucket.total
+			errors += bucket.errors
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(errors) / float64(total)
+}
+
+// record records outcome of a check call.
+func (f *adaptiveFailOpen) record(failed bool) {
+	f.tracker.record(failed)
+}
+
+// shouldFailOpen returns true if a check should be allowed without calling Google ServiceControl.
+func (f *adaptiveFailOpen) shouldFailOpen() bool {
+	rate := f.tracker.errorRate()
+	return rate > f.threshold && f.rand() < rate
+}
+
+func newErrorRateTracker(window time.Duration) *errorRateTracker {
+	bucketSize := window / errorRateBuckets
+	if bucketSize <= 0 {
+		bucketSize = 1
+	}
+	return &errorRateTracker{
+		bucketSize: bucketSize,
+		now:        time.Now,
+	}
+}
+
+// newAdaptiveFailOpen creates adaptiveFailOpen from config, returns nil if it's not configured.
+func newAdaptiveFailOpen(cfg *config.AdaptiveFailOpen) *adaptiveFailOpen {
+	if cfg == nil {
+		return nil
+	}
+	return &adaptiveFailOpen{
+		threshold: cfg.ErrorRateThreshold,
+		tracker:   newErrorRateTracker(toDuration(cfg.Window)),
+		rand:      rand.Float64,
+	}
+}
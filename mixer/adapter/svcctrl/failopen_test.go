@@ -0,0 +1,172 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pbtypes "github.com/gogo/protobuf/types"
+	"google.golang.org/api/googleapi"
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+	"istio.io/istio/mixer/pkg/status"
+	"istio.io/istio/mixer/template/apikey"
+)
+
+func TestErrorRateTracker(t *testing.T) {
+	now := time.Now()
+	tracker := newErrorRateTracker(10 * time.Second)
+	tracker.now = func() time.Time { return now }
+
+	if rate := tracker.errorRate(); rate != 0 {
+		t.Errorf(`expect 0 error rate without calls, but get %v`, rate)
+	}
+
+	for i := 0; i < 4; i++ {
+		tracker.record(i%2 == 0)
+	}
+	if rate := tracker.errorRate(); rate != 0.5 {
+		t.Errorf(`expect 0.5 error rate, but get %v`, rate)
+	}
+
+	now = now.Add(5 * time.Second)
+	for i := 0; i < 4; i++ {
+		tracker.record(true)
+	}
+	if rate := tracker.errorRate(); rate != 0.75 {
+		t.Errorf(`expect 0.75 error rate, but get %v`, rate)
+	}
+
+	// Calls older than the window don't count.
+	now = now.Add(6 * time.Second)
+	if rate := tracker.errorRate(); rate != 1 {
+		t.Errorf(`expect 1 error rate, but get %v`, rate)
+	}
+	now = now.Add(5 * time.Second)
+	if rate := tracker.errorRate(); rate != 0 {
+		t.Errorf(`expect 0 error rate after window, but get %v`, rate)
+	}
+}
+
+func TestAdaptiveFailOpen(t *testing.T) {
+	now := time.Now()
+	failOpen := newAdaptiveFailOpen(&config.AdaptiveFailOpen{
+		ErrorRateThreshold: 0.5,
+		Window:             &pbtypes.Duration{Seconds: 10},
+	})
+	failOpen.tracker.now = func() time.Time { return now }
+	randValue := 0.0
+	failOpen.rand = func() float64 { return randValue }
+
+	// Error rate at the threshold never fails open.
+	failOpen.record(true)
+	failOpen.record(false)
+	if failOpen.shouldFailOpen() {
+		t.Error(`expect no fail open at threshold`)
+	}
+
+	// Error rate is 0.75, fails open with probability of 0.75.
+	failOpen.record(true)
+	failOpen.record(true)
+	randValue = 0.7
+	if !failOpen.shouldFailOpen() {
+		t.Error(`expect fail open when error rate is 0.75 and rand is 0.7`)
+	}
+	randValue = 0.8
+	if failOpen.shouldFailOpen() {
+		t.Error(`expect no fail open when error rate is 0.75 and rand is 0.8`)
+	}
+
+	if newAdaptiveFailOpen(nil) != nil {
+		t.Error(`expect nil adaptiveFailOpen when not configured`)
+	}
+}
+
+func TestProcessCheckAdaptiveFailOpen(t *testing.T) {
+	test := checkProcessorTestSetup(t)
+	now := time.Now()
+	failOpen := newAdaptiveFailOpen(&config.AdaptiveFailOpen{
+		ErrorRateThreshold: 0.5,
+		Window:             &pbtypes.Duration{Seconds: 10},
+	})
+	failOpen.tracker.now = func() time.Time { return now }
+	// Never fails open while recording failures.
+	randValue := 1.0
+	failOpen.rand = func() float64 { return randValue }
+	test.checkProc.failOpen = failOpen
+
+	instance := &apikey.Instance{
+		ApiKey:       "test_key",
+		ApiOperation: "echo",
+		Timestamp:    now,
+	}
+
+	// Service unavailable and transport errors are counted as failure.
+	test.mockClient.setCheckResponse(&sc.CheckResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 503,
+		},
+	})
+	for i := 0; i < 5; i++ {
+		result, err := test.checkProc.ProcessCheck(context.Background(), instance)
+		if err != nil {
+			t.Fatalf(`ProcessCheck() failed with %v`, err)
+		}
+		if status.IsOK(result.Status) {
+			t.Fatalf(`expect check to fail, but get %v`, result)
+		}
+	}
+	test.mockClient.setCheckResponse(nil)
+	for i := 0; i < 5; i++ {
+		if _, err := test.checkProc.ProcessCheck(context.Background(), instance); err != nil {
+			t.Fatalf(`ProcessCheck() failed with %v`, err)
+		}
+	}
+
+	// Error rate is 1, checks fail open without calling Google ServiceControl.
+	randValue = 0.9
+	test.mockClient.reset()
+	failOpens := getCounterValue(failOpenCount, gcpServiceName, methodCheck)
+	result, err := test.checkProc.ProcessCheck(context.Background(), instance)
+	if err != nil {
+		t.Fatalf(`ProcessCheck() failed with %v`, err)
+	}
+	if !status.IsOK(result.Status) || result.ValidDuration != failOpenResultExpiration {
+		t.Errorf(`expect check to fail open, but get %v`, result)
+	}
+	if actual := getCounterValue(failOpenCount, gcpServiceName, methodCheck); actual != failOpens+1 {
+		t.Errorf(`expect %v check fail-opens, but get %v`, failOpens+1, actual)
+	}
+	if test.mockClient.checkRequest != nil {
+		t.Errorf(`expect no check request when failing open, but get %v`, *test.mockClient.checkRequest)
+	}
+
+	// Once the window passes, checks go to Google ServiceControl again.
+	now = now.Add(10 * time.Second)
+	test.mockClient.setCheckResponse(&sc.CheckResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 200,
+		},
+	})
+	if _, err := test.checkProc.ProcessCheck(context.Background(), instance); err != nil {
+		t.Fatalf(`ProcessCheck() failed with %v`, err)
+	}
+	if test.mockClient.checkRequest == nil {
+		t.Error(`expect check request after error rate recovers`)
+	}
+}
@@ -16,8 +16,14 @@ package svcctrl
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"math"
+	"sync"
+	"time"
 
+	multierror "github.com/hashicorp/go-multierror"
 	sc "google.golang.org/api/servicecontrol/v1"
 
 	"istio.io/istio/mixer/adapter/svcctrl/config"
@@ -25,14 +31,23 @@ import (
 	"istio.io/istio/mixer/pkg/adapter"
 	"istio.io/istio/mixer/pkg/status"
 	"istio.io/istio/mixer/template/apikey"
+	"istio.io/istio/mixer/template/logentry"
 	"istio.io/istio/mixer/template/quota"
 )
 
+// Close waits for in-flight requests up to this period when it's not configured.
+const defaultCloseGracePeriod = 5 * time.Second
+
+// errShuttingDown is returned for requests arriving once the handler starts closing.
+var errShuttingDown = errors.New("svcctrl handler is shutting down")
+
 type (
 	serviceControlClient interface {
 		Check(googleServiceName string, request *sc.CheckRequest) (*sc.CheckResponse, error)
 		Report(googleServiceName string, request *sc.ReportRequest) (*sc.ReportResponse, error)
-		AllocateQuota(googleServiceName string, request *sc.AllocateQuotaRequest) (*sc.AllocateQuotaResponse, error)
+		// AllocateQuota is cancelled once ctx is done.
+		AllocateQuota(ctx context.Context, googleServiceName string,
+			request *sc.AllocateQuotaRequest) (*sc.AllocateQuotaResponse, error)
 	}
 
 	checkProcessor interface {
@@ -42,9 +57,16 @@ type (
 	reportProcessor interface {
 		io.Closer
 		ProcessReport(ctx context.Context, instances []*svcctrlreport.Instance) error
+		ProcessLogEntry(ctx context.Context, instances []*logentry.Instance) error
+	}
+
+	// reportFlusher is implemented by reportProcessors buffering operations.
+	reportFlusher interface {
+		flush() int
 	}
 
 	quotaProcessor interface {
+		io.Closer
 		ProcessQuota(ctx context.Context, instances *quota.Instance, args adapter.QuotaArgs) (adapter.QuotaResult, error)
 	}
 
@@ -57,13 +79,28 @@ type (
 	handlerContext struct {
 		env    adapter.Env
 		config *config.Params
-		// A map keyed by mesh service name to service config in adapter config
-		serviceConfigIndex map[string]*config.GcpServiceSetting
 
 		checkDataShape  map[string]*apikey.Type
 		reportDataShape map[string]*svcctrlreport.Type
 
 		client serviceControlClient
+
+		lock sync.RWMutex // guards services and serviceClients, which are swapped on reload
+		// Service configs in adapter config, keyed by mesh service name or pattern.
+		services *serviceIndex
+		// Clients of services with their own credential, keyed by mesh service name or pattern.
+		serviceClients map[string]serviceControlClient
+		// Content of the service_configs_path file services were loaded from.
+		serviceConfigsContent []byte
+
+		// Logger for warnings that may be emitted on every request.
+		warningLogger *rateLimitedLogger
+		// Nil when per-consumer metrics are disabled.
+		consumerMetrics *consumerMetrics
+		// Nil when check results are not cached.
+		checkCache *checkCache
+		// Flushes buffered report operations of all services, nil when reports aren't buffered.
+		reportFlushPool *reportFlushPool
 	}
 
 	handler struct {
@@ -71,53 +108,333 @@ type (
 		// TODO(manlinl): Switch to a LRU cache of serviceProcessor once Mixer includes destination.service in all
 		// instances by default. Then we can enable a single handler to server multiple services.
 		svcProc *serviceProcessor
+		// Nil unless requests are routed to the mesh services their instances name.
+		router *serviceRouter
+		// Maximum time Close waits for in-flight requests.
+		closeGracePeriod time.Duration
+
+		lock     sync.RWMutex // guards closing
+		closing  bool
+		inFlight sync.WaitGroup
 	}
 )
 
+// Close closes the report and quota processors.
+func (p *serviceProcessor) Close() error {
+	var result *multierror.Error
+	if err := p.reportProcessor.Close(); err != nil {
+		result = multierror.Append(result, err)
+	}
+	if p.quotaProcessor != nil {
+		if err := p.quotaProcessor.Close(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result.ErrorOrNil()
+}
+
+// flushReports sends operations buffered by the report processor, and returns the number of
+// operations sent.
+func (p *serviceProcessor) flushReports() int {
+	if f, ok := p.reportProcessor.(reportFlusher); ok {
+		return f.flush()
+	}
+	return 0
+}
+
+// serviceClient returns the client of a mesh service, which is the handler-level client unless
+// the service has its own credential.
+func (c *handlerContext) serviceClient(meshServiceName string) serviceControlClient {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.serviceClientLocked(meshServiceName)
+}
+
+func (c *handlerContext) serviceClientLocked(meshServiceName string) serviceControlClient {
+	if client, found := c.serviceClients[meshServiceName]; found {
+		return client
+	}
+	return c.client
+}
+
+// service returns the service config of a mesh service, and its client.
+func (c *handlerContext) service(meshServiceName string) (*config.GcpServiceSetting, serviceControlClient, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	setting, configuredName, found := c.services.lookup(meshServiceName)
+	if !found {
+		return nil, nil, fmt.Errorf("unknown mesh service %v", meshServiceName)
+	}
+	return setting, c.serviceClientLocked(configuredName), nil
+}
+
+// setServices swaps service configs and clients of services with their own credential.
+func (c *handlerContext) setServices(services *serviceIndex, serviceClients map[string]serviceControlClient) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.services = services
+	c.serviceClients = serviceClients
+}
+
 func newServiceProcessor(meshServiceName string, ctx *handlerContext) (*serviceProcessor, error) {
 	checkProc, err := newCheckProcessor(meshServiceName, ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var reportProc reportProcessor = passThroughProcessor{}
+	if ctx.config.RuntimeConfig.EnableReport {
+		if reportProc, err = newReportProcessor(meshServiceName, ctx, checkProc); err != nil {
+			return nil, err
+		}
+	}
+
+	var quotaProc quotaProcessor = passThroughProcessor{}
+	if ctx.config.RuntimeConfig.EnableQuota {
+		if quotaProc, err = newQuotaProcessor(meshServiceName, ctx, checkProc); err != nil {
+			return nil, err
+		}
+	}
+
 	return &serviceProcessor{
-		checkProcessor: checkProc,
+		checkProcessor:  checkProc,
+		reportProcessor: reportProc,
+		quotaProcessor:  quotaProc,
+	}, nil
+}
+
+// passThroughProcessor allows every check and quota request, and drops reports. It serves
+// handlers without service configs.
+type passThroughProcessor struct{}
+
+func (passThroughProcessor) ProcessCheck(ctx context.Context, instance *apikey.Instance) (adapter.CheckResult, error) {
+	return adapter.CheckResult{
+		Status:        status.OK,
+		ValidDuration: failOpenResultExpiration,
+		ValidUseCount: math.MaxInt32,
+	}, nil
+}
+
+func (passThroughProcessor) ProcessReport(ctx context.Context, instances []*svcctrlreport.Instance) error {
+	return nil
+}
+
+func (passThroughProcessor) ProcessLogEntry(ctx context.Context, instances []*logentry.Instance) error {
+	return nil
+}
+
+func (passThroughProcessor) ProcessQuota(ctx context.Context, instance *quota.Instance,
+	args adapter.QuotaArgs) (adapter.QuotaResult, error) {
+	return adapter.QuotaResult{
+		Status:        status.OK,
+		ValidDuration: failOpenResultExpiration,
+		Amount:        args.QuotaAmount,
 	}, nil
 }
 
+func (passThroughProcessor) Close() error {
+	return nil
+}
+
+// begin registers an in-flight request, returns errShuttingDown if the handler is closing.
+func (h *handler) begin() error {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	if h.closing {
+		return errShuttingDown
+	}
+	h.inFlight.Add(1)
+	return nil
+}
+
+// processor returns the processor of a mesh service, which is svcProc unless requests are routed.
+func (h *handler) processor(meshServiceName string) (*serviceProcessor, error) {
+	if h.router == nil {
+		return h.svcProc, nil
+	}
+	return h.router.processor(meshServiceName)
+}
+
+// route calls fn with the processor of each mesh service in names, and indexes of the names of
+// the service. All names are served by svcProc unless requests are routed.
+func (h *handler) route(names []string, fn func(proc *serviceProcessor, indexes []int) error) error {
+	if h.router == nil {
+		indexes := make([]int, len(names))
+		for i := range names {
+			indexes[i] = i
+		}
+		return fn(h.svcProc, indexes)
+	}
+	var order []string
+	groups := make(map[string][]int)
+	for i, name := range names {
+		if _, found := groups[name]; !found {
+			order = append(order, name)
+		}
+		groups[name] = append(groups[name], i)
+	}
+	var result *multierror.Error
+	for _, name := range order {
+		proc, err := h.router.processor(name)
+		if err == nil {
+			err = fn(proc, groups[name])
+		}
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result.ErrorOrNil()
+}
+
 // HandleApiKey handles apikey check.
 func (h *handler) HandleApiKey(ctx context.Context, instance *apikey.Instance) (adapter.CheckResult, error) {
-	result, err := h.svcProc.ProcessCheck(ctx, instance)
-	logger := h.ctx.env.Logger()
+	if err := h.begin(); err != nil {
+		return adapter.CheckResult{}, err
+	}
+	defer h.inFlight.Done()
+	var result adapter.CheckResult
+	proc, err := h.processor(instance.Api)
+	if err == nil {
+		result, err = proc.ProcessCheck(ctx, instance)
+	}
 	if err != nil {
-		logger.Errorf("svcctrl check failed: %v", err)
+		h.ctx.warningLogger.Errorf("svcctrl check failed: %v", err)
 	}
 	return result, err
 }
 
 // HandleSvcctrlReport handles reporting metrics and logs.
 func (h *handler) HandleSvcctrlReport(ctx context.Context, instances []*svcctrlreport.Instance) error {
-	return nil
+	if err := h.begin(); err != nil {
+		return err
+	}
+	defer h.inFlight.Done()
+	names := make([]string, len(instances))
+	for i, instance := range instances {
+		names[i] = instance.ApiService
+	}
+	err := h.route(names, func(proc *serviceProcessor, indexes []int) error {
+		routed := make([]*svcctrlreport.Instance, len(indexes))
+		for i, index := range indexes {
+			routed[i] = instances[index]
+		}
+		return proc.ProcessReport(ctx, routed)
+	})
+	if err != nil {
+		h.ctx.warningLogger.Errorf("svcctrl report failed: %v", err)
+	}
+	return err
+}
+
+// HandleLogEntry handles exporting log entries.
+func (h *handler) HandleLogEntry(ctx context.Context, instances []*logentry.Instance) error {
+	if err := h.begin(); err != nil {
+		return err
+	}
+	defer h.inFlight.Done()
+	names := make([]string, len(instances))
+	for i, instance := range instances {
+		names[i], _ = instance.Variables[apiServiceDimension].(string)
+	}
+	err := h.route(names, func(proc *serviceProcessor, indexes []int) error {
+		routed := make([]*logentry.Instance, len(indexes))
+		for i, index := range indexes {
+			routed[i] = instances[index]
+		}
+		return proc.ProcessLogEntry(ctx, routed)
+	})
+	if err != nil {
+		h.ctx.warningLogger.Errorf("svcctrl logentry failed: %v", err)
+	}
+	return err
 }
 
 // HandleQuota handles rate limiting quota.
 func (h *handler) HandleQuota(ctx context.Context, instance *quota.Instance,
 	args adapter.QuotaArgs) (adapter.QuotaResult, error) {
-	return adapter.QuotaResult{
-		Status: status.OK,
-	}, nil
-
+	if err := h.begin(); err != nil {
+		return adapter.QuotaResult{}, err
+	}
+	defer h.inFlight.Done()
+	var result adapter.QuotaResult
+	meshServiceName, _ := instance.Dimensions[apiServiceDimension].(string)
+	proc, err := h.processor(meshServiceName)
+	if err == nil {
+		result, err = proc.ProcessQuota(ctx, instance, args)
+	}
+	if err != nil {
+		h.ctx.warningLogger.Errorf("svcctrl quota failed: %v", err)
+	}
+	return result, err
 }
 
-// Close closes a serviceProcessor
+// Close rejects new requests, waits for in-flight requests up to the grace period, and closes
+// the serviceProcessor.
 func (h *handler) Close() error {
+	h.lock.Lock()
+	h.closing = true
+	h.lock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.inFlight.Wait()
+		close(done)
+	}()
+	timer := time.NewTimer(h.closeGracePeriod)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		h.ctx.warningLogger.Warningf("close svcctrl handler with requests in flight after %v", h.closeGracePeriod)
+	}
+	defer h.ctx.warningLogger.flush()
+	if h.ctx.reportFlushPool != nil {
+		// Processors flush their remaining operations themselves once they're closed.
+		defer h.ctx.reportFlushPool.close()
+	}
+	if h.router != nil {
+		return h.router.close()
+	}
 	return h.svcProc.Close()
 }
 
 func newHandler(ctx *handlerContext) (*handler, error) {
-	svcProc, err := newServiceProcessor(ctx.config.ServiceConfigs[0].MeshServiceName, ctx)
-	if err != nil {
-		return nil, err
+	closeGracePeriod := defaultCloseGracePeriod
+	if ctx.config.RuntimeConfig.CloseGracePeriod != nil {
+		closeGracePeriod = toDuration(ctx.config.RuntimeConfig.CloseGracePeriod)
+	}
+	if dynamicServiceRouting(ctx.config) {
+		router := newServiceRouter(ctx, closeGracePeriod)
+		if interval := ctx.config.RuntimeConfig.ServiceConfigsReloadInterval; interval != nil {
+			ctx.env.ScheduleDaemon(func() {
+				router.run(toDuration(interval))
+			})
+		}
+		return &handler{
+			ctx:              ctx,
+			router:           router,
+			closeGracePeriod: closeGracePeriod,
+		}, nil
 	}
-	return &handler{ctx, svcProc}, nil
+
+	var svcProc *serviceProcessor
+	if allowEmptyServiceConfigs(ctx.config) {
+		ctx.warningLogger.Warningf("svcctrl handler has no service configs, allow all requests")
+		svcProc = &serviceProcessor{
+			checkProcessor:  passThroughProcessor{},
+			reportProcessor: passThroughProcessor{},
+			quotaProcessor:  passThroughProcessor{},
+		}
+	} else {
+		var err error
+		svcProc, err = newServiceProcessor(ctx.config.ServiceConfigs[0].MeshServiceName, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &handler{
+		ctx:              ctx,
+		svcProc:          svcProc,
+		closeGracePeriod: closeGracePeriod,
+	}, nil
 }
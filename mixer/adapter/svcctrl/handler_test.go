@@ -17,14 +17,17 @@ package svcctrl
 import (
 	"context"
 	"errors"
+	"math"
 	"reflect"
 	"testing"
 	"time"
 
+	"istio.io/istio/mixer/adapter/svcctrl/template/svcctrlreport"
 	"istio.io/istio/mixer/pkg/adapter"
 	at "istio.io/istio/mixer/pkg/adapter/test"
 	"istio.io/istio/mixer/pkg/status"
 	"istio.io/istio/mixer/template/apikey"
+	"istio.io/istio/mixer/template/logentry"
 )
 
 type mockCheckProcessor struct {
@@ -38,6 +41,35 @@ func (p *mockCheckProcessor) ProcessCheck(ctx context.Context, instance *apikey.
 	return *p.result, nil
 }
 
+// blockingCheckProcessor blocks checks until unblocked.
+type blockingCheckProcessor struct {
+	started chan struct{}
+	unblock chan struct{}
+}
+
+func (p *blockingCheckProcessor) ProcessCheck(ctx context.Context, instance *apikey.Instance) (adapter.CheckResult, error) {
+	p.started <- struct{}{}
+	<-p.unblock
+	return adapter.CheckResult{Status: status.OK}, nil
+}
+
+type mockReportProcessor struct {
+	closed bool
+}
+
+func (p *mockReportProcessor) ProcessReport(ctx context.Context, instances []*svcctrlreport.Instance) error {
+	return nil
+}
+
+func (p *mockReportProcessor) ProcessLogEntry(ctx context.Context, instances []*logentry.Instance) error {
+	return nil
+}
+
+func (p *mockReportProcessor) Close() error {
+	p.closed = true
+	return nil
+}
+
 func TestHandleApiKey(t *testing.T) {
 	instance := apikey.Instance{
 		ApiOperation: "/echo",
@@ -48,7 +80,8 @@ func TestHandleApiKey(t *testing.T) {
 	mock := &mockCheckProcessor{}
 	h := handler{
 		ctx: &handlerContext{
-			env: at.NewEnv(t),
+			env:           at.NewEnv(t),
+			warningLogger: newRateLimitedLogger(at.NewEnv(t).Logger(), 0),
 		},
 		svcProc: &serviceProcessor{
 			checkProcessor: mock,
@@ -64,3 +97,166 @@ func TestHandleApiKey(t *testing.T) {
 		t.Errorf(`expect check result %v, but get %v`, *mock.result, result)
 	}
 }
+
+func TestCloseWaitsForInFlightRequests(t *testing.T) {
+	checkProc := &blockingCheckProcessor{
+		started: make(chan struct{}),
+		unblock: make(chan struct{}),
+	}
+	reportProc := &mockReportProcessor{}
+	h := &handler{
+		ctx: &handlerContext{
+			env:           at.NewEnv(t),
+			warningLogger: newRateLimitedLogger(at.NewEnv(t).Logger(), 0),
+		},
+		svcProc: &serviceProcessor{
+			checkProcessor:  checkProc,
+			reportProcessor: reportProc,
+		},
+		closeGracePeriod: time.Minute,
+	}
+
+	checkDone := make(chan error)
+	go func() {
+		_, err := h.HandleApiKey(context.Background(), &apikey.Instance{})
+		checkDone <- err
+	}()
+	<-checkProc.started
+
+	closeDone := make(chan error)
+	go func() {
+		closeDone <- h.Close()
+	}()
+
+	// Requests arriving once Close starts are rejected.
+	for {
+		if err := h.HandleSvcctrlReport(context.Background(), nil); err == errShuttingDown {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := h.HandleQuota(context.Background(), nil, adapter.QuotaArgs{}); err != errShuttingDown {
+		t.Errorf(`expect quota to be rejected while closing, but get %v`, err)
+	}
+
+	select {
+	case <-closeDone:
+		t.Fatal(`expect Close to wait for in-flight check`)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(checkProc.unblock)
+	if err := <-checkDone; err != nil {
+		t.Errorf(`expect in-flight check to finish, but get %v`, err)
+	}
+	if err := <-closeDone; err != nil {
+		t.Errorf(`Close() failed with %v`, err)
+	}
+	if !reportProc.closed {
+		t.Error(`expect serviceProcessor to be closed`)
+	}
+}
+
+func TestCloseGracePeriod(t *testing.T) {
+	checkProc := &blockingCheckProcessor{
+		started: make(chan struct{}),
+		unblock: make(chan struct{}),
+	}
+	defer close(checkProc.unblock)
+	reportProc := &mockReportProcessor{}
+	env := at.NewEnv(t)
+	h := &handler{
+		ctx: &handlerContext{
+			env:           env,
+			warningLogger: newRateLimitedLogger(env.Logger(), 0),
+		},
+		svcProc: &serviceProcessor{
+			checkProcessor:  checkProc,
+			reportProcessor: reportProc,
+		},
+		closeGracePeriod: 10 * time.Millisecond,
+	}
+
+	go func() {
+		_, _ = h.HandleApiKey(context.Background(), &apikey.Instance{})
+	}()
+	<-checkProc.started
+
+	if err := h.Close(); err != nil {
+		t.Errorf(`Close() failed with %v`, err)
+	}
+	if !reportProc.closed {
+		t.Error(`expect serviceProcessor to be closed once grace period passes`)
+	}
+	if logs := env.GetLogs(); len(logs) != 1 {
+		t.Errorf(`expect a warning of requests in flight, but get %v`, logs)
+	}
+}
+
+func TestHandlerWithoutServiceConfigs(t *testing.T) {
+	adapterCfg := getTestAdapterConfig()
+	adapterCfg.ServiceConfigs = nil
+	adapterCfg.RuntimeConfig.AllowEmptyServiceConfigs = true
+	mockClient := &mockSvcctrlClient{}
+	ctx, err := initializeHandlerContext(at.NewEnv(t), adapterCfg, mockClient)
+	if err != nil {
+		t.Fatalf(`initializeHandlerContext() failed with %v`, err)
+	}
+	h, err := newHandler(ctx)
+	if err != nil {
+		t.Fatalf(`newHandler() failed with %v`, err)
+	}
+
+	checkResult, err := h.HandleApiKey(context.Background(), &apikey.Instance{
+		ApiKey:       "test_key",
+		ApiOperation: "echo",
+		Timestamp:    time.Now(),
+	})
+	if err != nil || !status.IsOK(checkResult.Status) || checkResult.ValidUseCount != math.MaxInt32 {
+		t.Errorf(`expect check to be allowed, but get %v, %v`, checkResult, err)
+	}
+
+	quotaResult, err := h.HandleQuota(context.Background(), getTestQuotaInstance(),
+		adapter.QuotaArgs{QuotaAmount: 10})
+	if err != nil || !status.IsOK(quotaResult.Status) || quotaResult.Amount != 10 {
+		t.Errorf(`expect quota to be granted, but get %v, %v`, quotaResult, err)
+	}
+
+	if err := h.HandleSvcctrlReport(context.Background(), []*svcctrlreport.Instance{{}}); err != nil {
+		t.Errorf(`HandleSvcctrlReport() failed with %v`, err)
+	}
+	if err := h.HandleLogEntry(context.Background(), []*logentry.Instance{{}}); err != nil {
+		t.Errorf(`HandleLogEntry() failed with %v`, err)
+	}
+	if mockClient.checkRequest != nil || mockClient.allocateQuotaRequest != nil || mockClient.reportRequest != nil {
+		t.Error(`expect no request to ServiceControl without service configs`)
+	}
+	if err := h.Close(); err != nil {
+		t.Errorf(`Close() failed with %v`, err)
+	}
+}
+
+func TestNewServiceProcessorEnabled(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		adapterCfg := getTestAdapterConfig()
+		adapterCfg.RuntimeConfig.EnableReport = enabled
+		adapterCfg.RuntimeConfig.EnableQuota = enabled
+		ctx, err := initializeHandlerContext(at.NewEnv(t), adapterCfg, &mockSvcctrlClient{})
+		if err != nil {
+			t.Fatalf(`initializeHandlerContext() failed with %v`, err)
+		}
+		proc, err := newServiceProcessor("service_a", ctx)
+		if err != nil {
+			t.Fatalf(`newServiceProcessor() failed with %v`, err)
+		}
+		if _, sent := proc.reportProcessor.(*reportImpl); sent != enabled {
+			t.Errorf(`expect reports sent %v, but get %T`, enabled, proc.reportProcessor)
+		}
+		if _, allocated := proc.quotaProcessor.(*quotaImpl); allocated != enabled {
+			t.Errorf(`expect quotas allocated %v, but get %T`, enabled, proc.quotaProcessor)
+		}
+		if err := proc.Close(); err != nil {
+			t.Errorf(`Close() failed with %v`, err)
+		}
+	}
+}
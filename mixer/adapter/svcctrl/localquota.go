@@ -0,0 +1,202 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"sync"
+	"time"
+
+	rpc "github.com/googleapis/googleapis/google/rpc"
+)
+
+const (
+	// Defaults of local quota settings when they're not configured.
+	defaultQuotaPrefetchAmount    = 100
+	defaultQuotaReconcileInterval = time.Second
+
+	// Local token buckets unused for this many reconcile intervals are dropped.
+	idleQuotaBucketIntervals = 60
+)
+
+type (
+	localQuotaKey struct {
+		consumerID string
+		opName     string
+	}
+
+	// refillFunc allocates up to amount tokens from Google ServiceControl, returns the granted
+	// tokens, and whether the quota is short of the amount.
+	refillFunc func(amount int64) (granted int64, exhausted bool, err error)
+
+	// localQuotaBucket holds tokens allocated for a consumer and operation.
+	localQuotaBucket struct {
+		refill refillFunc
+		tokens int64
+		// Whether the last refill was short of quota, tokens aren't refilled until the next
+		// reconcile interval.
+		exhausted bool
+		// Error of the last refill.
+		err error
+		// Closed once the refill in flight completes, nil if none is in flight.
+		refilling chan struct{}
+		lastUsed  time.Time
+	}
+
+	// localQuotaPool grants quota of a quota config from local token buckets, and refills
+	// buckets by allocating tokens in batches in the background.
+	localQuotaPool struct {
+		prefetchAmount    int64
+		reconcileInterval time.Duration
+		now               func() time.Time
+		schedule          func(func())
+
+		lock    sync.Mutex // guards buckets and their fields
+		buckets map[localQuotaKey]*localQuotaBucket
+
+		stop chan struct{}
+		done chan struct{}
+	}
+
+	// quotaAllocateError is an allocate error other than lack of quota.
+	quotaAllocateError struct {
+		code    rpc.Code
+		message string
+	}
+)
+
+func (e *quotaAllocateError) Error() string {
+	return e.message
+}
+
+// allocate grants up to amount tokens of key, or none unless bestEffort if fewer are left. A
+// call finding the bucket short of tokens waits for it to be refilled with refill.
+func (p *localQuotaPool) allocate(key localQuotaKey, amount int64, bestEffort bool,
+	refill refillFunc) (int64, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	bucket, found := p.buckets[key]
+	if !found {
+		bucket = &localQuotaBucket{refill: refill}
+		p.buckets[key] = bucket
+	}
+	bucket.lastUsed = p.now()
+
+	if bucket.tokens < amount && !bucket.exhausted {
+		done := p.startRefill(bucket, amount-bucket.tokens+p.prefetchAmount)
+		p.lock.Unlock()
+		<-done
+		p.lock.Lock()
+		if bucket.err != nil {
+			return 0, bucket.err
+		}
+	}
+
+	granted := amount
+	if bucket.tokens < amount {
+		granted = 0
+		if bestEffort {
+			granted = bucket.tokens
+		}
+	}
+	bucket.tokens -= granted
+	if !bucket.exhausted && bucket.tokens < p.prefetchAmount/2 {
+		p.startRefill(bucket, p.prefetchAmount)
+	}
+	return granted, nil
+}
+
+// startRefill starts refilling bucket with amount tokens in the background unless a refill is
+// in flight, and returns a channel closed once the refill in flight completes. It must be called
+// with lock held.
+func (p *localQuotaPool) startRefill(bucket *localQuotaBucket, amount int64) <-chan struct{} {
+	if bucket.refilling != nil {
+		return bucket.refilling
+	}
+	done := make(chan struct{})
+	bucket.refilling = done
+	p.schedule(func() {
+		granted, exhausted, err := bucket.refill(amount)
+		p.lock.Lock()
+		bucket.tokens += granted
+		bucket.exhausted = err == nil && exhausted
+		bucket.err = err
+		bucket.refilling = nil
+		p.lock.Unlock()
+		close(done)
+	})
+	return done
+}
+
+// reconcile refills buckets short of quota, and drops buckets unused for idleQuotaBucketIntervals.
+func (p *localQuotaPool) reconcile() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	now := p.now()
+	for key, bucket := range p.buckets {
+		if bucket.refilling != nil {
+			continue
+		}
+		if now.Sub(bucket.lastUsed) >= idleQuotaBucketIntervals*p.reconcileInterval {
+			delete(p.buckets, key)
+			continue
+		}
+		if bucket.exhausted {
+			p.startRefill(bucket, p.prefetchAmount)
+		}
+	}
+}
+
+// run reconciles buckets on the reconcile interval until the pool is closed.
+func (p *localQuotaPool) run() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.reconcileInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.reconcile()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// close stops reconciling buckets.
+func (p *localQuotaPool) close() {
+	close(p.stop)
+	<-p.done
+}
+
+// newLocalQuotaPool creates localQuotaPool, running refills with schedule. prefetchAmount and
+// reconcileInterval default to defaultQuotaPrefetchAmount and defaultQuotaReconcileInterval if
+// they're not positive.
+func newLocalQuotaPool(prefetchAmount int64, reconcileInterval time.Duration,
+	schedule func(func())) *localQuotaPool {
+	if prefetchAmount <= 0 {
+		prefetchAmount = defaultQuotaPrefetchAmount
+	}
+	if reconcileInterval <= 0 {
+		reconcileInterval = defaultQuotaReconcileInterval
+	}
+	return &localQuotaPool{
+		prefetchAmount:    prefetchAmount,
+		reconcileInterval: reconcileInterval,
+		now:               time.Now,
+		schedule:          schedule,
+		buckets:           make(map[localQuotaKey]*localQuotaBucket),
+		stop:              make(chan struct{}),
+		done:              make(chan struct{}),
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	rpc "github.com/googleapis/googleapis/google/rpc"
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+	"istio.io/istio/mixer/pkg/adapter"
+	at "istio.io/istio/mixer/pkg/adapter/test"
+)
+
+// mockRefill grants refills from a fixed amount of available quota, and records requested amounts.
+type mockRefill struct {
+	lock      sync.Mutex // guards fields below
+	available int64
+	requested []int64
+	err       error
+}
+
+func (m *mockRefill) refill(amount int64) (int64, bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.requested = append(m.requested, amount)
+	if m.err != nil {
+		return 0, false, m.err
+	}
+	granted := amount
+	if m.available < amount {
+		granted = m.available
+	}
+	m.available -= granted
+	return granted, granted < amount, nil
+}
+
+func (m *mockRefill) setAvailable(available int64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.available = available
+}
+
+func (m *mockRefill) getRequested() []int64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]int64(nil), m.requested...)
+}
+
+// waitForRefill waits for the refill in flight of key if any.
+func waitForRefill(pool *localQuotaPool, key localQuotaKey) {
+	pool.lock.Lock()
+	var done chan struct{}
+	if bucket, found := pool.buckets[key]; found {
+		done = bucket.refilling
+	}
+	pool.lock.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+func TestLocalQuotaPool(t *testing.T) {
+	pool := newLocalQuotaPool(10, time.Hour, func(refill func()) { go refill() })
+	key := localQuotaKey{"api_key:test_key", "echo"}
+	refill := &mockRefill{available: 100}
+
+	// A cold bucket is refilled with the requested amount plus the prefetch amount.
+	if granted, err := pool.allocate(key, 3, false, refill.refill); err != nil || granted != 3 {
+		t.Fatalf(`expect 3 granted, but get %v, %v`, granted, err)
+	}
+	// Allocations are granted locally, and the bucket is refilled in the background once it's
+	// below half of the prefetch amount.
+	if granted, err := pool.allocate(key, 6, false, refill.refill); err != nil || granted != 6 {
+		t.Fatalf(`expect 6 granted, but get %v, %v`, granted, err)
+	}
+	waitForRefill(pool, key)
+	if requested := refill.getRequested(); !reflect.DeepEqual(requested, []int64{13, 10}) {
+		t.Errorf(`expect refills of [13 10], but get %v`, requested)
+	}
+
+	// A bucket short of quota denies allocations it can't cover, and grants what's left to best
+	// effort allocations without refilling.
+	refill.setAvailable(2)
+	if granted, err := pool.allocate(key, 20, false, refill.refill); err != nil || granted != 0 {
+		t.Errorf(`expect none granted from an exhausted quota, but get %v, %v`, granted, err)
+	}
+	if granted, err := pool.allocate(key, 20, true, refill.refill); err != nil || granted != 16 {
+		t.Errorf(`expect 16 granted to best effort allocation, but get %v, %v`, granted, err)
+	}
+	if requested := refill.getRequested(); len(requested) != 3 {
+		t.Errorf(`expect no refill of an exhausted bucket, but get %v`, requested)
+	}
+
+	// Exhausted buckets are refilled on reconcile.
+	refill.setAvailable(100)
+	pool.reconcile()
+	waitForRefill(pool, key)
+	if granted, err := pool.allocate(key, 10, false, refill.refill); err != nil || granted != 10 {
+		t.Errorf(`expect 10 granted after reconcile, but get %v, %v`, granted, err)
+	}
+	waitForRefill(pool, key)
+
+	// Idle buckets are dropped on reconcile.
+	now := time.Now()
+	pool.now = func() time.Time { return now.Add(idleQuotaBucketIntervals * time.Hour) }
+	pool.reconcile()
+	if len(pool.buckets) != 0 {
+		t.Errorf(`expect idle bucket to be dropped, but get %v`, pool.buckets)
+	}
+
+	// Refill errors are returned to allocations waiting for the refill.
+	failed := &mockRefill{err: &quotaAllocateError{rpc.PERMISSION_DENIED, "denied"}}
+	if _, err := pool.allocate(localQuotaKey{"api_key:other_key", "echo"}, 1, false, failed.refill); err == nil ||
+		err.(*quotaAllocateError).code != rpc.PERMISSION_DENIED {
+		t.Errorf(`expect refill error, but get %v`, err)
+	}
+
+	go pool.run()
+	pool.close()
+}
+
+func TestProcessQuotaLocal(t *testing.T) {
+	test := quotaProcessorTestSetup(t)
+	quotaCfg := test.testConfig.ServiceConfigs[0].Quotas[0]
+	quotaCfg.AllocationMode = config.BEST_EFFORT_LOCAL
+	quotaCfg.PrefetchAmount = 10
+	ctx, err := initializeHandlerContext(at.NewEnv(t), &test.testConfig, test.mockClient)
+	if err != nil {
+		t.Fatalf(`fail to initialize handleContext %v`, err)
+	}
+	quotaProc, err := newQuotaProcessor(meshServiceName, ctx, &mockConsumerProjectIDResolver{})
+	if err != nil {
+		t.Fatalf(`fail to create test quotaProcessor %v`, err)
+	}
+	defer func() {
+		if err := quotaProc.Close(); err != nil {
+			t.Errorf(`Close() failed with %v`, err)
+		}
+	}()
+
+	test.mockClient.setQuotaAllocateRespone(&sc.AllocateQuotaResponse{})
+	result, err := quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(),
+		adapter.QuotaArgs{QuotaAmount: 2})
+	if err != nil || result.Status.Code != int32(rpc.OK) || result.Amount != 2 {
+		t.Fatalf(`expect 2 granted, but get %v, %v`, result, err)
+	}
+	op := test.mockClient.allocateQuotaRequest.AllocateOperation
+	if op.QuotaMode != quotaModeBestEffort || *op.QuotaMetrics[0].MetricValues[0].Int64Value != 12 {
+		t.Errorf(`expect best effort refill of 12, but get %v`, *op)
+	}
+
+	// Allocations covered by the local bucket don't call Google ServiceControl.
+	test.mockClient.reset()
+	result, err = quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(),
+		adapter.QuotaArgs{QuotaAmount: 2})
+	if err != nil || result.Status.Code != int32(rpc.OK) || result.Amount != 2 {
+		t.Fatalf(`expect 2 granted, but get %v, %v`, result, err)
+	}
+	if test.mockClient.allocateQuotaRequest != nil {
+		t.Errorf(`expect allocation to be granted locally, but get %v`, *test.mockClient.allocateQuotaRequest)
+	}
+
+	test.mockClient.setQuotaAllocateRespone(&sc.AllocateQuotaResponse{
+		AllocateErrors: []*sc.QuotaError{{Code: "RESOURCE_EXHAUSTED"}},
+	})
+	result, err = quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(),
+		adapter.QuotaArgs{QuotaAmount: 20})
+	if err != nil || result.Status.Code != int32(rpc.RESOURCE_EXHAUSTED) || result.Amount != 0 {
+		t.Errorf(`expect RESOURCE_EXHAUSTED with no quota granted, but get %v, %v`, result, err)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pborman/uuid"
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+	"istio.io/istio/mixer/template/logentry"
+)
+
+const logSeverityDefault = "DEFAULT"
+
+// Google ServiceControl log severities keyed by upper case logentry severity, which also accepts
+// a few common aliases.
+var logSeverities = map[string]string{
+	"DEFAULT":   logSeverityDefault,
+	"DEBUG":     "DEBUG",
+	"INFO":      "INFO",
+	"NOTICE":    "NOTICE",
+	"WARN":      "WARNING",
+	"WARNING":   "WARNING",
+	"ERROR":     "ERROR",
+	"CRITICAL":  "CRITICAL",
+	"FATAL":     "CRITICAL",
+	"ALERT":     "ALERT",
+	"EMERGENCY": "EMERGENCY",
+}
+
+// logEntryBuilder builds Google ServiceControl operations from logentry instances.
+type logEntryBuilder struct {
+	export *config.LogEntryExport
+	// Replaces the API key in labels and payload with its hash if set. The consumer keeps
+	// the API key, so that Google ServiceControl can attribute the log entry.
+	anonymize func(apiKey string) string
+}
+
+// build builds an operation of the log entry of instance.
+func (b *logEntryBuilder) build(instance *logentry.Instance) (*sc.Operation, error) {
+	labelVariables := make(map[string]bool, len(b.export.LabelVariables))
+	for _, variable := range b.export.LabelVariables {
+		labelVariables[variable] = true
+	}
+
+	apiKey, _ := instance.Variables[b.export.ApiKeyVariable].(string)
+	labels := make(map[string]string)
+	payload := make(map[string]interface{})
+	for variable, value := range instance.Variables {
+		value = logEntryValue(value)
+		if variable == b.export.ApiKeyVariable && apiKey != "" {
+			value = b.anonymize(apiKey)
+		}
+		if labelVariables[variable] {
+			labels[variable] = fmt.Sprint(value)
+		} else {
+			payload[variable] = value
+		}
+	}
+	structPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("fail to encode payload of log entry %s: %v", instance.Name, err)
+	}
+
+	timestamp := instance.Timestamp.UTC().Format(time.RFC3339Nano)
+	op := &sc.Operation{
+		OperationId:   uuid.New(),
+		OperationName: b.export.LogName,
+		StartTime:     timestamp,
+		EndTime:       timestamp,
+		LogEntries: []*sc.LogEntry{
+			{
+				Name:          b.export.LogName,
+				Timestamp:     timestamp,
+				Severity:      logSeverity(instance.Severity),
+				Labels:        labels,
+				StructPayload: structPayload,
+			},
+		},
+	}
+	if opName, _ := instance.Variables[b.export.ApiOperationVariable].(string); opName != "" {
+		op.OperationName = opName
+	}
+	if apiKey != "" {
+		op.ConsumerId = generateConsumerIDFromAPIKey(apiKey)
+	}
+	return op, nil
+}
+
+// logSeverity returns the Google ServiceControl log severity of a logentry severity, DEFAULT if
+// it's unknown.
+func logSeverity(severity string) string {
+	if s, found := logSeverities[strings.ToUpper(severity)]; found {
+		return s
+	}
+	return logSeverityDefault
+}
+
+// logEntryValue converts a variable value to a value encoded as is in JSON payloads.
+func logEntryValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case time.Duration:
+		return v.String()
+	case []byte:
+		// IP addresses are delivered as bytes.
+		if len(v) == net.IPv4len || len(v) == net.IPv6len {
+			return net.IP(v).String()
+		}
+		return string(v)
+	}
+	return value
+}
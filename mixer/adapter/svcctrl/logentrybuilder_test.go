@@ -0,0 +1,157 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+	"istio.io/istio/mixer/template/logentry"
+)
+
+const testLogEntryName = "accesslog.logentry.istio-system"
+
+func getTestLogEntryExport() *config.LogEntryExport {
+	return &config.LogEntryExport{
+		LogName:              "access_log",
+		LabelVariables:       []string{"response_code", "source_ip"},
+		ApiKeyVariable:       "api_key",
+		ApiOperationVariable: "api_operation",
+	}
+}
+
+func getTestLogEntryInstance() *logentry.Instance {
+	timestamp, _ := time.Parse(time.RFC3339Nano, "2017-10-21T17:09:05.000Z")
+	return &logentry.Instance{
+		Name:      testLogEntryName,
+		Timestamp: timestamp,
+		Severity:  "warn",
+		Variables: map[string]interface{}{
+			"api_key":       "test_key",
+			"api_operation": "echo",
+			"response_code": int64(503),
+			"source_ip":     []byte(net.ParseIP("10.0.0.1").To4()),
+			"latency":       100 * time.Millisecond,
+			"url":           "/echo",
+		},
+	}
+}
+
+func TestLogEntryBuilder(t *testing.T) {
+	builder := &logEntryBuilder{
+		export:    getTestLogEntryExport(),
+		anonymize: func(apiKey string) string { return apiKey },
+	}
+	op, err := builder.build(getTestLogEntryInstance())
+	if err != nil {
+		t.Fatalf(`build() failed with %v`, err)
+	}
+	if op.OperationId == "" || op.OperationName != "echo" || op.ConsumerId != "api_key:test_key" ||
+		op.StartTime != "2017-10-21T17:09:05Z" || op.EndTime != op.StartTime {
+		t.Errorf(`unexpected operation %v`, *op)
+	}
+	if len(op.LogEntries) != 1 {
+		t.Fatalf(`expect 1 log entry, but get %v`, op.LogEntries)
+	}
+	log := op.LogEntries[0]
+	if log.Name != "access_log" || log.Severity != "WARNING" || log.Timestamp != op.StartTime {
+		t.Errorf(`unexpected log entry %v`, *log)
+	}
+	expectedLabels := map[string]string{"response_code": "503", "source_ip": "10.0.0.1"}
+	if !reflect.DeepEqual(log.Labels, expectedLabels) {
+		t.Errorf(`expect labels %v, but get %v`, expectedLabels, log.Labels)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(log.StructPayload, &payload); err != nil {
+		t.Fatalf(`fail to decode payload %s: %v`, log.StructPayload, err)
+	}
+	expectedPayload := map[string]interface{}{
+		"api_key":       "test_key",
+		"api_operation": "echo",
+		"latency":       "100ms",
+		"url":           "/echo",
+	}
+	if !reflect.DeepEqual(payload, expectedPayload) {
+		t.Errorf(`expect payload %v, but get %v`, expectedPayload, payload)
+	}
+
+	// The operation is named after the log and has no consumer without the variables.
+	instance := getTestLogEntryInstance()
+	delete(instance.Variables, "api_key")
+	delete(instance.Variables, "api_operation")
+	if op, _ = builder.build(instance); op.OperationName != "access_log" || op.ConsumerId != "" {
+		t.Errorf(`expect operation of the log without consumer, but get %v`, *op)
+	}
+}
+
+func TestLogSeverity(t *testing.T) {
+	for severity, expected := range map[string]string{
+		"":         "DEFAULT",
+		"info":     "INFO",
+		"Error":    "ERROR",
+		"fatal":    "CRITICAL",
+		"verbose":  "DEFAULT",
+		"WARNING":  "WARNING",
+		"critical": "CRITICAL",
+	} {
+		if actual := logSeverity(severity); actual != expected {
+			t.Errorf(`expect severity %s of %s, but get %s`, expected, severity, actual)
+		}
+	}
+}
+
+func TestProcessLogEntry(t *testing.T) {
+	test := reportProcessorTestSetup(t)
+	test.testConfig.ServiceConfigs[0].LogEntryExports = map[string]*config.LogEntryExport{
+		testLogEntryName: getTestLogEntryExport(),
+	}
+	test.reportProc.anonymizer = newConsumerAnonymizer(&config.ConsumerAnonymization{Salt: "salt"})
+
+	unexported := getTestLogEntryInstance()
+	unexported.Name = "other.logentry.istio-system"
+	dropped := getCounterValue(droppedOperationCount, gcpServiceName, dropReasonNotExported)
+	if err := test.reportProc.ProcessLogEntry(context.Background(),
+		[]*logentry.Instance{getTestLogEntryInstance(), unexported}); err != nil {
+		t.Fatalf(`ProcessLogEntry() failed with %v`, err)
+	}
+	if actual := getCounterValue(droppedOperationCount, gcpServiceName, dropReasonNotExported); actual != dropped+1 {
+		t.Errorf(`expect %v log entries dropped, but get %v`, dropped+1, actual)
+	}
+
+	request := test.mockClient.reportRequest
+	if request == nil || len(request.Operations) != 1 {
+		t.Fatalf(`expect a report of 1 operation, but get %v`, request)
+	}
+	anonymized := test.reportProc.anonymizer.anonymize("test_key")
+	op := request.Operations[0]
+	if op.ConsumerId != generateConsumerIDFromAPIKey("test_key") {
+		t.Errorf(`expect consumer to keep API key, but get %v`, op.ConsumerId)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(op.LogEntries[0].StructPayload, &payload); err != nil || payload["api_key"] != anonymized {
+		t.Errorf(`expect anonymized API key in payload, but get %v, %v`, payload, err)
+	}
+
+	test.mockClient.reset()
+	if err := test.reportProc.ProcessLogEntry(context.Background(),
+		[]*logentry.Instance{unexported}); err != nil || test.mockClient.reportRequest != nil {
+		t.Errorf(`expect no report without exported log entries, but get %v, %v`, test.mockClient.reportRequest, err)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"sync"
+	"time"
+
+	"istio.io/istio/mixer/pkg/adapter"
+)
+
+const (
+	defaultWarningLogRate = 10
+	warningLogInterval    = time.Second
+)
+
+// rateLimitedLogger emits at most a fixed number of warnings and errors per interval, so a
+// sustained misconfiguration doesn't flood the log. Suppressed messages are summarized once the
+// next interval starts, or once the logger is flushed.
+type rateLimitedLogger struct {
+	logger   adapter.Logger
+	limit    int
+	interval time.Duration
+	now      func() time.Time
+
+	lock        sync.Mutex // guards fields below
+	windowStart time.Time
+	logged      int
+	suppressed  int
+}
+
+// Infof logs an informational message, which is never limited.
+func (l *rateLimitedLogger) Infof(format string, args ...interface{}) {
+	l.logger.Infof(format, args...)
+}
+
+// Warningf logs a warning unless the limit of current interval has been reached.
+func (l *rateLimitedLogger) Warningf(format string, args ...interface{}) {
+	if l.allow() {
+		l.logger.Warningf(format, args...)
+	}
+}
+
+// Errorf logs an error unless the limit of current interval has been reached.
+func (l *rateLimitedLogger) Errorf(format string, args ...interface{}) {
+	if l.allow() {
+		_ = l.logger.Errorf(format, args...)
+	}
+}
+
+// allow returns true if a message can be logged in current interval, and logs the summary of
+// the last interval once the interval starts.
+func (l *rateLimitedLogger) allow() bool {
+	l.lock.Lock()
+	now := l.now()
+	suppressed := 0
+	if now.Sub(l.windowStart) >= l.interval {
+		suppressed = l.suppressed
+		l.windowStart = now
+		l.logged = 0
+		l.suppressed = 0
+	}
+
+	allowed := l.logged < l.limit
+	if allowed {
+		l.logged++
+	} else {
+		l.suppressed++
+	}
+	l.lock.Unlock()
+
+	if suppressed > 0 {
+		l.logger.Warningf("%d warnings suppressed in last %v", suppressed, l.interval)
+	}
+	return allowed
+}
+
+// flush logs the summary of messages suppressed in current interval, so that they're not lost
+// when no message follows, e.g. once the handler is closed.
+func (l *rateLimitedLogger) flush() {
+	l.lock.Lock()
+	suppressed := l.suppressed
+	l.suppressed = 0
+	l.lock.Unlock()
+
+	if suppressed > 0 {
+		l.logger.Warningf("%d warnings suppressed in last %v", suppressed, l.interval)
+	}
+}
+
+func newRateLimitedLogger(logger adapter.Logger, rate int32) *rateLimitedLogger {
+	limit := int(rate)
+	if limit <= 0 {
+		limit = defaultWarningLogRate
+	}
+	return &rateLimitedLogger{
+		logger:   logger,
+		limit:    limit,
+		interval: warningLogInterval,
+		now:      time.Now,
+	}
+}
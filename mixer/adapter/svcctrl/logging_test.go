@@ -0,0 +1,78 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"testing"
+	"time"
+
+	at "istio.io/istio/mixer/pkg/adapter/test"
+)
+
+func TestRateLimitedLogger(t *testing.T) {
+	env := at.NewEnv(t)
+	now := time.Now()
+	logger := newRateLimitedLogger(env.Logger(), 3)
+	logger.now = func() time.Time { return now }
+
+	for i := 0; i < 10; i++ {
+		logger.Warningf("warning %d", i)
+	}
+	if logs := env.GetLogs(); len(logs) != 3 {
+		t.Errorf(`expect 3 warnings to be logged, but get %v`, logs)
+	}
+
+	// Suppressed warnings are summarized in the next interval.
+	now = now.Add(warningLogInterval)
+	logger.Warningf("warning after interval")
+	logs := env.GetLogs()
+	if len(logs) != 5 {
+		t.Fatalf(`expect 5 logs, but get %v`, logs)
+	}
+	if logs[3] != "7 warnings suppressed in last 1s" || logs[4] != "warning after interval" {
+		t.Errorf(`unexpected logs after interval: %v`, logs[3:])
+	}
+}
+
+func TestRateLimitedLoggerFlush(t *testing.T) {
+	env := at.NewEnv(t)
+	now := time.Now()
+	logger := newRateLimitedLogger(env.Logger(), 1)
+	logger.now = func() time.Time { return now }
+
+	logger.Warningf("warning 0")
+	logger.Errorf("error 1")
+	logger.Warningf("warning 2")
+	// Suppressed warnings are summarized on flush, even if no warning follows.
+	logger.flush()
+	logger.flush()
+	logs := env.GetLogs()
+	if len(logs) != 2 || logs[1] != "2 warnings suppressed in last 1s" {
+		t.Errorf(`expect suppressed warnings to be summarized once, but get %v`, logs)
+	}
+}
+
+func TestRateLimitedLoggerDefaultRate(t *testing.T) {
+	env := at.NewEnv(t)
+	logger := newRateLimitedLogger(env.Logger(), 0)
+	logger.now = func() time.Time { return time.Time{} }
+
+	for i := 0; i < 2*defaultWarningLogRate; i++ {
+		logger.Warningf("warning %d", i)
+	}
+	if logs := env.GetLogs(); len(logs) != defaultWarningLogRate {
+		t.Errorf(`expect %d warnings to be logged, but get %d`, defaultWarningLogRate, len(logs))
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+)
+
+// Names of built-in metric transformers.
+const (
+	scaleTransformer  = "scale"
+	clampTransformer  = "clamp"
+	bucketTransformer = "bucket"
+)
+
+type (
+	// MetricTransformer transforms a metric value before it's reported.
+	MetricTransformer interface {
+		Transform(value float64) float64
+	}
+
+	// MetricTransformerFactory creates a MetricTransformer from its config, returns an error if
+	// the config is invalid.
+	MetricTransformerFactory func(cfg *config.MetricTransform) (MetricTransformer, error)
+
+	scale struct {
+		factor float64
+	}
+
+	clamp struct {
+		min, max float64
+	}
+
+	bucket struct {
+		boundaries []float64
+	}
+)
+
+var (
+	transformerLock      sync.RWMutex // guards transformerFactories
+	transformerFactories = map[string]MetricTransformerFactory{
+		scaleTransformer:  newScale,
+		clampTransformer:  newClamp,
+		bucketTransformer: newBucket,
+	}
+)
+
+// RegisterMetricTransformer registers a custom metric transformer, which can then be configured
+// by name in GcpServiceSetting.metric_transforms. It must be called before handlers are built.
+func RegisterMetricTransformer(name string, factory MetricTransformerFactory) {
+	transformerLock.Lock()
+	defer transformerLock.Unlock()
+	transformerFactories[name] = factory
+}
+
+// Transform multiplies value by the factor.
+func (s *scale) Transform(value float64) float64 {
+	return value * s.factor
+}
+
+// Transform limits value to [min, max].
+func (c *clamp) Transform(value float64) float64 {
+	return math.Max(c.min, math.Min(c.max, value))
+}
+
+// Transform returns the largest boundary not above value, or the first boundary if value is below
+// all boundaries.
+func (b *bucket) Transform(value float64) float64 {
+	i := sort.SearchFloat64s(b.boundaries, value)
+	if i < len(b.boundaries) && b.boundaries[i] == value {
+		return value
+	}
+	if i == 0 {
+		return b.boundaries[0]
+	}
+	return b.boundaries[i-1]
+}
+
+func newScale(cfg *config.MetricTransform) (MetricTransformer, error) {
+	if cfg.Factor <= 0 {
+		return nil, fmt.Errorf("scale factor must be positive, but get %v", cfg.Factor)
+	}
+	return &scale{cfg.Factor}, nil
+}
+
+func newClamp(cfg *config.MetricTransform) (MetricTransformer, error) {
+	if cfg.Min > cfg.Max {
+		return nil, fmt.Errorf("clamp min %v must not be above max %v", cfg.Min, cfg.Max)
+	}
+	return &clamp{cfg.Min, cfg.Max}, nil
+}
+
+func newBucket(cfg *config.MetricTransform) (MetricTransformer, error) {
+	if len(cfg.Boundaries) == 0 {
+		return nil, errors.New("bucket boundaries must not be empty")
+	}
+	for i := 1; i < len(cfg.Boundaries); i++ {
+		if cfg.Boundaries[i] <= cfg.Boundaries[i-1] {
+			return nil, fmt.Errorf("bucket boundaries must be strictly ascending, but get %v", cfg.Boundaries)
+		}
+	}
+	return &bucket{append([]float64{}, cfg.Boundaries...)}, nil
+}
+
+// newMetricTransformer creates the metric transformer of cfg.
+func newMetricTransformer(cfg *config.MetricTransform) (MetricTransformer, error) {
+	transformerLock.RLock()
+	factory, found := transformerFactories[cfg.Transformer]
+	transformerLock.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("unknown metric transformer %q", cfg.Transformer)
+	}
+	return factory(cfg)
+}
+
+// newMetricTransformers returns transformers keyed by metric name in config order, returns nil if
+// none is configured.
+func newMetricTransformers(cfgs []*config.MetricTransform) (map[string][]MetricTransformer, error) {
+	if len(cfgs) == 0 {
+		return nil, nil
+	}
+	transformers := make(map[string][]MetricTransformer)
+	for _, cfg := range cfgs {
+		transformer, err := newMetricTransformer(cfg)
+		if err != nil {
+			return nil, err
+		}
+		transformers[cfg.MetricName] = append(transformers[cfg.MetricName], transformer)
+	}
+	return transformers, nil
+}
+
+// transformMetricValue applies transformers to the int64 or double value of a metric value in order.
+// Int64 values are rounded to the nearest integer.
+func transformMetricValue(value *sc.MetricValue, transformers []MetricTransformer) {
+	switch {
+	case value.Int64Value != nil:
+		v := float64(*value.Int64Value)
+		for _, transformer := range transformers {
+			v = transformer.Transform(v)
+		}
+		value.Int64Value = getInt64Address(int64(math.Floor(v + 0.5)))
+	case value.DoubleValue != nil:
+		v := *value.DoubleValue
+		for _, transformer := range transformers {
+			v = transformer.Transform(v)
+		}
+		value.DoubleValue = &v
+	}
+}
+
+// isTransformableMetric returns true if name is a supported metric with int64 or double values.
+func isTransformableMetric(name string) bool {
+	for _, metric := range supportedMetrics {
+		if metric.name == name {
+			return !metric.distribution
+		}
+	}
+	return false
+}
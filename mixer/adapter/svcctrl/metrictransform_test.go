@@ -0,0 +1,129 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"testing"
+
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+	"istio.io/istio/mixer/adapter/svcctrl/template/svcctrlreport"
+)
+
+const testRequestCountMetric = "serviceruntime.googleapis.com/api/producer/request_count"
+
+func TestBuiltinMetricTransformers(t *testing.T) {
+	testCases := []struct {
+		cfg      *config.MetricTransform
+		value    float64
+		expected float64
+	}{
+		{&config.MetricTransform{Transformer: scaleTransformer, Factor: 1.0 / 1024}, 2048, 2},
+		{&config.MetricTransform{Transformer: clampTransformer, Min: 1, Max: 10}, 0, 1},
+		{&config.MetricTransform{Transformer: clampTransformer, Min: 1, Max: 10}, 5, 5},
+		{&config.MetricTransform{Transformer: clampTransformer, Min: 1, Max: 10}, 20, 10},
+		{&config.MetricTransform{Transformer: bucketTransformer, Boundaries: []float64{0, 100, 1000}}, -1, 0},
+		{&config.MetricTransform{Transformer: bucketTransformer, Boundaries: []float64{0, 100, 1000}}, 100, 100},
+		{&config.MetricTransform{Transformer: bucketTransformer, Boundaries: []float64{0, 100, 1000}}, 999, 100},
+		{&config.MetricTransform{Transformer: bucketTransformer, Boundaries: []float64{0, 100, 1000}}, 5000, 1000},
+	}
+	for _, tc := range testCases {
+		transformer, err := newMetricTransformer(tc.cfg)
+		if err != nil {
+			t.Fatalf(`newMetricTransformer(%v) failed with %v`, *tc.cfg, err)
+		}
+		if actual := transformer.Transform(tc.value); actual != tc.expected {
+			t.Errorf(`expect %s to transform %v to %v, but get %v`, tc.cfg.Transformer, tc.value, tc.expected, actual)
+		}
+	}
+}
+
+func TestInvalidMetricTransformers(t *testing.T) {
+	for _, cfg := range []*config.MetricTransform{
+		{Transformer: "unknown"},
+		{Transformer: scaleTransformer},
+		{Transformer: clampTransformer, Min: 10, Max: 1},
+		{Transformer: bucketTransformer},
+		{Transformer: bucketTransformer, Boundaries: []float64{0, 100, 100}},
+	} {
+		if _, err := newMetricTransformer(cfg); err == nil {
+			t.Errorf(`expect error on invalid transform %v`, *cfg)
+		}
+	}
+}
+
+type offsetTransformer struct {
+	offset float64
+}
+
+func (o *offsetTransformer) Transform(value float64) float64 {
+	return value + o.offset
+}
+
+func TestRegisterMetricTransformer(t *testing.T) {
+	RegisterMetricTransformer("test-offset", func(cfg *config.MetricTransform) (MetricTransformer, error) {
+		return &offsetTransformer{cfg.Factor}, nil
+	})
+	transformer, err := newMetricTransformer(&config.MetricTransform{Transformer: "test-offset", Factor: 3})
+	if err != nil {
+		t.Fatalf(`newMetricTransformer() failed with %v`, err)
+	}
+	if actual := transformer.Transform(1); actual != 4 {
+		t.Errorf(`expect custom transformer to transform 1 to 4, but get %v`, actual)
+	}
+}
+
+func TestTransformMetricValue(t *testing.T) {
+	transformers := []MetricTransformer{&scale{2.6}, &clamp{0, 3}}
+	value := &sc.MetricValue{Int64Value: getInt64Address(1)}
+	transformMetricValue(value, transformers)
+	if *value.Int64Value != 3 {
+		t.Errorf(`expect int64 value to be rounded to 3, but get %v`, *value.Int64Value)
+	}
+
+	double := 1.0
+	value = &sc.MetricValue{DoubleValue: &double}
+	transformMetricValue(value, transformers)
+	if *value.DoubleValue != 2.6 {
+		t.Errorf(`expect double value 2.6, but get %v`, *value.DoubleValue)
+	}
+}
+
+func TestProcessReportMetricTransforms(t *testing.T) {
+	test := reportProcessorTestSetup(t)
+	transformers, err := newMetricTransformers([]*config.MetricTransform{
+		{MetricName: testRequestCountMetric, Transformer: scaleTransformer, Factor: 10},
+	})
+	if err != nil {
+		t.Fatalf(`newMetricTransformers() failed with %v`, err)
+	}
+	test.reportProc.metricTransformers = transformers
+	if err := test.reportProc.ProcessReport(context.Background(),
+		[]*svcctrlreport.Instance{getTestReportInstance()}); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+
+	for _, metricSet := range test.mockClient.reportRequest.Operations[0].MetricValueSets {
+		expected := int64(1)
+		if metricSet.MetricName == testRequestCountMetric {
+			expected = 10
+		}
+		if value := metricSet.MetricValues[0]; value.Int64Value != nil && *value.Int64Value != expected {
+			t.Errorf(`expect %s value %v, but get %v`, metricSet.MetricName, expected, *value.Int64Value)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	serviceLabel  = "service"
+	reasonLabel   = "reason"
+	consumerLabel = "consumer"
+	methodLabel   = "method"
+	resultLabel   = "result"
+	quotaLabel    = "quota"
+	codeLabel     = "code"
+
+	// Methods of calls, values of method label.
+	methodCheck  = "check"
+	methodQuota  = "quota"
+	methodReport = "report"
+
+	// Reasons for rejecting a consumer.
+	rejectReasonDenied     = "denied"
+	rejectReasonNotAllowed = "not_allowed"
+
+	// Reasons for dropping an operation before reporting.
+	dropReasonMissingLabel    = "missing_required_label"
+	dropReasonThrottled       = "throttled"
+	dropReasonNotExported     = "not_exported"
+	dropReasonInvalidLogEntry = "invalid_log_entry"
+	dropReasonBufferFull      = "buffer_full"
+)
+
+var (
+	// Buckets in seconds of call durations.
+	durationBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+	// Buckets in seconds of ages of cached check results.
+	cacheAgeBuckets = []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800, 3600}
+
+	// Buckets in bytes of serialized requests and responses, from 64B to 4MB.
+	payloadSizeBuckets = prometheus.ExponentialBuckets(64, 4, 9)
+
+	droppedOperationCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "mixer",
+			Subsystem: "svcctrl",
+			Name:      "dropped_operation_count",
+			Help:      "Total number of operations dropped by svcctrl adapter instead of being reported.",
+		}, []string{serviceLabel, reasonLabel})
+
+	suppressedCallCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "mixer",
+			Subsystem: "svcctrl",
+			Name:      "suppressed_call_count",
+			Help:      "Total number of quota calls and report operations suppressed by the suppress report attribute.",
+		}, []string{serviceLabel, methodLabel})
+
+	rejectedConsumerCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "mixer",
+			Subsystem: "svcctrl",
+			Name:      "rejected_consumer_count",
+			Help:      "Total number of check and quota calls rejected by consumer allowlist or denylist.",
+		}, []string{serviceLabel, methodLabel, reasonLabel})
+
+	advisoryCheckErrorCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "mixer",
+			Subsystem: "svcctrl",
+			Name:      "advisory_check_error_count",
+			Help:      "Total number of advisory check errors, which don't deny checks.",
+		}, []string{serviceLabel, codeLabel})
+
+	unresolvedConsumerCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "mixer",
+			Subsystem: "svcctrl",
+			Name:      "unresolved_consumer_count",
+			Help:      "Total number of check, quota and report calls whose consumer can't be resolved.",
+		}, []string{serviceLabel, methodLabel})
+
+	failOpenCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "mixer",
+			Subsystem: "svcctrl",
+			Name:      "fail_open_count",
+			Help:      "Total number of check and quota calls allowed without Google ServiceControl by fail-open, or despite transient check errors.",
+		}, []string{serviceLabel, methodLabel})
+
+	retryCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "mixer",
+			Subsystem: "svcctrl",
+			Name:      "retry_count",
+			Help:      "Total number of calls to Google ServiceControl retried because it's unreachable.",
+		}, []string{serviceLabel, methodLabel})
+
+	circuitBreakerRejectedCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "mixer",
+			Subsystem: "svcctrl",
+			Name:      "circuit_breaker_rejected_count",
+			Help:      "Total number of calls to Google ServiceControl rejected by the open circuit breaker.",
+		}, []string{serviceLabel, methodLabel})
+
+	allocateQuotaDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "mixer",
+			Subsystem: "svcctrl",
+			Name:      "allocate_quota_duration",
+			Help:      "Histogram of times in seconds for AllocateQuota calls to Google ServiceControl.",
+			Buckets:   durationBuckets,
+		}, []string{serviceLabel, quotaLabel})
+
+	checkCacheHitAge = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "mixer",
+			Subsystem: "svcctrl",
+			Name:      "check_cache_hit_age",
+			Help:      "Histogram of ages in seconds of cached check results served by svcctrl adapter.",
+			Buckets:   cacheAgeBuckets,
+		}, []string{serviceLabel})
+
+	requestSize = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "mixer",
+			Subsystem: "svcctrl",
+			Name:      "request_size",
+			Help:      "Histogram of body sizes in bytes of requests to Google ServiceControl.",
+			Buckets:   payloadSizeBuckets,
+		}, []string{serviceLabel, methodLabel})
+
+	responseSize = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "mixer",
+			Subsystem: "svcctrl",
+			Name:      "response_size",
+			Help:      "Histogram of body sizes in bytes of responses from Google ServiceControl.",
+			Buckets:   payloadSizeBuckets,
+		}, []string{serviceLabel, methodLabel})
+
+	reportBufferBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "mixer",
+			Subsystem: "svcctrl",
+			Name:      "report_buffer_bytes",
+			Help:      "Estimated bytes of report operations buffered before they're flushed.",
+		}, []string{serviceLabel})
+
+	consumerCallCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "mixer",
+			Subsystem: "svcctrl",
+			Name:      "consumer_call_count",
+			Help:      "Total number of check and quota calls by consumer and result.",
+		}, []string{serviceLabel, consumerLabel, methodLabel, resultLabel})
+)
+
+func init() {
+	prometheus.MustRegister(droppedOperationCount)
+	prometheus.MustRegister(suppressedCallCount)
+	prometheus.MustRegister(rejectedConsumerCount)
+	prometheus.MustRegister(advisoryCheckErrorCount)
+	prometheus.MustRegister(unresolvedConsumerCount)
+	prometheus.MustRegister(failOpenCount)
+	prometheus.MustRegister(retryCount)
+	prometheus.MustRegister(circuitBreakerRejectedCount)
+	prometheus.MustRegister(allocateQuotaDuration)
+	prometheus.MustRegister(checkCacheHitAge)
+	prometheus.MustRegister(requestSize)
+	prometheus.MustRegister(responseSize)
+	prometheus.MustRegister(consumerCallCount)
+	prometheus.MustRegister(reportBufferBytes)
+}
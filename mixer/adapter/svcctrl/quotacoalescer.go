@@ -0,0 +1,195 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"sync"
+	"time"
+
+	rpc "github.com/googleapis/googleapis/google/rpc"
+	sc "google.golang.org/api/servicecontrol/v1"
+)
+
+type (
+	// coalescingKey identifies quota allocations which can be coalesced into one AllocateQuota call.
+	coalescingKey struct {
+		consumerID string
+		opName     string
+		quotaName  string
+		bestEffort bool
+	}
+
+	// quotaGrant is the outcome of a coalesced allocation. Amount is the granted amount when
+	// response carries no allocate errors.
+	quotaGrant struct {
+		response *sc.AllocateQuotaResponse
+		amount   int64
+		err      error
+	}
+
+	quotaWaiter struct {
+		amount int64
+		grant  chan quotaGrant
+	}
+
+	// quotaBatch holds allocations waiting for the same AllocateQuota call, in arrival order.
+	quotaBatch struct {
+		waiters []*quotaWaiter
+	}
+
+	// allocateFunc calls AllocateQuota for amount, in BEST_EFFORT mode if bestEffort is set.
+	allocateFunc func(amount int64, bestEffort bool) (*sc.AllocateQuotaResponse, error)
+
+	// quotaCoalescer coalesces concurrent allocations of the same key within a window into a single
+	// AllocateQuota call of the summed amount, and distributes the grant back to the allocations.
+	quotaCoalescer struct {
+		window time.Duration
+
+		lock    sync.Mutex // guards batches
+		batches map[coalescingKey]*quotaBatch
+	}
+)
+
+// allocate joins the pending batch of key, or starts a new one which is allocated once the window
+// passes, and returns the grant of amount. metricName is the quota metric the grant is read from.
+func (c *quotaCoalescer) allocate(key coalescingKey, metricName string, amount int64,
+	allocate allocateFunc) quotaGrant {
+	waiter := &quotaWaiter{amount, make(chan quotaGrant, 1)}
+	c.lock.Lock()
+	batch, found := c.batches[key]
+	if !found {
+		batch = &quotaBatch{}
+		c.batches[key] = batch
+	}
+	batch.waiters = append(batch.waiters, waiter)
+	c.lock.Unlock()
+
+	// The allocation starting the batch allocates it for all.
+	if !found {
+		time.Sleep(c.window)
+		c.lock.Lock()
+		delete(c.batches, key)
+		c.lock.Unlock()
+		flushQuotaBatch(batch, metricName, key.bestEffort, allocate)
+	}
+	return <-waiter.grant
+}
+
+// flushQuotaBatch allocates the summed amount of a batch and distributes the grant in arrival order.
+// When a normal allocation of several waiters is exhausted, what's left is allocated in BEST_EFFORT
+// mode and granted to waiters in arrival order as long as their whole amount fits, so that small
+// allocations aren't denied because of the others. A batch makes at most two AllocateQuota calls.
+func flushQuotaBatch(batch *quotaBatch, metricName string, bestEffort bool, allocate allocateFunc) {
+	var total int64
+	for _, waiter := range batch.waiters {
+		total += waiter.amount
+	}
+
+	response, err := allocate(total, bestEffort)
+	if err != nil {
+		for _, waiter := range batch.waiters {
+			waiter.grant <- quotaGrant{err: err}
+		}
+		return
+	}
+
+	if len(response.AllocateErrors) > 0 {
+		if !bestEffort && len(batch.waiters) > 1 && isQuotaExhausted(response) {
+			splitExhaustedBatch(batch, metricName, total, response, allocate)
+			return
+		}
+		for _, waiter := range batch.waiters {
+			waiter.grant <- quotaGrant{response: response}
+		}
+		return
+	}
+
+	remaining := grantedAmount(response, metricName, total)
+	for _, waiter := range batch.waiters {
+		amount := waiter.amount
+		if amount > remaining {
+			amount = remaining
+		}
+		remaining -= amount
+		waiter.grant <- quotaGrant{response: response, amount: amount}
+	}
+}
+
+// splitExhaustedBatch allocates what's left of total in BEST_EFFORT mode, and grants it to waiters
+// of a normal batch whose whole amount fits, in arrival order. Other waiters are denied with the
+// exhausted response. Quota left over by denied waiters is not returned.
+func splitExhaustedBatch(batch *quotaBatch, metricName string, total int64,
+	exhausted *sc.AllocateQuotaResponse, allocate allocateFunc) {
+	response, err := allocate(total, true)
+	if err != nil || len(response.AllocateErrors) > 0 {
+		for _, waiter := range batch.waiters {
+			waiter.grant <- quotaGrant{response: exhausted}
+		}
+		return
+	}
+
+	remaining := grantedAmount(response, metricName, total)
+	for _, waiter := range batch.waiters {
+		if waiter.amount > remaining {
+			waiter.grant <- quotaGrant{response: exhausted}
+			continue
+		}
+		remaining -= waiter.amount
+		waiter.grant <- quotaGrant{response: response, amount: waiter.amount}
+	}
+}
+
+// grantedAmount returns the amount of metricName granted by response, or requested if the response
+// doesn't carry the quota metric.
+func grantedAmount(response *sc.AllocateQuotaResponse, metricName string, requested int64) int64 {
+	if response == nil {
+		return 0
+	}
+	for _, metricSet := range response.QuotaMetrics {
+		if metricSet.MetricName != metricName {
+			continue
+		}
+		var granted int64
+		for _, value := range metricSet.MetricValues {
+			if value.Int64Value != nil {
+				granted += *value.Int64Value
+			}
+		}
+		return granted
+	}
+	return requested
+}
+
+// isQuotaExhausted returns true if the allocation failed for lack of quota.
+func isQuotaExhausted(response *sc.AllocateQuotaResponse) bool {
+	for _, allocateError := range response.AllocateErrors {
+		if serviceControlErrorToRPCCode(allocateError.Code) == rpc.RESOURCE_EXHAUSTED {
+			return true
+		}
+	}
+	return false
+}
+
+// newQuotaCoalescer creates quotaCoalescer coalescing allocations within window, returns nil if
+// window is not positive.
+func newQuotaCoalescer(window time.Duration) *quotaCoalescer {
+	if window <= 0 {
+		return nil
+	}
+	return &quotaCoalescer{
+		window:  window,
+		batches: make(map[coalescingKey]*quotaBatch),
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/pkg/adapter"
+	"istio.io/istio/mixer/pkg/status"
+)
+
+var testCoalescingKey = coalescingKey{"api_key:test_key", "echo", testQuotaName, false}
+
+// mockAllocator allocates from a fixed amount of available quota, and records requested amounts.
+type mockAllocator struct {
+	lock      sync.Mutex // guards fields below
+	available int64
+	requested []int64
+	// Best effort allocations grant what's available instead of failing.
+	bestEffort bool
+}
+
+func (a *mockAllocator) allocate(amount int64, bestEffort bool) (*sc.AllocateQuotaResponse, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.requested = append(a.requested, amount)
+	if amount > a.available && !a.bestEffort && !bestEffort {
+		return &sc.AllocateQuotaResponse{
+			AllocateErrors: []*sc.QuotaError{{Code: "RESOURCE_EXHAUSTED"}},
+		}, nil
+	}
+	granted := amount
+	if granted > a.available {
+		granted = a.available
+	}
+	a.available -= granted
+	return &sc.AllocateQuotaResponse{
+		QuotaMetrics: []*sc.MetricValueSet{
+			{
+				MetricName:   "read-requests",
+				MetricValues: []*sc.MetricValue{{Int64Value: getInt64Address(granted)}},
+			},
+		},
+	}, nil
+}
+
+func (a *mockAllocator) getRequested() []int64 {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.requested
+}
+
+// allocateInOrder starts allocations of amounts one after another joining the batch, and returns
+// their grants in the same order.
+func allocateInOrder(c *quotaCoalescer, key coalescingKey, allocate allocateFunc,
+	amounts ...int64) []quotaGrant {
+	grants := make([]quotaGrant, len(amounts))
+	var wg sync.WaitGroup
+	for i, amount := range amounts {
+		wg.Add(1)
+		go func(i int, amount int64) {
+			defer wg.Done()
+			grants[i] = c.allocate(key, "read-requests", amount, allocate)
+		}(i, amount)
+
+		// Wait for the allocation to join the batch.
+		for joined := false; !joined; {
+			c.lock.Lock()
+			joined = c.batches[key] != nil && len(c.batches[key].waiters) == i+1
+			c.lock.Unlock()
+			if !joined {
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}
+	wg.Wait()
+	return grants
+}
+
+func TestQuotaCoalescer(t *testing.T) {
+	c := newQuotaCoalescer(100 * time.Millisecond)
+	allocator := &mockAllocator{available: 10}
+	grants := allocateInOrder(c, testCoalescingKey, allocator.allocate, 1, 2, 3)
+
+	if requested := allocator.getRequested(); len(requested) != 1 || requested[0] != 6 {
+		t.Errorf(`expect a single allocation of 6, but get %v`, requested)
+	}
+	for i, expected := range []int64{1, 2, 3} {
+		if grants[i].err != nil || len(grants[i].response.AllocateErrors) > 0 || grants[i].amount != expected {
+			t.Errorf(`expect allocation %d to be granted %v, but get %v`, i, expected, grants[i])
+		}
+	}
+
+	// Batches of other keys are allocated separately.
+	otherKey := testCoalescingKey
+	otherKey.consumerID = "api_key:other_key"
+	allocateInOrder(c, otherKey, allocator.allocate, 1)
+	if requested := allocator.getRequested(); len(requested) != 2 || requested[1] != 1 {
+		t.Errorf(`expect a separate allocation of other key, but get %v`, requested)
+	}
+
+	if newQuotaCoalescer(0) != nil {
+		t.Error(`expect nil quotaCoalescer when not configured`)
+	}
+}
+
+func TestQuotaCoalescerPartialGrant(t *testing.T) {
+	c := newQuotaCoalescer(100 * time.Millisecond)
+	allocator := &mockAllocator{available: 4, bestEffort: true}
+	key := testCoalescingKey
+	key.bestEffort = true
+	grants := allocateInOrder(c, key, allocator.allocate, 3, 2, 1)
+
+	// Granted amount is distributed in arrival order.
+	for i, expected := range []int64{3, 1, 0} {
+		if grants[i].err != nil || grants[i].amount != expected {
+			t.Errorf(`expect allocation %d to be granted %v, but get %v`, i, expected, grants[i])
+		}
+	}
+}
+
+func TestQuotaCoalescerExhausted(t *testing.T) {
+	c := newQuotaCoalescer(100 * time.Millisecond)
+	allocator := &mockAllocator{available: 4}
+	grants := allocateInOrder(c, testCoalescingKey, allocator.allocate, 1, 2, 3, 1, 1)
+
+	// The summed allocation is exhausted, what's left is allocated once in best effort mode
+	// and granted to waiters whose amount fits.
+	requested := allocator.getRequested()
+	if len(requested) != 2 || requested[0] != 8 || requested[1] != 8 {
+		t.Errorf(`expect two allocations regardless of waiters, but get %v`, requested)
+	}
+	for i, expectedGranted := range []bool{true, true, false, true, false} {
+		granted := grants[i].err == nil && len(grants[i].response.AllocateErrors) == 0
+		if granted != expectedGranted {
+			t.Errorf(`expect allocation %d granted to be %v, but get %v`, i, expectedGranted, grants[i])
+		}
+	}
+}
+
+func TestQuotaCoalescerError(t *testing.T) {
+	c := newQuotaCoalescer(10 * time.Millisecond)
+	grants := allocateInOrder(c, testCoalescingKey, func(int64, bool) (*sc.AllocateQuotaResponse, error) {
+		return nil, errors.New("injected error")
+	}, 1, 2)
+	for i, grant := range grants {
+		if grant.err == nil {
+			t.Errorf(`expect allocation %d to fail, but get %v`, i, grant)
+		}
+	}
+}
+
+func TestProcessQuotaCoalesced(t *testing.T) {
+	test := quotaProcessorTestSetup(t)
+	test.quotaProc.coalescer = newQuotaCoalescer(200 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	results := make([]adapter.QuotaResult, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = test.quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(),
+				adapter.QuotaArgs{QuotaAmount: int64(i + 1)})
+		}(i)
+	}
+	wg.Wait()
+
+	op := test.mockClient.allocateQuotaRequest.AllocateOperation
+	if amount := *op.QuotaMetrics[0].MetricValues[0].Int64Value; amount != 3 {
+		t.Errorf(`expect a coalesced allocation of 3, but get %v`, amount)
+	}
+	for i, result := range results {
+		if !status.IsOK(result.Status) || result.Amount != int64(i+1) {
+			t.Errorf(`expect quota %d granted, but get %v`, i, result)
+		}
+	}
+}
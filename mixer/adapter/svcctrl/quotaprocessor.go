@@ -0,0 +1,476 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	rpc "github.com/googleapis/googleapis/google/rpc"
+	"github.com/pborman/uuid"
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+	"istio.io/istio/mixer/pkg/adapter"
+	"istio.io/istio/mixer/pkg/status"
+	"istio.io/istio/mixer/template/quota"
+)
+
+const (
+	// Quota instance dimensions used to build quota operation.
+	apiKeyDimension       = "api_key"
+	apiOperationDimension = "api_operation"
+
+	quotaModeNormal     = "NORMAL"
+	quotaModeBestEffort = "BEST_EFFORT"
+
+	// Quota granted on deadline is only valid for a short time, so that it's allocated
+	// for real soon.
+	quotaDeadlineResultExpiration = time.Second
+)
+
+// expirationOverride is a compiled config.Quota_ExpirationOverride.
+type expirationOverride struct {
+	consumerPattern *regexp.Regexp
+	expiration      time.Duration
+}
+
+// quotaImpl implements quotaProcessor interface, handles AllocateQuota call to Google ServiceControl backend.
+type quotaImpl struct {
+	env           adapter.Env
+	serviceConfig *config.GcpServiceSetting
+	// A map keyed by Istio quota name to quota config.
+	quotaIndex map[string]*config.Quota
+	// A map keyed by Istio quota name to expiration overrides of the quota.
+	expirationOverrides map[string][]expirationOverride
+	client              serviceControlClient
+	resolver            consumerProjectIDResolver
+	// Whether Mixer deduplication ids are used as operation ids, and their namespace.
+	useDedupID           bool
+	operationIDNamespace string
+	// Cap of the total time of a quota call, 0 when not capped.
+	deadline time.Duration
+	// Whether quota is granted or denied when the deadline is exceeded.
+	failOpenOnDeadline bool
+	// Whether quota is granted or denied when Google ServiceControl is unreachable.
+	failOpenOnUnreachable bool
+	// Nil when per-consumer metrics are disabled.
+	consumerMetrics *consumerMetrics
+	// Nil when consumers are not filtered.
+	consumerFilter *consumerFilter
+	warningLogger  *rateLimitedLogger
+	// Nil when quota allocations are not coalesced.
+	coalescer *quotaCoalescer
+	// A map keyed by Istio quota name to local token buckets of quotas in BEST_EFFORT_LOCAL mode.
+	localQuotas map[string]*localQuotaPool
+}
+
+// ProcessQuota allocates quota within the deadline if configured. The deadline bounds the
+// AllocateQuota calls themselves, so that they're cancelled once it passes.
+func (q *quotaImpl) ProcessQuota(ctx context.Context,
+	instance *quota.Instance, args adapter.QuotaArgs) (adapter.QuotaResult, error) {
+	if q.deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.deadline)
+		defer cancel()
+	}
+	return q.allocateQuota(ctx, instance, args)
+}
+
+// deadlineResult grants or denies a quota call exceeding the deadline.
+func (q *quotaImpl) deadlineResult(quotaCfg *config.Quota, args adapter.QuotaArgs) adapter.QuotaResult {
+	if q.failOpenOnDeadline {
+		failOpenCount.WithLabelValues(q.serviceConfig.GoogleServiceName, methodQuota).Inc()
+		q.warningLogger.Warningf("fail open quota %s, allocation exceeds deadline %v", quotaCfg.Name, q.deadline)
+		return adapter.QuotaResult{
+			Status:        status.OK,
+			ValidDuration: quotaDeadlineResultExpiration,
+			Amount:        args.QuotaAmount,
+		}
+	}
+	return adapter.QuotaResult{
+		Status: status.WithDeadlineExceeded(
+			fmt.Sprintf("quota %s allocation exceeds deadline %v", quotaCfg.Name, q.deadline)),
+	}
+}
+
+// backgroundContext returns the context of allocations outliving the quota call starting them,
+// bounded by the deadline if configured.
+func (q *quotaImpl) backgroundContext() (context.Context, context.CancelFunc) {
+	if q.deadline > 0 {
+		return context.WithTimeout(context.Background(), q.deadline)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// allocateQuota allocates quota from Google ServiceControl and converts AllocateQuotaResponse to adapter.QuotaResult.
+func (q *quotaImpl) allocateQuota(ctx context.Context,
+	instance *quota.Instance, args adapter.QuotaArgs) (adapter.QuotaResult, error) {
+	quotaCfg, found := q.quotaIndex[instance.Name]
+	if !found {
+		// Quotas not in the service config are enforced elsewhere, if at all.
+		return adapter.QuotaResult{
+			Status:        status.OK,
+			ValidDuration: failOpenResultExpiration,
+			Amount:        args.QuotaAmount,
+		}, nil
+	}
+
+	if attribute := q.serviceConfig.SuppressReportAttribute; attribute != "" {
+		if suppress, _ := instance.Dimensions[attribute].(string); isTruthy(suppress) {
+			suppressedCallCount.WithLabelValues(q.serviceConfig.GoogleServiceName, methodQuota).Inc()
+			return adapter.QuotaResult{
+				Status:        status.OK,
+				ValidDuration: toDuration(quotaCfg.Expiration),
+				Amount:        args.QuotaAmount,
+			}, nil
+		}
+	}
+
+	apiKey, _ := instance.Dimensions[apiKeyDimension].(string)
+	opName, _ := instance.Dimensions[apiOperationDimension].(string)
+	var peerIdentity string
+	if attribute := q.serviceConfig.PeerIdentityAttribute; attribute != "" {
+		peerIdentity, _ = instance.Dimensions[attribute].(string)
+	}
+	// Missing consumer sources are handled by the consumer resolution failure policy.
+	if opName == "" || (apiKey == "" && q.serviceConfig.QuotaConsumer != config.PEER_IDENTITY &&
+		q.serviceConfig.ConsumerResolutionFailurePolicy == config.DENY) {
+		return adapter.QuotaResult{
+			Status: status.WithInvalidArgument(
+				fmt.Sprintf("instance:%s, api key and api operation must not be empty", instance.Name)),
+		}, nil
+	}
+
+	if q.useDedupID && args.DeduplicationID == "" {
+		return adapter.QuotaResult{
+			Status: status.WithInvalidArgument(
+				fmt.Sprintf("instance:%s, deduplication id must not be empty", instance.Name)),
+		}, nil
+	}
+
+	// Google ServiceControl doesn't support releasing quota.
+	if args.QuotaAmount <= 0 {
+		return adapter.QuotaResult{
+			Status: status.OK,
+		}, nil
+	}
+
+	consumerID, err := resolveConsumerID(q.serviceConfig.QuotaConsumer, apiKey, peerIdentity, opName,
+		q.serviceConfig.PeerIdentityProjects, q.resolver)
+	if err != nil {
+		unresolvedConsumerCount.WithLabelValues(q.serviceConfig.GoogleServiceName, methodQuota).Inc()
+		if q.serviceConfig.ConsumerResolutionFailurePolicy != config.DENY {
+			return adapter.QuotaResult{
+				Status:        status.OK,
+				ValidDuration: toDuration(quotaCfg.Expiration),
+				Amount:        args.QuotaAmount,
+			}, nil
+		}
+		return adapter.QuotaResult{
+			Status: status.WithPermissionDenied(fmt.Sprintf("fail to resolve quota consumer: %v", err)),
+		}, nil
+	}
+
+	if q.consumerFilter != nil {
+		if reason := q.consumerFilter.reject(consumerID); reason != "" {
+			rejectedConsumerCount.WithLabelValues(q.serviceConfig.GoogleServiceName, methodQuota, reason).Inc()
+			return adapter.QuotaResult{
+				Status: status.WithPermissionDenied(
+					fmt.Sprintf("instance:%s, consumer is %s to call %s",
+						instance.Name, rejectReasonMessage(reason), q.serviceConfig.GoogleServiceName)),
+			}, nil
+		}
+	}
+
+	if pool := q.localQuotas[quotaCfg.Name]; pool != nil {
+		return q.allocateLocal(pool, consumerID, opName, quotaCfg, args), nil
+	}
+
+	if q.coalescer != nil {
+		return q.allocateCoalesced(ctx, consumerID, opName, quotaCfg, args), nil
+	}
+
+	response, err := q.doAllocateQuota(ctx, consumerID, opName, quotaCfg, args)
+	if err != nil {
+		q.recordConsumerResult(consumerID, false)
+		return q.unavailableResult(quotaCfg, err, args), nil
+	}
+
+	if q.env.Logger().VerbosityLevel(logDebug) {
+		if responseDetail, err := toFormattedJSON(response); err == nil {
+			q.env.Logger().Infof("response: %v", responseDetail)
+		}
+	}
+
+	result := q.responseToQuotaResult(response, quotaCfg, consumerID, args)
+	q.recordConsumerResult(consumerID, status.IsOK(result.Status))
+	return result, nil
+}
+
+// allocateCoalesced allocates quota together with concurrent allocations of the same consumer,
+// operation and quota. The coalesced call is bounded by ctx of the allocation starting the batch.
+func (q *quotaImpl) allocateCoalesced(ctx context.Context, consumerID, opName string, quotaCfg *config.Quota,
+	args adapter.QuotaArgs) adapter.QuotaResult {
+	key := coalescingKey{consumerID, opName, quotaCfg.Name, args.BestEffort}
+	grant := q.coalescer.allocate(key, quotaCfg.GoogleQuotaMetricName, args.QuotaAmount,
+		func(amount int64, bestEffort bool) (*sc.AllocateQuotaResponse, error) {
+			batchArgs := args
+			batchArgs.QuotaAmount = amount
+			batchArgs.BestEffort = bestEffort
+			return q.doAllocateQuota(ctx, consumerID, opName, quotaCfg, batchArgs)
+		})
+	if grant.err != nil {
+		q.recordConsumerResult(consumerID, false)
+		return q.unavailableResult(quotaCfg, grant.err, args)
+	}
+
+	result := q.responseToQuotaResult(grant.response, quotaCfg, consumerID, args)
+	if status.IsOK(result.Status) {
+		result.Amount = grant.amount
+	}
+	q.recordConsumerResult(consumerID, status.IsOK(result.Status))
+	return result
+}
+
+// allocateLocal grants quota from the local token bucket of the consumer and operation.
+func (q *quotaImpl) allocateLocal(pool *localQuotaPool, consumerID, opName string, quotaCfg *config.Quota,
+	args adapter.QuotaArgs) adapter.QuotaResult {
+	granted, err := pool.allocate(localQuotaKey{consumerID, opName}, args.QuotaAmount, args.BestEffort,
+		func(amount int64) (int64, bool, error) {
+			// Refills run in the background, and may be reused by later calls.
+			ctx, cancel := q.backgroundContext()
+			defer cancel()
+			response, err := q.doAllocateQuota(ctx, consumerID, opName, quotaCfg, adapter.QuotaArgs{
+				QuotaAmount: amount,
+				BestEffort:  true,
+			})
+			if err != nil {
+				return 0, false, err
+			}
+			if isQuotaExhausted(response) {
+				return 0, true, nil
+			}
+			if len(response.AllocateErrors) > 0 {
+				allocateError := response.AllocateErrors[0]
+				return 0, false, &quotaAllocateError{serviceControlErrorToRPCCode(allocateError.Code),
+					fmt.Sprintf("%s: %s", allocateError.Code, allocateError.Description)}
+			}
+			granted := grantedAmount(response, quotaCfg.GoogleQuotaMetricName, amount)
+			return granted, granted < amount, nil
+		})
+	if err != nil {
+		q.recordConsumerResult(consumerID, false)
+		if allocateError, ok := err.(*quotaAllocateError); ok {
+			return adapter.QuotaResult{
+				Status: status.WithMessage(allocateError.code, err.Error()),
+			}
+		}
+		return q.unavailableResult(quotaCfg, err, args)
+	}
+	if granted == 0 && args.QuotaAmount > 0 {
+		q.recordConsumerResult(consumerID, false)
+		return adapter.QuotaResult{
+			Status: status.WithResourceExhausted(fmt.Sprintf("quota %s is exhausted", quotaCfg.Name)),
+		}
+	}
+
+	q.recordConsumerResult(consumerID, true)
+	return adapter.QuotaResult{
+		Status:        status.OK,
+		ValidDuration: q.quotaExpiration(quotaCfg, consumerID),
+		Amount:        granted,
+	}
+}
+
+// Close stops refilling local token buckets.
+func (q *quotaImpl) Close() error {
+	for _, pool := range q.localQuotas {
+		pool.close()
+	}
+	return nil
+}
+
+// unavailableResult grants a quota call failing with err if Google ServiceControl is unreachable
+// and failOpenOnUnreachable is set, and denies it with UNAVAILABLE otherwise. Calls exceeding the
+// deadline get deadlineResult.
+func (q *quotaImpl) unavailableResult(quotaCfg *config.Quota, err error, args adapter.QuotaArgs) adapter.QuotaResult {
+	if q.deadline > 0 && err == context.DeadlineExceeded {
+		return q.deadlineResult(quotaCfg, args)
+	}
+	if q.failOpenOnUnreachable && isUnreachable(err) {
+		failOpenCount.WithLabelValues(q.serviceConfig.GoogleServiceName, methodQuota).Inc()
+		q.warningLogger.Warningf("fail open quota %s, Google ServiceControl is unreachable: %v", quotaCfg.Name, err)
+		return adapter.QuotaResult{
+			Status:        status.OK,
+			ValidDuration: failOpenResultExpiration,
+			Amount:        args.QuotaAmount,
+		}
+	}
+	return adapter.QuotaResult{
+		Status: status.WithMessage(rpc.UNAVAILABLE, err.Error()),
+	}
+}
+
+// recordConsumerResult counts a quota result in per-consumer metrics if enabled.
+func (q *quotaImpl) recordConsumerResult(consumerID string, success bool) {
+	if q.consumerMetrics != nil {
+		q.consumerMetrics.record(q.serviceConfig.GoogleServiceName, consumerID, methodQuota, success)
+	}
+}
+
+// doAllocateQuota calls AllocateQuota on Google ServiceControl client.
+func (q *quotaImpl) doAllocateQuota(ctx context.Context, consumerID, opName string, quotaCfg *config.Quota,
+	args adapter.QuotaArgs) (*sc.AllocateQuotaResponse, error) {
+	quotaMode := quotaModeNormal
+	if args.BestEffort {
+		quotaMode = quotaModeBestEffort
+	}
+
+	request := &sc.AllocateQuotaRequest{
+		AllocateOperation: &sc.QuotaOperation{
+			OperationId: q.operationID(args),
+			MethodName:  opName,
+			ConsumerId:  consumerID,
+			QuotaMode:   quotaMode,
+			QuotaMetrics: []*sc.MetricValueSet{
+				{
+					MetricName: quotaCfg.GoogleQuotaMetricName,
+					MetricValues: []*sc.MetricValue{
+						{
+							Int64Value: getInt64Address(args.QuotaAmount),
+						},
+					},
+				},
+			},
+		},
+	}
+	start := time.Now()
+	response, err := q.client.AllocateQuota(ctx, q.serviceConfig.GoogleServiceName, request)
+	allocateQuotaDuration.WithLabelValues(
+		q.serviceConfig.GoogleServiceName, quotaCfg.Name).Observe(time.Since(start).Seconds())
+	return response, err
+}
+
+// operationID returns the operation id of a quota call, the namespaced Mixer deduplication id if
+// enabled, or a new UUID.
+func (q *quotaImpl) operationID(args adapter.QuotaArgs) string {
+	if q.useDedupID {
+		return q.operationIDNamespace + args.DeduplicationID
+	}
+	return uuid.New()
+}
+
+// responseToQuotaResult converts ServiceControl AllocateQuotaResponse to Mixer QuotaResult.
+func (q *quotaImpl) responseToQuotaResult(response *sc.AllocateQuotaResponse,
+	quotaCfg *config.Quota, consumerID string, args adapter.QuotaArgs) adapter.QuotaResult {
+	result := adapter.QuotaResult{
+		Status:        status.OK,
+		ValidDuration: q.quotaExpiration(quotaCfg, consumerID),
+		Amount:        args.QuotaAmount,
+	}
+
+	if len(response.AllocateErrors) > 0 {
+		allocateError := response.AllocateErrors[0]
+		result.Status = status.WithMessage(serviceControlErrorToRPCCode(allocateError.Code),
+			fmt.Sprintf("%s: %s", allocateError.Code, allocateError.Description))
+		result.Amount = 0
+	}
+	return result
+}
+
+// quotaExpiration returns the expiration of the first override matching consumer, or the quota expiration.
+func (q *quotaImpl) quotaExpiration(quotaCfg *config.Quota, consumerID string) time.Duration {
+	for _, override := range q.expirationOverrides[quotaCfg.Name] {
+		if override.consumerPattern.MatchString(consumerID) {
+			return override.expiration
+		}
+	}
+	return toDuration(quotaCfg.Expiration)
+}
+
+func newQuotaProcessor(meshServiceName string, ctx *handlerContext,
+	resolver consumerProjectIDResolver) (*quotaImpl, error) {
+	serviceConfig, client, err := ctx.service(meshServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	quotaIndex := make(map[string]*config.Quota, len(serviceConfig.Quotas))
+	expirationOverrides := make(map[string][]expirationOverride)
+	for _, quotaCfg := range serviceConfig.Quotas {
+		quotaIndex[quotaCfg.Name] = quotaCfg
+		for _, override := range quotaCfg.ExpirationOverrides {
+			pattern, err := compileConsumerPattern(override.ConsumerPattern)
+			if err != nil {
+				return nil, err
+			}
+			expirationOverrides[quotaCfg.Name] = append(expirationOverrides[quotaCfg.Name],
+				expirationOverride{pattern, toDuration(override.Expiration)})
+		}
+	}
+
+	consumerFilter, err := newConsumerFilter(serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var coalescingWindow time.Duration
+	if ctx.config.RuntimeConfig.QuotaCoalescingWindow != nil {
+		coalescingWindow = toDuration(ctx.config.RuntimeConfig.QuotaCoalescingWindow)
+	}
+
+	var deadline time.Duration
+	if ctx.config.RuntimeConfig.QuotaDeadline != nil {
+		deadline = toDuration(ctx.config.RuntimeConfig.QuotaDeadline)
+	}
+
+	localQuotas := make(map[string]*localQuotaPool)
+	for _, quotaCfg := range serviceConfig.Quotas {
+		if quotaCfg.AllocationMode != config.BEST_EFFORT_LOCAL {
+			continue
+		}
+		var reconcileInterval time.Duration
+		if quotaCfg.ReconcileInterval != nil {
+			reconcileInterval = toDuration(quotaCfg.ReconcileInterval)
+		}
+		pool := newLocalQuotaPool(quotaCfg.PrefetchAmount, reconcileInterval, func(refill func()) {
+			ctx.env.ScheduleWork(refill)
+		})
+		ctx.env.ScheduleDaemon(pool.run)
+		localQuotas[quotaCfg.Name] = pool
+	}
+
+	return &quotaImpl{
+		ctx.env,
+		serviceConfig,
+		quotaIndex,
+		expirationOverrides,
+		client,
+		resolver,
+		ctx.config.RuntimeConfig.UseDedupIdAsOperationId,
+		ctx.config.RuntimeConfig.OperationIdNamespace,
+		deadline,
+		ctx.config.RuntimeConfig.QuotaFailOpenOnDeadline,
+		ctx.config.RuntimeConfig.NetworkFailPolicy == config.FAIL_OPEN,
+		ctx.consumerMetrics,
+		consumerFilter,
+		ctx.warningLogger,
+		newQuotaCoalescer(coalescingWindow),
+		localQuotas,
+	}, nil
+}
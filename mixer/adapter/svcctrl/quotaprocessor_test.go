@@ -0,0 +1,532 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	pbtypes "github.com/gogo/protobuf/types"
+	rpc "github.com/googleapis/googleapis/google/rpc"
+	"google.golang.org/api/googleapi"
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+	"istio.io/istio/mixer/adapter/svcctrl/template/svcctrlreport"
+	"istio.io/istio/mixer/pkg/adapter"
+	at "istio.io/istio/mixer/pkg/adapter/test"
+	"istio.io/istio/mixer/pkg/status"
+	"istio.io/istio/mixer/template/quota"
+)
+
+const testQuotaName = "ratelimit.quota.istio-system"
+const testConsumerProject = "project_number:12345"
+const testPeerIdentity = "spiffe://cluster.local/ns/default/sa/client"
+
+type quotaProcessorTest struct {
+	testConfig config.Params
+	mockClient *mockSvcctrlClient
+	quotaProc  *quotaImpl
+}
+
+func quotaProcessorTestSetup(t *testing.T) *quotaProcessorTest {
+	test := &quotaProcessorTest{
+		testConfig: config.Params{
+			RuntimeConfig: &config.RuntimeConfig{
+				CheckCacheSize: 10,
+				CheckResultExpiration: &pbtypes.Duration{
+					Seconds: 300,
+				},
+			},
+			ServiceConfigs: []*config.GcpServiceSetting{
+				{
+					MeshServiceName:   meshServiceName,
+					GoogleServiceName: gcpServiceName,
+					Quotas: []*config.Quota{
+						{
+							Name:                  testQuotaName,
+							GoogleQuotaMetricName: "read-requests",
+							Expiration: &pbtypes.Duration{
+								Seconds: 60,
+							},
+						},
+					},
+				},
+			},
+		},
+		mockClient: &mockSvcctrlClient{},
+	}
+
+	ctx, err := initializeHandlerContext(at.NewEnv(t), &test.testConfig, test.mockClient)
+	if err != nil {
+		t.Fatalf(`fail to initialize handleContext %v`, err)
+	}
+
+	quotaProc, err := newQuotaProcessor(meshServiceName, ctx, &mockConsumerProjectIDResolver{
+		consumerProjectID: testConsumerProject,
+	})
+	if err != nil {
+		t.Fatalf(`fail to create test quotaProcessor %v`, err)
+	}
+
+	test.quotaProc = quotaProc
+	test.mockClient.setQuotaAllocateRespone(&sc.AllocateQuotaResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 200,
+		},
+	})
+	return test
+}
+
+func getTestQuotaInstance() *quota.Instance {
+	return &quota.Instance{
+		Name: testQuotaName,
+		Dimensions: map[string]interface{}{
+			apiKeyDimension:       "test_key",
+			apiOperationDimension: "echo",
+		},
+	}
+}
+
+func TestProcessQuota(t *testing.T) {
+	test := quotaProcessorTestSetup(t)
+	result, err := test.quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(),
+		adapter.QuotaArgs{QuotaAmount: 10})
+	if err != nil {
+		t.Fatalf(`ProcessQuota() failed with %v`, err)
+	}
+
+	expectedResult := adapter.QuotaResult{
+		Status:        status.OK,
+		ValidDuration: 60 * time.Second,
+		Amount:        10,
+	}
+	if !reflect.DeepEqual(expectedResult, result) {
+		t.Errorf(`expect quota result %v, but get %v`, expectedResult, result)
+	}
+
+	op := test.mockClient.allocateQuotaRequest.AllocateOperation
+	if op.MethodName != "echo" || op.ConsumerId != "api_key:test_key" || op.QuotaMode != quotaModeNormal {
+		t.Errorf(`unexpected quota operation %v`, *op)
+	}
+	if len(op.QuotaMetrics) != 1 || op.QuotaMetrics[0].MetricName != "read-requests" ||
+		*op.QuotaMetrics[0].MetricValues[0].Int64Value != 10 {
+		t.Errorf(`unexpected quota metrics %v`, op.QuotaMetrics)
+	}
+}
+
+func TestProcessQuotaDurationRecorded(t *testing.T) {
+	test := quotaProcessorTestSetup(t)
+	count := getHistogramSampleCount(allocateQuotaDuration, gcpServiceName, testQuotaName)
+	if _, err := test.quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(),
+		adapter.QuotaArgs{QuotaAmount: 10}); err != nil {
+		t.Fatalf(`ProcessQuota() failed with %v`, err)
+	}
+	// Failed calls are recorded too.
+	test.mockClient.setQuotaAllocateRespone(nil)
+	_, _ = test.quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(), adapter.QuotaArgs{QuotaAmount: 10})
+
+	if actual := getHistogramSampleCount(allocateQuotaDuration, gcpServiceName, testQuotaName); actual != count+2 {
+		t.Errorf(`expect %v AllocateQuota durations, but get %v`, count+2, actual)
+	}
+}
+
+func TestProcessQuotaWithAllocateError(t *testing.T) {
+	test := quotaProcessorTestSetup(t)
+	test.mockClient.setQuotaAllocateRespone(&sc.AllocateQuotaResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 200,
+		},
+		AllocateErrors: []*sc.QuotaError{
+			{
+				Code:        "RESOURCE_EXHAUSTED",
+				Description: "quota exhausted",
+			},
+		},
+	})
+
+	result, _ := test.quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(),
+		adapter.QuotaArgs{QuotaAmount: 10})
+	if result.Status.Code != int32(rpc.RESOURCE_EXHAUSTED) || result.Amount != 0 {
+		t.Errorf(`expect RESOURCE_EXHAUSTED with no quota granted, but get %v`, result)
+	}
+}
+
+func TestProcessQuotaInvalidInstance(t *testing.T) {
+	test := quotaProcessorTestSetup(t)
+	instances := []*quota.Instance{
+		{
+			Name: testQuotaName,
+			Dimensions: map[string]interface{}{
+				apiOperationDimension: "echo",
+			},
+		},
+	}
+
+	for _, instance := range instances {
+		result, _ := test.quotaProc.ProcessQuota(context.Background(), instance,
+			adapter.QuotaArgs{QuotaAmount: 10})
+		if result.Status.Code != int32(rpc.INVALID_ARGUMENT) {
+			t.Errorf(`expect INVALID_ARGUMENT for instance %v, but get %v`, *instance, result)
+		}
+	}
+	if test.mockClient.allocateQuotaRequest != nil {
+		t.Error(`expect no AllocateQuota call for invalid instances`)
+	}
+}
+
+func TestProcessQuotaUnknownQuota(t *testing.T) {
+	test := quotaProcessorTestSetup(t)
+	result, err := test.quotaProc.ProcessQuota(context.Background(), &quota.Instance{
+		Name:       "unknown_quota",
+		Dimensions: getTestQuotaInstance().Dimensions,
+	}, adapter.QuotaArgs{QuotaAmount: 10})
+	if err != nil || !status.IsOK(result.Status) || result.Amount != 10 {
+		t.Errorf(`expect quota not in service config to be granted, but get %v, %v`, result, err)
+	}
+	if test.mockClient.allocateQuotaRequest != nil {
+		t.Error(`expect no AllocateQuota call for quota not in service config`)
+	}
+}
+
+func TestProcessQuotaClientError(t *testing.T) {
+	test := quotaProcessorTestSetup(t)
+	test.mockClient.setQuotaAllocateRespone(nil)
+	result, _ := test.quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(),
+		adapter.QuotaArgs{QuotaAmount: 10})
+	if result.Status.Code != int32(rpc.UNAVAILABLE) {
+		t.Errorf(`expect UNAVAILABLE, but get %v`, result)
+	}
+}
+
+func TestQuotaAndReportConsumer(t *testing.T) {
+	testCases := []struct {
+		quotaConsumer          config.GcpServiceSetting_ConsumerSource
+		reportConsumer         config.GcpServiceSetting_ConsumerSource
+		expectedQuotaConsumer  string
+		expectedReportConsumer string
+	}{
+		{config.DEFAULT_CONSUMER, config.DEFAULT_CONSUMER, "api_key:test_key", "api_key:test_key"},
+		{config.API_KEY, config.CONSUMER_PROJECT, "api_key:test_key", testConsumerProject},
+		{config.CONSUMER_PROJECT, config.API_KEY, testConsumerProject, "api_key:test_key"},
+		{config.PEER_IDENTITY, config.PEER_IDENTITY, "project:client-project", "project:client-project"},
+		{config.PEER_IDENTITY, config.API_KEY, "project:client-project", "api_key:test_key"},
+	}
+
+	for _, tc := range testCases {
+		test := quotaProcessorTestSetup(t)
+		serviceConfig := test.testConfig.ServiceConfigs[0]
+		serviceConfig.QuotaConsumer = tc.quotaConsumer
+		serviceConfig.ReportConsumer = tc.reportConsumer
+		serviceConfig.PeerIdentityAttribute = "source.user"
+		serviceConfig.PeerIdentityProjects = map[string]string{testPeerIdentity: "client-project"}
+		quotaInstance := getTestQuotaInstance()
+		quotaInstance.Dimensions["source.user"] = testPeerIdentity
+		reportInstance := getTestReportInstance()
+		reportInstance.Attributes = map[string]string{"source.user": testPeerIdentity}
+		reportProc := &reportImpl{
+			env:           test.quotaProc.env,
+			serviceConfig: serviceConfig,
+			client:        test.mockClient,
+			resolver:      test.quotaProc.resolver,
+			warningLogger: newRateLimitedLogger(test.quotaProc.env.Logger(), 0),
+		}
+		test.mockClient.setReportResponse(&sc.ReportResponse{})
+
+		_, _ = test.quotaProc.ProcessQuota(context.Background(), quotaInstance,
+			adapter.QuotaArgs{QuotaAmount: 1})
+		_ = reportProc.ProcessReport(context.Background(),
+			[]*svcctrlreport.Instance{reportInstance})
+
+		if actual := test.mockClient.allocateQuotaRequest.AllocateOperation.ConsumerId; actual != tc.expectedQuotaConsumer {
+			t.Errorf(`expect quota consumer %v, but get %v`, tc.expectedQuotaConsumer, actual)
+		}
+		if actual := test.mockClient.reportRequest.Operations[0].ConsumerId; actual != tc.expectedReportConsumer {
+			t.Errorf(`expect report consumer %v, but get %v`, tc.expectedReportConsumer, actual)
+		}
+	}
+}
+
+func TestProcessQuotaPeerIdentityConsumer(t *testing.T) {
+	test := quotaProcessorTestSetup(t)
+	serviceConfig := test.testConfig.ServiceConfigs[0]
+	serviceConfig.QuotaConsumer = config.PEER_IDENTITY
+	serviceConfig.ReportConsumer = config.PEER_IDENTITY
+	serviceConfig.PeerIdentityAttribute = "source.user"
+	serviceConfig.PeerIdentityProjects = map[string]string{testPeerIdentity: "client-project"}
+
+	// API key isn't required when consumer is identified by peer identity.
+	instance := &quota.Instance{
+		Name: testQuotaName,
+		Dimensions: map[string]interface{}{
+			apiOperationDimension: "echo",
+			"source.user":         testPeerIdentity,
+		},
+	}
+	result, err := test.quotaProc.ProcessQuota(context.Background(), instance, adapter.QuotaArgs{QuotaAmount: 1})
+	if err != nil {
+		t.Fatalf(`ProcessQuota() failed with %v`, err)
+	}
+	if !status.IsOK(result.Status) {
+		t.Errorf(`expect quota to be granted, but get %v`, result)
+	}
+	if actual := test.mockClient.allocateQuotaRequest.AllocateOperation.ConsumerId; actual != "project:client-project" {
+		t.Errorf(`expect project of peer identity as consumer, but get %v`, actual)
+	}
+
+	// Quota is denied without peer identity, or with one without project.
+	for _, peerIdentity := range []interface{}{nil, "spiffe://cluster.local/ns/default/sa/other"} {
+		instance.Dimensions["source.user"] = peerIdentity
+		result, _ = test.quotaProc.ProcessQuota(context.Background(), instance, adapter.QuotaArgs{QuotaAmount: 1})
+		if result.Status.Code != int32(rpc.PERMISSION_DENIED) {
+			t.Errorf(`expect PERMISSION_DENIED with peer identity %v, but get %v`, peerIdentity, result)
+		}
+	}
+}
+
+func TestProcessQuotaDedupOperationID(t *testing.T) {
+	test := quotaProcessorTestSetup(t)
+	args := adapter.QuotaArgs{QuotaAmount: 1, DeduplicationID: "test_dedup_id"}
+
+	// Operation ids are generated by default.
+	_, _ = test.quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(), args)
+	if actual := test.mockClient.allocateQuotaRequest.AllocateOperation.OperationId; actual == "" || actual == args.DeduplicationID {
+		t.Errorf(`expect generated operation id, but get %v`, actual)
+	}
+
+	test.quotaProc.useDedupID = true
+	test.quotaProc.operationIDNamespace = "istio-"
+	_, _ = test.quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(), args)
+	if actual := test.mockClient.allocateQuotaRequest.AllocateOperation.OperationId; actual != "istio-test_dedup_id" {
+		t.Errorf(`expect namespaced dedup id as operation id, but get %v`, actual)
+	}
+
+	test.mockClient.reset()
+	result, _ := test.quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(), adapter.QuotaArgs{QuotaAmount: 1})
+	if result.Status.Code != int32(rpc.INVALID_ARGUMENT) {
+		t.Errorf(`expect INVALID_ARGUMENT without dedup id, but get %v`, result)
+	}
+	if test.mockClient.allocateQuotaRequest != nil {
+		t.Error(`expect no quota request without dedup id`)
+	}
+}
+
+func TestProcessQuotaDeadline(t *testing.T) {
+	test := quotaProcessorTestSetup(t)
+	test.quotaProc.deadline = time.Minute
+	result, err := test.quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(),
+		adapter.QuotaArgs{QuotaAmount: 10})
+	if err != nil {
+		t.Fatalf(`ProcessQuota() failed with %v`, err)
+	}
+	if !status.IsOK(result.Status) || result.Amount != 10 || result.ValidDuration != 60*time.Second {
+		t.Errorf(`expect quota allocated within deadline, but get %v`, result)
+	}
+
+	testCases := []struct {
+		failOpen          bool
+		expectedResult    adapter.QuotaResult
+		expectedFailOpens float64
+	}{
+		{false, adapter.QuotaResult{
+			Status: status.WithDeadlineExceeded(
+				fmt.Sprintf("quota %s allocation exceeds deadline %v", testQuotaName, 10*time.Millisecond)),
+		}, 0},
+		{true, adapter.QuotaResult{
+			Status:        status.OK,
+			ValidDuration: quotaDeadlineResultExpiration,
+			Amount:        10,
+		}, 1},
+	}
+	for _, tc := range testCases {
+		test := quotaProcessorTestSetup(t)
+		test.quotaProc.deadline = 10 * time.Millisecond
+		test.quotaProc.failOpenOnDeadline = tc.failOpen
+		// AllocateQuota fails without response once unblocked.
+		test.mockClient.allocateQuotaResponse = nil
+		block := make(chan struct{})
+		test.mockClient.allocateQuotaBlock = block
+
+		failOpens := getCounterValue(failOpenCount, gcpServiceName, methodQuota)
+		start := time.Now()
+		// AllocateQuota is cancelled on the deadline, so ProcessQuota returns while it's still blocked.
+		result, err := test.quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(),
+			adapter.QuotaArgs{QuotaAmount: 10})
+		close(block)
+		if err != nil {
+			t.Fatalf(`ProcessQuota() failed with %v`, err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf(`expect quota call to be capped, but it takes %v`, elapsed)
+		}
+		if !reflect.DeepEqual(tc.expectedResult, result) {
+			t.Errorf(`expect quota result %v, but get %v`, tc.expectedResult, result)
+		}
+		if actual := getCounterValue(failOpenCount, gcpServiceName, methodQuota); actual != failOpens+tc.expectedFailOpens {
+			t.Errorf(`expect %v quota fail-opens, but get %v`, failOpens+tc.expectedFailOpens, actual)
+		}
+	}
+}
+
+func TestProcessQuotaSuppressed(t *testing.T) {
+	test := quotaProcessorTestSetup(t)
+	test.testConfig.ServiceConfigs[0].SuppressReportAttribute = "request.synthetic"
+	// Suppressed requests may not carry API key.
+	instance := &quota.Instance{
+		Name: testQuotaName,
+		Dimensions: map[string]interface{}{
+			"request.synthetic": "true",
+		},
+	}
+
+	count := getCounterValue(suppressedCallCount, gcpServiceName, methodQuota)
+	result, err := test.quotaProc.ProcessQuota(context.Background(), instance, adapter.QuotaArgs{QuotaAmount: 10})
+	if err != nil {
+		t.Fatalf(`ProcessQuota() failed with %v`, err)
+	}
+	expectedResult := adapter.QuotaResult{
+		Status:        status.OK,
+		ValidDuration: 60 * time.Second,
+		Amount:        10,
+	}
+	if !reflect.DeepEqual(expectedResult, result) {
+		t.Errorf(`expect quota result %v, but get %v`, expectedResult, result)
+	}
+	if test.mockClient.allocateQuotaRequest != nil {
+		t.Errorf(`expect no quota request, but get %v`, *test.mockClient.allocateQuotaRequest)
+	}
+	if actual := getCounterValue(suppressedCallCount, gcpServiceName, methodQuota); actual != count+1 {
+		t.Errorf(`expect %v suppressed quota calls, but get %v`, count+1, actual)
+	}
+
+	// Requests not marked are allocated as usual.
+	_, _ = test.quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(), adapter.QuotaArgs{QuotaAmount: 10})
+	if test.mockClient.allocateQuotaRequest == nil {
+		t.Error(`expect quota request of request not suppressed`)
+	}
+}
+
+func TestProcessQuotaExpirationOverride(t *testing.T) {
+	testCases := []struct {
+		quotaConsumer      config.GcpServiceSetting_ConsumerSource
+		overrides          []*config.Quota_ExpirationOverride
+		expectedExpiration time.Duration
+	}{
+		{config.API_KEY, nil, 60 * time.Second},
+		{
+			config.CONSUMER_PROJECT,
+			[]*config.Quota_ExpirationOverride{
+				{ConsumerPattern: "api_key:.*", Expiration: &pbtypes.Duration{Seconds: 30}},
+				{ConsumerPattern: "project_number:123", Expiration: &pbtypes.Duration{Seconds: 20}},
+				{ConsumerPattern: "project_number:12345", Expiration: &pbtypes.Duration{Seconds: 10}},
+			},
+			10 * time.Second,
+		},
+		{
+			config.API_KEY,
+			[]*config.Quota_ExpirationOverride{
+				{ConsumerPattern: "api_key:.*", Expiration: &pbtypes.Duration{Seconds: 30}},
+				{ConsumerPattern: "api_key:test_key", Expiration: &pbtypes.Duration{Seconds: 20}},
+			},
+			30 * time.Second,
+		},
+		{
+			config.API_KEY,
+			[]*config.Quota_ExpirationOverride{
+				{ConsumerPattern: "project_number:.*", Expiration: &pbtypes.Duration{Seconds: 30}},
+			},
+			60 * time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		test := quotaProcessorTestSetup(t)
+		serviceConfig := test.testConfig.ServiceConfigs[0]
+		serviceConfig.QuotaConsumer = tc.quotaConsumer
+		serviceConfig.ReportConsumer = tc.quotaConsumer
+		serviceConfig.Quotas[0].ExpirationOverrides = tc.overrides
+
+		ctx, err := initializeHandlerContext(at.NewEnv(t), &test.testConfig, test.mockClient)
+		if err != nil {
+			t.Fatalf(`fail to initialize handleContext %v`, err)
+		}
+		quotaProc, err := newQuotaProcessor(meshServiceName, ctx, test.quotaProc.resolver)
+		if err != nil {
+			t.Fatalf(`fail to create test quotaProcessor %v`, err)
+		}
+
+		result, err := quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(),
+			adapter.QuotaArgs{QuotaAmount: 1})
+		if err != nil {
+			t.Fatalf(`ProcessQuota() failed with %v`, err)
+		}
+		if result.ValidDuration != tc.expectedExpiration {
+			t.Errorf(`expect expiration %v for overrides %v, but get %v`,
+				tc.expectedExpiration, tc.overrides, result.ValidDuration)
+		}
+	}
+}
+
+func TestProcessQuotaConsumerFilter(t *testing.T) {
+	test := quotaProcessorTestSetup(t)
+	test.quotaProc.consumerFilter, _ = newConsumerFilter(&config.GcpServiceSetting{
+		ConsumerAllowlist: []string{"api_key:allowed_.*"},
+	})
+
+	count := getCounterValue(rejectedConsumerCount, gcpServiceName, methodQuota, rejectReasonNotAllowed)
+	result, err := test.quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(),
+		adapter.QuotaArgs{QuotaAmount: 10})
+	if err != nil {
+		t.Fatalf(`ProcessQuota() failed with %v`, err)
+	}
+	if result.Status.Code != int32(rpc.PERMISSION_DENIED) || result.Amount != 0 {
+		t.Errorf(`expect consumer not in allowlist to be rejected, but get %v`, result)
+	}
+	if test.mockClient.allocateQuotaRequest != nil {
+		t.Errorf(`expect no quota request, but get %v`, *test.mockClient.allocateQuotaRequest)
+	}
+	if actual := getCounterValue(rejectedConsumerCount, gcpServiceName, methodQuota, rejectReasonNotAllowed); actual != count+1 {
+		t.Errorf(`expect %v rejected quota calls, but get %v`, count+1, actual)
+	}
+
+	instance := getTestQuotaInstance()
+	instance.Dimensions[apiKeyDimension] = "allowed_key"
+	result, err = test.quotaProc.ProcessQuota(context.Background(), instance, adapter.QuotaArgs{QuotaAmount: 10})
+	if err != nil || !status.IsOK(result.Status) || test.mockClient.allocateQuotaRequest == nil {
+		t.Errorf(`expect allowed consumer to be allocated, but get %v, %v`, result, err)
+	}
+}
+
+func TestProcessQuotaNetworkFailOpen(t *testing.T) {
+	test := quotaProcessorTestSetup(t)
+	test.quotaProc.failOpenOnUnreachable = true
+	test.mockClient.allocateQuotaError = &googleapi.Error{Code: 503}
+	result, err := test.quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(),
+		adapter.QuotaArgs{QuotaAmount: 10})
+	if err != nil || !status.IsOK(result.Status) || result.Amount != 10 || result.ValidDuration != failOpenResultExpiration {
+		t.Errorf(`expect quota granted briefly while Google ServiceControl is unreachable, but get %v, %v`, result, err)
+	}
+
+	test.mockClient.allocateQuotaError = nil
+	test.mockClient.setQuotaAllocateRespone(nil)
+	result, _ = test.quotaProc.ProcessQuota(context.Background(), getTestQuotaInstance(),
+		adapter.QuotaArgs{QuotaAmount: 10})
+	if result.Status.Code != int32(rpc.UNAVAILABLE) {
+		t.Errorf(`expect UNAVAILABLE on other client errors, but get %v`, result)
+	}
+}
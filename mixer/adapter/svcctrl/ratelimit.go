@@ -0,0 +1,84 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"sync"
+	"time"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+	"istio.io/istio/mixer/pkg/cache"
+)
+
+// Check results denied by the local rate limit are cached for a short time, so that
+// the API key is allowed again once its bucket refills.
+const rateLimitedResultExpiration = time.Second
+
+type (
+	tokenBucket struct {
+		tokens float64
+		last   time.Time
+	}
+
+	// apiKeyRateLimiter rate limits calls of each API key with a token bucket. Buckets
+	// are kept in a LRU cache, so that memory is bounded regardless of distinct API keys.
+	apiKeyRateLimiter struct {
+		ratePerSecond float64
+		burst         float64
+		now           func() time.Time
+
+		lock    sync.Mutex // guards buckets
+		buckets cache.ExpiringCache
+	}
+)
+
+// allow returns true if a call of the API key is within the rate limit.
+func (l *apiKeyRateLimiter) allow(apiKey string) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	now := l.now()
+	var bucket *tokenBucket
+	if value, found := l.buckets.Get(apiKey); found {
+		bucket = value.(*tokenBucket)
+		bucket.tokens += now.Sub(bucket.last).Seconds() * l.ratePerSecond
+		if bucket.tokens > l.burst {
+			bucket.tokens = l.burst
+		}
+		bucket.last = now
+	} else {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets.Set(apiKey, bucket)
+	}
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// newAPIKeyRateLimiter creates apiKeyRateLimiter from config, returns nil if it's not configured.
+func newAPIKeyRateLimiter(cfg *config.ApiKeyRateLimit) *apiKeyRateLimiter {
+	if cfg == nil {
+		return nil
+	}
+	return &apiKeyRateLimiter{
+		ratePerSecond: cfg.RequestsPerSecond,
+		burst:         float64(cfg.Burst),
+		now:           time.Now,
+		// Buckets are only evicted when the cache is full.
+		buckets: cache.NewLRU(0, 0, int(cfg.MaxTrackedKeys)),
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rpc "github.com/googleapis/googleapis/google/rpc"
+	"google.golang.org/api/googleapi"
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+	"istio.io/istio/mixer/pkg/status"
+	"istio.io/istio/mixer/template/apikey"
+)
+
+func TestAPIKeyRateLimiter(t *testing.T) {
+	now := time.Now()
+	limiter := newAPIKeyRateLimiter(&config.ApiKeyRateLimit{
+		RequestsPerSecond: 2,
+		Burst:             3,
+		MaxTrackedKeys:    2,
+	})
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if !limiter.allow("key_a") {
+			t.Fatalf(`expect call %d within burst to be allowed`, i)
+		}
+	}
+	if limiter.allow("key_a") {
+		t.Error(`expect call exceeding burst to be denied`)
+	}
+	if !limiter.allow("key_b") {
+		t.Error(`expect other api key to be allowed`)
+	}
+
+	// Half a second refills one token.
+	now = now.Add(500 * time.Millisecond)
+	if !limiter.allow("key_a") {
+		t.Error(`expect call to be allowed after refill`)
+	}
+	if limiter.allow("key_a") {
+		t.Error(`expect call to be denied after refilled token is used`)
+	}
+
+	// Tracking a third key forgets the least recently seen key_b, but key_a is still limited.
+	if !limiter.allow("key_c") {
+		t.Error(`expect new api key to be allowed`)
+	}
+	if limiter.allow("key_a") {
+		t.Error(`expect recently seen api key to stay limited`)
+	}
+
+	if newAPIKeyRateLimiter(nil) != nil {
+		t.Error(`expect nil apiKeyRateLimiter when not configured`)
+	}
+}
+
+func TestProcessCheckAPIKeyRateLimit(t *testing.T) {
+	test := checkProcessorTestSetup(t)
+	now := time.Now()
+	limiter := newAPIKeyRateLimiter(&config.ApiKeyRateLimit{
+		RequestsPerSecond: 1,
+		Burst:             1,
+		MaxTrackedKeys:    10,
+	})
+	limiter.now = func() time.Time { return now }
+	test.checkProc.rateLimiter = limiter
+	test.mockClient.setCheckResponse(&sc.CheckResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 200,
+		},
+	})
+
+	instance := &apikey.Instance{
+		ApiKey:       "test_key",
+		ApiOperation: "echo",
+		Timestamp:    now,
+	}
+	result, err := test.checkProc.ProcessCheck(context.Background(), instance)
+	if err != nil {
+		t.Fatalf(`ProcessCheck() failed with %v`, err)
+	}
+	if test.mockClient.checkRequest == nil {
+		t.Fatal(`expect check request within rate limit`)
+	}
+	// Allows are not reused by Mixer, so that every check reaches the rate limiter.
+	if !status.IsOK(result.Status) || result.ValidUseCount != 1 {
+		t.Errorf(`expect allow good for one use, but get %v`, result)
+	}
+
+	test.mockClient.reset()
+	result, err = test.checkProc.ProcessCheck(context.Background(), instance)
+	if err != nil {
+		t.Fatalf(`ProcessCheck() failed with %v`, err)
+	}
+	if result.Status.Code != int32(rpc.RESOURCE_EXHAUSTED) || result.ValidDuration != rateLimitedResultExpiration {
+		t.Errorf(`expect check to be rate limited, but get %v`, result)
+	}
+	if test.mockClient.checkRequest != nil {
+		t.Errorf(`expect no check request when rate limited, but get %v`, *test.mockClient.checkRequest)
+	}
+}
@@ -0,0 +1,322 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	sc "google.golang.org/api/servicecontrol/v1"
+)
+
+const (
+	// Operations are flushed once this many are buffered when the batch size is not configured.
+	defaultMaxReportBatchSize = 1000
+
+	// Operations are dropped once this many batches are buffered, e.g. when flushes fall behind.
+	maxBufferedReportBatches = 10
+
+	// Estimated bytes of the fixed fields of an operation, a metric value and a log entry.
+	operationOverheadBytes   = 256
+	metricValueOverheadBytes = 128
+	logEntryOverheadBytes    = 128
+)
+
+// reportBatcher buffers report operations, merges operations of the same consumer, operation
+// name and labels, and flushes them with send on the interval of its flush pool, once a batch
+// is full, or once the estimated size of buffered operations exceeds maxBufferBytes. Up to
+// maxBufferedReportBatches batches, or twice maxBufferBytes, are buffered, operations beyond are
+// dropped.
+type reportBatcher struct {
+	service      string
+	pool         *reportFlushPool
+	maxBatchSize int
+	// Zero when buffered operations are not bounded by size.
+	maxBufferBytes int64
+	send           func(operations []*sc.Operation) error
+	warningLogger  *rateLimitedLogger
+
+	flushLock sync.Mutex // serializes flushes, so that close waits for a flush in progress
+
+	lock       sync.Mutex      // guards operations, signatures and bytes
+	operations []*sc.Operation // in arrival order
+	signatures map[string]*sc.Operation
+	// Estimated size of operations, also added to reportBufferBytes.
+	bytes int64
+}
+
+// add buffers operations, merging them into buffered operations where possible.
+func (b *reportBatcher) add(operations []*sc.Operation) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	dropped := 0
+	var added int64
+	for _, op := range operations {
+		signature := operationSignature(op)
+		if buffered, found := b.signatures[signature]; found && mergeOperation(buffered, op) {
+			// Merged metric values take no more room, merged log entries do.
+			added += estimateLogEntriesSize(op.LogEntries)
+			continue
+		}
+		if len(b.operations) >= maxBufferedReportBatches*b.maxBatchSize ||
+			(b.maxBufferBytes > 0 && b.bytes+added >= 2*b.maxBufferBytes) {
+			dropped++
+			continue
+		}
+		b.signatures[signature] = op
+		b.operations = append(b.operations, op)
+		added += estimateOperationSize(op)
+	}
+	b.bytes += added
+	reportBufferBytes.WithLabelValues(b.service).Add(float64(added))
+	if dropped > 0 {
+		b.warningLogger.Warningf("drop %d operations: %d operations of %d bytes are buffered",
+			dropped, len(b.operations), b.bytes)
+		droppedOperationCount.WithLabelValues(b.service, dropReasonBufferFull).Add(float64(dropped))
+	}
+	if len(b.operations) >= b.maxBatchSize || (b.maxBufferBytes > 0 && b.bytes >= b.maxBufferBytes) {
+		b.pool.requestFlush(b)
+	}
+}
+
+// flush sends buffered operations in batches of up to maxBatchSize operations, and returns the
+// number of operations sent. Failed batches are logged, since there is no caller to return errors to.
+func (b *reportBatcher) flush() int {
+	b.flushLock.Lock()
+	defer b.flushLock.Unlock()
+	b.lock.Lock()
+	operations := b.operations
+	b.operations = nil
+	b.signatures = make(map[string]*sc.Operation)
+	reportBufferBytes.WithLabelValues(b.service).Sub(float64(b.bytes))
+	b.bytes = 0
+	b.lock.Unlock()
+
+	flushed := len(operations)
+	for len(operations) > 0 {
+		size := len(operations)
+		if size > b.maxBatchSize {
+			size = b.maxBatchSize
+		}
+		if err := b.send(operations[:size]); err != nil {
+			b.warningLogger.Warningf("fail to flush %d report operations: %v", size, err)
+		}
+		operations = operations[size:]
+	}
+	return flushed
+}
+
+// close stops flushing on the interval, and flushes operations still buffered.
+func (b *reportBatcher) close() {
+	b.pool.unregister(b)
+	b.flush()
+}
+
+// estimateOperationSize returns the estimated bytes op takes in memory.
+func estimateOperationSize(op *sc.Operation) int64 {
+	size := int64(operationOverheadBytes + len(op.OperationId) + len(op.OperationName) + len(op.ConsumerId) +
+		len(op.StartTime) + len(op.EndTime))
+	size += estimateLabelsSize(op.Labels)
+	for _, set := range op.MetricValueSets {
+		size += int64(len(set.MetricName))
+		for _, value := range set.MetricValues {
+			size += metricValueOverheadBytes + estimateLabelsSize(value.Labels)
+			if value.DistributionValue != nil {
+				size += int64(8 * (len(value.DistributionValue.BucketCounts)))
+			}
+		}
+	}
+	return size + estimateLogEntriesSize(op.LogEntries)
+}
+
+// estimateLogEntriesSize returns the estimated bytes entries take in memory.
+func estimateLogEntriesSize(entries []*sc.LogEntry) int64 {
+	var size int64
+	for _, entry := range entries {
+		size += int64(logEntryOverheadBytes + len(entry.Name) + len(entry.InsertId) + len(entry.Severity) +
+			len(entry.TextPayload) + len(entry.StructPayload) + len(entry.ProtoPayload) + len(entry.Timestamp))
+		size += estimateLabelsSize(entry.Labels)
+	}
+	return size
+}
+
+func estimateLabelsSize(labels map[string]string) int64 {
+	var size int64
+	for label, value := range labels {
+		size += int64(len(label) + len(value))
+	}
+	return size
+}
+
+// operationSignature returns the key of operations which can be merged.
+func operationSignature(op *sc.Operation) string {
+	labels := make([]string, 0, len(op.Labels))
+	for label, value := range op.Labels {
+		labels = append(labels, label+"="+value)
+	}
+	sort.Strings(labels)
+	return op.ConsumerId + "\x00" + op.OperationName + "\x00" + strings.Join(labels, "\x00")
+}
+
+// mergeOperation merges metric values and log entries of src into dst, returns false and leaves
+// dst unchanged if their metric values can't be merged.
+func mergeOperation(dst, src *sc.Operation) bool {
+	merged := make([]*sc.MetricValueSet, len(dst.MetricValueSets))
+	for i, dstSet := range dst.MetricValueSets {
+		merged[i] = &sc.MetricValueSet{
+			MetricName:   dstSet.MetricName,
+			MetricValues: append([]*sc.MetricValue(nil), dstSet.MetricValues...),
+		}
+	}
+	for _, srcSet := range src.MetricValueSets {
+		var dstSet *sc.MetricValueSet
+		for _, set := range merged {
+			if set.MetricName == srcSet.MetricName {
+				dstSet = set
+				break
+			}
+		}
+		if dstSet == nil {
+			merged = append(merged, srcSet)
+			continue
+		}
+		if len(dstSet.MetricValues) != 1 || len(srcSet.MetricValues) != 1 {
+			return false
+		}
+		value, ok := mergeMetricValue(dstSet.MetricValues[0], srcSet.MetricValues[0])
+		if !ok {
+			return false
+		}
+		dstSet.MetricValues[0] = value
+	}
+
+	dst.MetricValueSets = merged
+	dst.LogEntries = append(dst.LogEntries, src.LogEntries...)
+	dst.StartTime = earlierTime(dst.StartTime, src.StartTime)
+	dst.EndTime = laterTime(dst.EndTime, src.EndTime)
+	return true
+}
+
+// mergeMetricValue returns the sum of metric values a and b, or false if they are of different
+// types or labels.
+func mergeMetricValue(a, b *sc.MetricValue) (*sc.MetricValue, bool) {
+	if !reflect.DeepEqual(a.Labels, b.Labels) {
+		return nil, false
+	}
+	merged := &sc.MetricValue{
+		Labels:    a.Labels,
+		StartTime: earlierTime(a.StartTime, b.StartTime),
+		EndTime:   laterTime(a.EndTime, b.EndTime),
+	}
+	switch {
+	case a.Int64Value != nil && b.Int64Value != nil:
+		merged.Int64Value = getInt64Address(*a.Int64Value + *b.Int64Value)
+	case a.DoubleValue != nil && b.DoubleValue != nil:
+		sum := *a.DoubleValue + *b.DoubleValue
+		merged.DoubleValue = &sum
+	case a.DistributionValue != nil && b.DistributionValue != nil:
+		distribution, ok := mergeDistribution(a.DistributionValue, b.DistributionValue)
+		if !ok {
+			return nil, false
+		}
+		merged.DistributionValue = distribution
+	default:
+		return nil, false
+	}
+	return merged, true
+}
+
+// mergeDistribution returns the distribution of samples of a and b, or false if their buckets differ.
+func mergeDistribution(a, b *sc.Distribution) (*sc.Distribution, bool) {
+	if !reflect.DeepEqual(a.ExponentialBuckets, b.ExponentialBuckets) || a.ExplicitBuckets != nil ||
+		b.ExplicitBuckets != nil || a.LinearBuckets != nil || b.LinearBuckets != nil ||
+		len(a.BucketCounts) != len(b.BucketCounts) {
+		return nil, false
+	}
+	if a.Count == 0 {
+		return b, true
+	}
+	if b.Count == 0 {
+		return a, true
+	}
+
+	merged := &sc.Distribution{
+		BucketCounts:       make([]int64, len(a.BucketCounts)),
+		Count:              a.Count + b.Count,
+		ExponentialBuckets: a.ExponentialBuckets,
+		Minimum:            a.Minimum,
+		Maximum:            a.Maximum,
+	}
+	for i := range a.BucketCounts {
+		merged.BucketCounts[i] = a.BucketCounts[i] + b.BucketCounts[i]
+	}
+	if b.Minimum < merged.Minimum {
+		merged.Minimum = b.Minimum
+	}
+	if b.Maximum > merged.Maximum {
+		merged.Maximum = b.Maximum
+	}
+	// Combines means and squared deviations of both sets of samples, see
+	// https://en.wikipedia.org/wiki/Algorithms_for_calculating_variance#Parallel_algorithm
+	delta := b.Mean - a.Mean
+	merged.Mean = a.Mean + delta*float64(b.Count)/float64(merged.Count)
+	merged.SumOfSquaredDeviation = a.SumOfSquaredDeviation + b.SumOfSquaredDeviation +
+		delta*delta*float64(a.Count)*float64(b.Count)/float64(merged.Count)
+	return merged, true
+}
+
+// earlierTime returns the earlier one of RFC3339 timestamps a and b, or a if either can't be parsed.
+func earlierTime(a, b string) string {
+	ta, errA := time.Parse(time.RFC3339Nano, a)
+	tb, errB := time.Parse(time.RFC3339Nano, b)
+	if errA == nil && errB == nil && tb.Before(ta) {
+		return b
+	}
+	return a
+}
+
+// laterTime returns the later one of RFC3339 timestamps a and b, or a if either can't be parsed.
+func laterTime(a, b string) string {
+	ta, errA := time.Parse(time.RFC3339Nano, a)
+	tb, errB := time.Parse(time.RFC3339Nano, b)
+	if errA == nil && errB == nil && tb.After(ta) {
+		return b
+	}
+	return a
+}
+
+// newReportBatcher creates reportBatcher flushing operations of service with send, and registers
+// it with pool. maxBatchSize defaults to defaultMaxReportBatchSize if it's not positive, buffered
+// operations are not bounded by size if maxBufferBytes is not positive.
+func newReportBatcher(service string, pool *reportFlushPool, maxBatchSize int, maxBufferBytes int64,
+	send func([]*sc.Operation) error, warningLogger *rateLimitedLogger) *reportBatcher {
+	if maxBatchSize <= 0 {
+		maxBatchSize = defaultMaxReportBatchSize
+	}
+	b := &reportBatcher{
+		service:        service,
+		pool:           pool,
+		maxBatchSize:   maxBatchSize,
+		maxBufferBytes: maxBufferBytes,
+		send:           send,
+		warningLogger:  warningLogger,
+		signatures:     make(map[string]*sc.Operation),
+	}
+	pool.register(b)
+	return b
+}
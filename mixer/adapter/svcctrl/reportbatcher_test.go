@@ -0,0 +1,261 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	pbtypes "github.com/gogo/protobuf/types"
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/template/svcctrlreport"
+	at "istio.io/istio/mixer/pkg/adapter/test"
+)
+
+func getTestBatchedOperation(consumerID string, count int64, latency float64, start, end string) *sc.Operation {
+	builder, _ := newDistValueBuilder(timeOption)
+	builder.addSample(latency)
+	return &sc.Operation{
+		OperationName: "echo",
+		ConsumerId:    consumerID,
+		StartTime:     start,
+		EndTime:       end,
+		Labels:        map[string]string{"/response_code": "200"},
+		MetricValueSets: []*sc.MetricValueSet{
+			{
+				MetricName:   "request_count",
+				MetricValues: []*sc.MetricValue{{Int64Value: getInt64Address(count)}},
+			},
+			{
+				MetricName:   "backend_latencies",
+				MetricValues: []*sc.MetricValue{{DistributionValue: builder.build()}},
+			},
+		},
+		LogEntries: []*sc.LogEntry{{Name: consumerID}},
+	}
+}
+
+func TestMergeOperation(t *testing.T) {
+	dst := getTestBatchedOperation("api_key:a", 1, 0.5, "2017-10-21T17:09:05Z", "2017-10-21T17:09:06Z")
+	src := getTestBatchedOperation("api_key:a", 2, 2.5, "2017-10-21T17:09:04.5Z", "2017-10-21T17:09:05.5Z")
+	if operationSignature(dst) != operationSignature(src) {
+		t.Fatal(`expect operations of the same consumer, operation and labels to have the same signature`)
+	}
+	if !mergeOperation(dst, src) {
+		t.Fatal(`expect operations to be merged`)
+	}
+
+	if count := *dst.MetricValueSets[0].MetricValues[0].Int64Value; count != 3 {
+		t.Errorf(`expect merged request count 3, but get %v`, count)
+	}
+	expected, _ := newDistValueBuilder(timeOption)
+	expected.addSample(0.5)
+	expected.addSample(2.5)
+	dist := dst.MetricValueSets[1].MetricValues[0].DistributionValue
+	if dist.Count != 2 || dist.Minimum != 0.5 || dist.Maximum != 2.5 || dist.Mean != expected.build().Mean ||
+		math.Abs(dist.SumOfSquaredDeviation-expected.build().SumOfSquaredDeviation) > 1e-9 {
+		t.Errorf(`expect merged distribution %v, but get %v`, *expected.build(), *dist)
+	}
+	for i, count := range expected.build().BucketCounts {
+		if dist.BucketCounts[i] != count {
+			t.Errorf(`expect merged bucket counts %v, but get %v`, expected.build().BucketCounts, dist.BucketCounts)
+			break
+		}
+	}
+	if dst.StartTime != "2017-10-21T17:09:04.5Z" || dst.EndTime != "2017-10-21T17:09:06Z" {
+		t.Errorf(`expect merged operation to span both operations, but get %s - %s`, dst.StartTime, dst.EndTime)
+	}
+	if len(dst.LogEntries) != 2 {
+		t.Errorf(`expect log entries of both operations, but get %v`, dst.LogEntries)
+	}
+
+	// Values of different types are not merged.
+	src = getTestBatchedOperation("api_key:a", 1, 0.5, "2017-10-21T17:09:05Z", "2017-10-21T17:09:06Z")
+	src.MetricValueSets[0].MetricValues[0] = &sc.MetricValue{DoubleValue: new(float64)}
+	if mergeOperation(dst, src) || *dst.MetricValueSets[0].MetricValues[0].Int64Value != 3 {
+		t.Error(`expect operations with different value types not to be merged`)
+	}
+	if operationSignature(dst) == operationSignature(
+		getTestBatchedOperation("api_key:b", 1, 0.5, "2017-10-21T17:09:05Z", "2017-10-21T17:09:06Z")) {
+		t.Error(`expect operations of different consumers to have different signatures`)
+	}
+}
+
+func TestReportBatcher(t *testing.T) {
+	sent := make(chan []*sc.Operation, 10)
+	pool := newReportFlushPool(time.Hour, 1)
+	defer pool.close()
+	go pool.work()
+	batcher := newReportBatcher(gcpServiceName, pool, 2, 0, func(operations []*sc.Operation) error {
+		sent <- operations
+		return nil
+	}, newRateLimitedLogger(at.NewEnv(t).Logger(), 0))
+
+	batcher.add([]*sc.Operation{
+		getTestBatchedOperation("api_key:a", 1, 0.5, "2017-10-21T17:09:05Z", "2017-10-21T17:09:06Z"),
+		getTestBatchedOperation("api_key:a", 1, 0.5, "2017-10-21T17:09:05Z", "2017-10-21T17:09:06Z"),
+	})
+	select {
+	case operations := <-sent:
+		t.Errorf(`expect merged operations to be buffered, but get %v sent`, operations)
+	default:
+	}
+
+	// A full batch is flushed early.
+	batcher.add([]*sc.Operation{
+		getTestBatchedOperation("api_key:b", 1, 0.5, "2017-10-21T17:09:05Z", "2017-10-21T17:09:06Z"),
+	})
+	select {
+	case operations := <-sent:
+		if len(operations) != 2 || *operations[0].MetricValueSets[0].MetricValues[0].Int64Value != 2 {
+			t.Errorf(`expect 2 merged operations, but get %v`, operations)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal(`expect full batch to be flushed`)
+	}
+
+	// Operations still buffered are flushed on close, in batches of up to the batch size.
+	batcher.add([]*sc.Operation{
+		getTestBatchedOperation("api_key:a", 1, 0.5, "2017-10-21T17:09:05Z", "2017-10-21T17:09:06Z"),
+	})
+	batcher.close()
+	if operations := <-sent; len(operations) != 1 || operations[0].ConsumerId != "api_key:a" {
+		t.Errorf(`expect buffered operation to be flushed on close, but get %v`, operations)
+	}
+}
+
+func TestReportBatcherBufferFull(t *testing.T) {
+	var sent []*sc.Operation
+	batcher := newReportBatcher(gcpServiceName, newReportFlushPool(time.Hour, 1), 1, 0, func(operations []*sc.Operation) error {
+		sent = append(sent, operations...)
+		return nil
+	}, newRateLimitedLogger(at.NewEnv(t).Logger(), 0))
+
+	// The pool isn't started, so that full batches stay buffered.
+	dropped := getCounterValue(droppedOperationCount, gcpServiceName, dropReasonBufferFull)
+	for i := 0; i < maxBufferedReportBatches+2; i++ {
+		batcher.add([]*sc.Operation{
+			getTestBatchedOperation(fmt.Sprintf("api_key:%d", i), 1, 0.5, "2017-10-21T17:09:05Z", "2017-10-21T17:09:06Z"),
+		})
+	}
+	// Operations merged into buffered ones are still accepted.
+	batcher.add([]*sc.Operation{
+		getTestBatchedOperation("api_key:0", 1, 0.5, "2017-10-21T17:09:05Z", "2017-10-21T17:09:06Z"),
+	})
+	if actual := getCounterValue(droppedOperationCount, gcpServiceName, dropReasonBufferFull); actual != dropped+2 {
+		t.Errorf(`expect %v dropped operations, but get %v`, dropped+2, actual)
+	}
+	batcher.flush()
+	if len(sent) != maxBufferedReportBatches || *sent[0].MetricValueSets[0].MetricValues[0].Int64Value != 2 {
+		t.Errorf(`expect %d buffered operations, but get %v`, maxBufferedReportBatches, sent)
+	}
+}
+
+func TestReportBatcherByteBudget(t *testing.T) {
+	var sent []*sc.Operation
+	pool := newReportFlushPool(time.Hour, 1)
+	newOperation := func(i int) *sc.Operation {
+		return getTestBatchedOperation(fmt.Sprintf("api_key:%d", i), 1, 0.5, "2017-10-21T17:09:05Z", "2017-10-21T17:09:06Z")
+	}
+	size := estimateOperationSize(newOperation(0))
+	// The pool isn't started, so that flush requests stay queued.
+	batcher := newReportBatcher(gcpServiceName, pool, 0, 2*size, func(operations []*sc.Operation) error {
+		sent = append(sent, operations...)
+		return nil
+	}, newRateLimitedLogger(at.NewEnv(t).Logger(), 0))
+	buffered := getGaugeValue(reportBufferBytes, gcpServiceName)
+
+	batcher.add([]*sc.Operation{newOperation(0)})
+	if len(pool.tasks) != 0 {
+		t.Error(`expect no flush within the byte budget`)
+	}
+	if actual := getGaugeValue(reportBufferBytes, gcpServiceName); actual != buffered+float64(size) {
+		t.Errorf(`expect %v buffered bytes, but get %v`, buffered+float64(size), actual)
+	}
+	// Merged operations only take the room of their log entries.
+	merged := newOperation(0)
+	batcher.add([]*sc.Operation{merged})
+	expected := buffered + float64(size+estimateLogEntriesSize(merged.LogEntries))
+	if actual := getGaugeValue(reportBufferBytes, gcpServiceName); actual != expected {
+		t.Errorf(`expect %v buffered bytes after merge, but get %v`, expected, actual)
+	}
+
+	// A flush is requested once the budget is exceeded, long before the batch is full.
+	batcher.add([]*sc.Operation{newOperation(1)})
+	if len(pool.tasks) != 1 {
+		t.Error(`expect flush to be requested once the byte budget is exceeded`)
+	}
+
+	// Operations beyond twice the budget are dropped.
+	dropped := getCounterValue(droppedOperationCount, gcpServiceName, dropReasonBufferFull)
+	batcher.add([]*sc.Operation{newOperation(2), newOperation(3), newOperation(4)})
+	if actual := getCounterValue(droppedOperationCount, gcpServiceName, dropReasonBufferFull); actual != dropped+1 {
+		t.Errorf(`expect %v dropped operations, but get %v`, dropped+1, actual)
+	}
+
+	(<-pool.tasks).flush()
+	if len(sent) != 4 {
+		t.Errorf(`expect 4 buffered operations to be flushed, but get %v`, sent)
+	}
+	if actual := getGaugeValue(reportBufferBytes, gcpServiceName); actual != buffered {
+		t.Errorf(`expect no buffered bytes after flush, but get %v`, actual-buffered)
+	}
+}
+
+func TestProcessReportBatched(t *testing.T) {
+	test := reportProcessorTestSetup(t)
+	test.testConfig.RuntimeConfig.ReportFlushInterval = &pbtypes.Duration{Seconds: 3600}
+	ctx, err := initializeHandlerContext(at.NewEnv(t), &test.testConfig, test.mockClient)
+	if err != nil {
+		t.Fatalf(`fail to initialize handleContext %v`, err)
+	}
+	defer ctx.reportFlushPool.close()
+	test.reportProc, err = newReportProcessor(meshServiceName, ctx, &mockConsumerProjectIDResolver{})
+	if err != nil {
+		t.Fatalf(`fail to create test reportProcessor %v`, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := test.reportProc.ProcessReport(context.Background(),
+			[]*svcctrlreport.Instance{getTestReportInstance()}); err != nil {
+			t.Fatalf(`ProcessReport() failed with %v`, err)
+		}
+	}
+	if test.mockClient.reportRequest != nil {
+		t.Fatalf(`expect operations to be buffered, but get report %v`, *test.mockClient.reportRequest)
+	}
+
+	if err := test.reportProc.Close(); err != nil {
+		t.Fatalf(`Close() failed with %v`, err)
+	}
+	request := test.mockClient.reportRequest
+	if request == nil || len(request.Operations) != 1 {
+		t.Fatalf(`expect a report of 1 merged operation on close, but get %v`, request)
+	}
+	op := request.Operations[0]
+	if len(op.LogEntries) != 2 {
+		t.Errorf(`expect log entries of both instances, but get %v`, op.LogEntries)
+	}
+	for _, set := range op.MetricValueSets {
+		if set.MetricName == "serviceruntime.googleapis.com/api/producer/request_count" &&
+			*set.MetricValues[0].Int64Value != 2 {
+			t.Errorf(`expect merged request count 2, but get %v`, *set.MetricValues[0].Int64Value)
+		}
+	}
+}
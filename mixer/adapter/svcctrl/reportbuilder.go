@@ -17,6 +17,7 @@ package svcctrl
 import (
 	"encoding/json"
 	"strconv"
+	"strings"
 	"time"
 
 	rpc "github.com/googleapis/googleapis/google/rpc"
@@ -32,6 +33,22 @@ const (
 	endPointsLogErrorCauseAuth        = "AUTH"
 	endPointsLogErrorCauseApplication = "APPLICATION"
 	endPointsMessage                  = "Method:"
+
+	// Labels attached to every operation by generateAPIResourceLabels.
+	consumerProjectLabel = "serviceruntime.googleapis.com/consumer_project"
+	apiVersionLabel      = "serviceruntime.googleapis.com/api_version"
+	apiMethodLabel       = "serviceruntime.googleapis.com/api_method"
+	locationLabel        = "cloud.googleapis.com/location"
+	requestStateLabel    = "/request_state"
+
+	// Labels of workloads of a request.
+	sourceWorkloadLabel      = "/source_workload"
+	destinationWorkloadLabel = "/destination_workload"
+
+	// End states of a request.
+	requestStateCompleted = "completed"
+	requestStateAborted   = "aborted"
+	requestStateUnknown   = "unknown"
 )
 
 type (
@@ -50,6 +67,8 @@ type (
 		name           string
 		valueGenerator generateMetricValueFunc
 		labels         []string
+		// Whether values are distributions, which can't be transformed.
+		distribution bool
 	}
 
 	// JSON payload
@@ -75,6 +94,12 @@ type (
 		supportedMetrics []metricDef
 		instance         *svcctrlreport.Instance
 		resolver         consumerProjectIDResolver
+		// Nil when API keys are reported as is.
+		anonymizer *consumerAnonymizer
+		// 0 when metric start times are reported as is.
+		timeAlignment time.Duration
+		// Transformers of metric values keyed by metric name, nil when values are reported as is.
+		metricTransformers map[string][]MetricTransformer
 	}
 )
 
@@ -89,11 +114,81 @@ var labelGeneratorMap = map[string]generateLabelFunc{
 	"/status_code":         generateStatusCode,
 }
 
+// Metrics reported for each svcctrlreport instance.
+var supportedMetrics = []metricDef{
+	{
+		name:           "serviceruntime.googleapis.com/api/consumer/request_count",
+		valueGenerator: generateRequestCount,
+		labels: []string{
+			"/credential_id",
+			"/protocol",
+			"/response_code",
+			"/response_code_class",
+			"/status_code",
+		},
+	},
+	{
+		name:           "serviceruntime.googleapis.com/api/consumer/error_count",
+		valueGenerator: generateErrorCount,
+		labels: []string{
+			"/credential_id",
+			"/error_type",
+		},
+	},
+	{
+		name:           "serviceruntime.googleapis.com/api/producer/request_count",
+		valueGenerator: generateRequestCount,
+		labels: []string{
+			"/consumer_id",
+			"/protocol",
+			"/response_code",
+			"/response_code_class",
+			"/status_code",
+		},
+	},
+	{
+		name:           "serviceruntime.googleapis.com/api/producer/error_count",
+		valueGenerator: generateErrorCount,
+		labels: []string{
+			"/consumer_id",
+			"/error_type",
+		},
+	},
+	{
+		name:           "serviceruntime.googleapis.com/api/producer/backend_latencies",
+		valueGenerator: generateBackendLatencies,
+		labels: []string{
+			"/consumer_id",
+		},
+		distribution: true,
+	},
+}
+
 // Error types based on HTTP status code
 var errorTypes = []string{
 	"0xx", "1xx", "2xx", "3xx", "4xx",
 	"5xx", "6xx", "7xx", "8xx", "9xx"}
 
+// isKnownLabel returns true if the label can be generated by reportBuilder.
+func isKnownLabel(label string) bool {
+	switch label {
+	case consumerProjectLabel, apiVersionLabel, apiMethodLabel, locationLabel, requestStateLabel,
+		sourceWorkloadLabel, destinationWorkloadLabel:
+		return true
+	}
+	_, found := labelGeneratorMap[label]
+	return found
+}
+
+// generateRequestState returns end state of a request from the attribute holding it.
+func generateRequestState(instance *svcctrlreport.Instance, attribute string) string {
+	switch state := strings.ToLower(instance.Attributes[attribute]); state {
+	case requestStateCompleted, requestStateAborted:
+		return state
+	}
+	return requestStateUnknown
+}
+
 // Well-known metric labels generator functions
 func generateConsumerID(instance *svcctrlreport.Instance) (string, bool) {
 	if instance.ApiKey == "" {
@@ -208,14 +303,19 @@ func (b *reportBuilder) addMetricValues(op *sc.Operation) {
 
 	op.Labels = b.generateAPIResourceLabels()
 	metricValueSets := make([]*sc.MetricValueSet, 0, len(b.supportedMetrics))
+	instance := b.metricInstance()
 	for _, metric := range b.supportedMetrics {
 		metricSet := new(sc.MetricValueSet)
 		metricSet.MetricName = metric.name
-		metricValue, innerErr := metric.valueGenerator(b.instance)
-		if innerErr != nil {
+		metricValue, innerErr := metric.valueGenerator(instance)
+		if innerErr != nil || metricValue == nil {
 			continue
 		}
 
+		if transformers := b.metricTransformers[metric.name]; len(transformers) > 0 {
+			transformMetricValue(metricValue, transformers)
+		}
+
 		for _, label := range metric.labels {
 			b.addMetricLabel(label, op)
 		}
@@ -238,7 +338,7 @@ func (b *reportBuilder) addMetricLabel(label string, op *sc.Operation) {
 
 	labelGenerator, found := labelGeneratorMap[label]
 	if found {
-		labelValue, ok := labelGenerator(b.instance)
+		labelValue, ok := labelGenerator(b.reportedInstance())
 		if ok {
 			op.Labels[label] = labelValue
 		}
@@ -268,7 +368,7 @@ func (b *reportBuilder) addLogEntry(op *sc.Operation) {
 
 func (b *reportBuilder) generateLogJSONPayload() ([]byte, error) {
 	payload := logPayload{}
-	payload.APIKey = b.instance.ApiKey
+	payload.APIKey = b.reportedInstance().ApiKey
 	payload.APIName = b.instance.ApiService
 	payload.APIOperation = b.instance.ApiOperation
 	payload.HTTPMethod = b.instance.RequestMethod
@@ -282,6 +382,28 @@ func (b *reportBuilder) generateLogJSONPayload() ([]byte, error) {
 	return json.Marshal(payload)
 }
 
+// reportedInstance returns the instance with API key anonymized if anonymizer is set, which
+// labels and logs are generated from. Consumer and consumer project still use the original API key.
+func (b *reportBuilder) reportedInstance() *svcctrlreport.Instance {
+	if b.anonymizer == nil || b.instance.ApiKey == "" {
+		return b.instance
+	}
+	instance := *b.instance
+	instance.ApiKey = b.anonymizer.anonymize(b.instance.ApiKey)
+	return &instance
+}
+
+// metricInstance returns the instance with request time aligned if time alignment is set, which
+// metric values are generated from.
+func (b *reportBuilder) metricInstance() *svcctrlreport.Instance {
+	if b.timeAlignment <= 0 {
+		return b.instance
+	}
+	instance := *b.instance
+	instance.RequestTime = alignTime(b.instance.RequestTime, b.timeAlignment)
+	return &instance
+}
+
 func (b *reportBuilder) generateAPIResourceLabels() map[string]string {
 	labels := make(map[string]string)
 	if b.instance.ApiKey != "" {
@@ -289,20 +411,20 @@ func (b *reportBuilder) generateAPIResourceLabels() map[string]string {
 		if b.instance.ApiOperation != "" {
 			consumerProjID, err := b.resolver.ResolveConsumerProjectID(consumerID, b.instance.ApiOperation)
 			if err == nil {
-				labels["serviceruntime.googleapis.com/consumer_project"] = consumerProjID
+				labels[consumerProjectLabel] = consumerProjID
 			}
 		}
 	}
 
 	if b.instance.ApiVersion != "" {
-		labels["serviceruntime.googleapis.com/api_version"] = b.instance.ApiVersion
+		labels[apiVersionLabel] = b.instance.ApiVersion
 	}
 
 	if b.instance.ApiOperation != "" {
-		labels["serviceruntime.googleapis.com/api_method"] = b.instance.ApiOperation
+		labels[apiMethodLabel] = b.instance.ApiOperation
 	}
 
 	// TODO(manlinl): Read location from GCE metadata server.
-	labels["cloud.googleapis.com/location"] = "global"
+	labels[locationLabel] = "global"
 	return labels
 }
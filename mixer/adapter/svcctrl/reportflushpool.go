@@ -0,0 +1,128 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"sync"
+	"time"
+
+	"istio.io/istio/mixer/pkg/adapter"
+)
+
+// Default of RuntimeConfig.ReportFlushWorkers when it's not configured.
+const defaultReportFlushWorkers = 4
+
+// reportFlushPool flushes report batchers of all services of a handler with a fixed number of
+// workers, so that concurrent flushes are bounded however many services are routed. Batchers
+// are flushed on interval, or once they request a flush because a batch is full.
+type reportFlushPool struct {
+	interval time.Duration
+	workers  int
+	// Batchers waiting for a worker.
+	tasks chan *reportBatcher
+	stop  chan struct{}
+
+	lock     sync.Mutex // guards batchers
+	batchers map[*reportBatcher]bool
+}
+
+// register flushes b on interval until it's unregistered.
+func (p *reportFlushPool) register(b *reportBatcher) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.batchers[b] = true
+}
+
+func (p *reportFlushPool) unregister(b *reportBatcher) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.batchers, b)
+}
+
+// requestFlush queues b to be flushed by a worker. The request is dropped if workers are all
+// busy and the queue is full, b is flushed on the next interval then.
+func (p *reportFlushPool) requestFlush(b *reportBatcher) {
+	select {
+	case p.tasks <- b:
+	default:
+	}
+}
+
+// start schedules the workers and the timer of the pool as daemons of env.
+func (p *reportFlushPool) start(env adapter.Env) {
+	for i := 0; i < p.workers; i++ {
+		env.ScheduleDaemon(p.work)
+	}
+	env.ScheduleDaemon(p.run)
+}
+
+// work flushes queued batchers until the pool is closed.
+func (p *reportFlushPool) work() {
+	for {
+		select {
+		case b := <-p.tasks:
+			b.flush()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// run queues all registered batchers on interval until the pool is closed.
+func (p *reportFlushPool) run() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.stop:
+			return
+		}
+		p.lock.Lock()
+		batchers := make([]*reportBatcher, 0, len(p.batchers))
+		for b := range p.batchers {
+			batchers = append(batchers, b)
+		}
+		p.lock.Unlock()
+		for _, b := range batchers {
+			select {
+			case p.tasks <- b:
+			case <-p.stop:
+				return
+			}
+		}
+	}
+}
+
+// close stops the workers and the timer. Batchers flush their remaining operations themselves
+// when they're closed.
+func (p *reportFlushPool) close() {
+	close(p.stop)
+}
+
+// newReportFlushPool creates reportFlushPool flushing on interval with workers workers, which
+// defaults to defaultReportFlushWorkers if it's not positive.
+func newReportFlushPool(interval time.Duration, workers int) *reportFlushPool {
+	if workers <= 0 {
+		workers = defaultReportFlushWorkers
+	}
+	return &reportFlushPool{
+		interval: interval,
+		workers:  workers,
+		tasks:    make(chan *reportBatcher, workers),
+		stop:     make(chan struct{}),
+		batchers: make(map[*reportBatcher]bool),
+	}
+}
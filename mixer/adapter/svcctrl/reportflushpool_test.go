@@ -0,0 +1,75 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	at "istio.io/istio/mixer/pkg/adapter/test"
+)
+
+func TestReportFlushPoolBoundsConcurrency(t *testing.T) {
+	const services = 20
+	const workers = 3
+	env := at.NewEnv(t)
+	pool := newReportFlushPool(10*time.Millisecond, workers)
+	pool.start(env)
+	defer pool.close()
+
+	var lock sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	flushed := make(chan string, services)
+	for i := 0; i < services; i++ {
+		service := fmt.Sprintf("service-%d.googleapis.com", i)
+		batcher := newReportBatcher(service, pool, 0, 0, func(operations []*sc.Operation) error {
+			lock.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			lock.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			lock.Lock()
+			inFlight--
+			lock.Unlock()
+			flushed <- service
+			return nil
+		}, newRateLimitedLogger(env.Logger(), 0))
+		defer batcher.close()
+		batcher.add([]*sc.Operation{
+			getTestBatchedOperation("api_key:a", 1, 0.5, "2017-10-21T17:09:05Z", "2017-10-21T17:09:06Z"),
+		})
+	}
+
+	seen := make(map[string]bool)
+	for len(seen) < services {
+		select {
+		case service := <-flushed:
+			seen[service] = true
+		case <-time.After(10 * time.Second):
+			t.Fatalf(`expect operations of %d services to be flushed, but get %d`, services, len(seen))
+		}
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if maxInFlight > workers {
+		t.Errorf(`expect at most %d concurrent flushes, but get %d`, workers, maxInFlight)
+	}
+}
@@ -0,0 +1,363 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pborman/uuid"
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+	"istio.io/istio/mixer/adapter/svcctrl/template/svcctrlreport"
+	"istio.io/istio/mixer/pkg/adapter"
+	"istio.io/istio/mixer/template/logentry"
+)
+
+// reportImpl implements reportProcessor interface, handles report call to Google ServiceControl backend.
+type reportImpl struct {
+	env           adapter.Env
+	serviceConfig *config.GcpServiceSetting
+	client        serviceControlClient
+	resolver      consumerProjectIDResolver
+	warningLogger *rateLimitedLogger
+	// Nil when adaptive report throttling is disabled.
+	throttle *reportThrottle
+	// Nil when consumer anonymization is disabled.
+	anonymizer *consumerAnonymizer
+	// Map from allowed header name to label name, nil when header labels are disabled.
+	headerLabels map[string]string
+	// Map from lowercase trailer name to label name.
+	trailerLabels map[string]string
+	// Alignment of reported start times, 0 when they're reported as is.
+	timeAlignment time.Duration
+	// Transformers of metric values keyed by metric name.
+	metricTransformers map[string][]MetricTransformer
+	// Nil when operations are reported synchronously.
+	batcher *reportBatcher
+}
+
+// ProcessReport converts svcctrlreport instances to operations and reports them to Google ServiceControl.
+func (r *reportImpl) ProcessReport(ctx context.Context, instances []*svcctrlreport.Instance) error {
+	operations := make([]*sc.Operation, 0, len(instances))
+	for _, instance := range instances {
+		if attribute := r.serviceConfig.SuppressReportAttribute; attribute != "" && isTruthy(instance.Attributes[attribute]) {
+			suppressedCallCount.WithLabelValues(r.serviceConfig.GoogleServiceName, methodReport).Inc()
+			continue
+		}
+		if r.isConsumerUnresolved(instance) {
+			unresolvedConsumerCount.WithLabelValues(r.serviceConfig.GoogleServiceName, methodReport).Inc()
+			if r.serviceConfig.ConsumerResolutionFailurePolicy == config.ALLOW_NO_REPORT {
+				continue
+			}
+		}
+		op := r.buildOperation(instance)
+		if missing := missingRequiredLabels(op, r.serviceConfig.RequiredLabels); len(missing) > 0 {
+			r.warningLogger.Warningf("drop operation %s: missing required labels %v", op.OperationName, missing)
+			droppedOperationCount.WithLabelValues(
+				r.serviceConfig.GoogleServiceName, dropReasonMissingLabel).Inc()
+			continue
+		}
+		operations = append(operations, op)
+	}
+	return r.report(operations)
+}
+
+// ProcessLogEntry converts logentry instances with an export to operations of log entries, and
+// reports them to Google ServiceControl.
+func (r *reportImpl) ProcessLogEntry(ctx context.Context, instances []*logentry.Instance) error {
+	operations := make([]*sc.Operation, 0, len(instances))
+	for _, instance := range instances {
+		export, found := r.serviceConfig.LogEntryExports[instance.Name]
+		if !found {
+			r.warningLogger.Warningf("drop log entry %s: instance is not exported", instance.Name)
+			droppedOperationCount.WithLabelValues(
+				r.serviceConfig.GoogleServiceName, dropReasonNotExported).Inc()
+			continue
+		}
+		builder := &logEntryBuilder{
+			export: export,
+			anonymize: func(apiKey string) string {
+				if r.anonymizer == nil {
+					return apiKey
+				}
+				return r.anonymizer.anonymize(apiKey)
+			},
+		}
+		op, err := builder.build(instance)
+		if err != nil {
+			r.warningLogger.Warningf("drop log entry %s: %v", instance.Name, err)
+			droppedOperationCount.WithLabelValues(
+				r.serviceConfig.GoogleServiceName, dropReasonInvalidLogEntry).Inc()
+			continue
+		}
+		operations = append(operations, op)
+	}
+	return r.report(operations)
+}
+
+// report reports operations, or buffers them if operations are batched.
+func (r *reportImpl) report(operations []*sc.Operation) error {
+	if len(operations) == 0 {
+		return nil
+	}
+	if r.batcher != nil {
+		r.batcher.add(operations)
+		return nil
+	}
+	return r.send(operations)
+}
+
+// send reports operations to Google ServiceControl.
+func (r *reportImpl) send(operations []*sc.Operation) error {
+	request := &sc.ReportRequest{
+		Operations: operations,
+	}
+	if r.throttle != nil && !r.throttle.allow() {
+		exempted := make([]*sc.Operation, 0, len(request.Operations))
+		var rest []*sc.Operation
+		for _, op := range request.Operations {
+			if r.throttle.isExempt(op) {
+				exempted = append(exempted, op)
+			} else {
+				rest = append(rest, op)
+			}
+		}
+		r.deferOperations(rest)
+		if len(exempted) == 0 {
+			return nil
+		}
+		request.Operations = exempted
+	} else if r.throttle != nil {
+		// Operations deferred while throttled ride along with allowed report calls.
+		request.Operations = append(r.throttle.takeDeferred(defaultMaxReportBatchSize), request.Operations...)
+	}
+
+	response, err := r.client.Report(r.serviceConfig.GoogleServiceName, request)
+	if r.throttle != nil && r.throttle.record(err) {
+		// Throttled calls are rejected as a whole, none of their operations is reported yet.
+		r.warningLogger.Warningf("report of %s is throttled, defer %d operations",
+			r.serviceConfig.GoogleServiceName, len(request.Operations))
+		r.deferOperations(request.Operations)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if r.env.Logger().VerbosityLevel(logDebug) {
+		if responseDetail, err := toFormattedJSON(response); err == nil {
+			r.env.Logger().Infof("response: %v", responseDetail)
+		}
+	}
+
+	if len(response.ReportErrors) > 0 {
+		reportError := response.ReportErrors[0]
+		return fmt.Errorf("fail to report operation %s: %v",
+			reportError.OperationId, reportError.Status.Message)
+	}
+	return nil
+}
+
+// deferOperations defers operations not sent while throttled, and counts those dropped once
+// too many operations are deferred.
+func (r *reportImpl) deferOperations(operations []*sc.Operation) {
+	if dropped := r.throttle.deferOperations(operations); dropped > 0 {
+		r.warningLogger.Warningf("drop %d operations: too many operations deferred while report is throttled", dropped)
+		droppedOperationCount.WithLabelValues(
+			r.serviceConfig.GoogleServiceName, dropReasonThrottled).Add(float64(dropped))
+	}
+}
+
+// flush sends buffered operations without closing the reportProcessor, and returns the number
+// of operations sent.
+func (r *reportImpl) flush() int {
+	if r.batcher == nil {
+		return 0
+	}
+	return r.batcher.flush()
+}
+
+// Close closes a reportProcessor, flushing buffered and deferred operations.
+func (r *reportImpl) Close() error {
+	if r.batcher != nil {
+		r.batcher.close()
+	}
+	if r.throttle == nil {
+		return nil
+	}
+	for {
+		operations := r.throttle.takeDeferred(defaultMaxReportBatchSize)
+		if len(operations) == 0 {
+			return nil
+		}
+		if _, err := r.client.Report(r.serviceConfig.GoogleServiceName,
+			&sc.ReportRequest{Operations: operations}); err != nil {
+			r.warningLogger.Warningf("fail to flush %d deferred report operations: %v", len(operations), err)
+		}
+	}
+}
+
+// peerIdentity returns the peer identity of instance, or an empty string if it's unknown.
+func (r *reportImpl) peerIdentity(instance *svcctrlreport.Instance) string {
+	if attribute := r.serviceConfig.PeerIdentityAttribute; attribute != "" {
+		return instance.Attributes[attribute]
+	}
+	return ""
+}
+
+// isConsumerUnresolved returns true if no consumer source of instance is known.
+func (r *reportImpl) isConsumerUnresolved(instance *svcctrlreport.Instance) bool {
+	return instance.ApiKey == "" && r.peerIdentity(instance) == ""
+}
+
+// buildOperation builds a ServiceControl operation from a svcctrlreport instance.
+func (r *reportImpl) buildOperation(instance *svcctrlreport.Instance) *sc.Operation {
+	op := &sc.Operation{
+		OperationId:   uuid.New(),
+		OperationName: instance.ApiOperation,
+		StartTime:     alignTime(instance.RequestTime, r.timeAlignment).UTC().Format(time.RFC3339Nano),
+		EndTime:       instance.ResponseTime.UTC().Format(time.RFC3339Nano),
+	}
+	peerIdentity := r.peerIdentity(instance)
+	if instance.ApiKey != "" || peerIdentity != "" {
+		consumerID, err := resolveConsumerID(r.serviceConfig.ReportConsumer,
+			instance.ApiKey, peerIdentity, instance.ApiOperation, r.serviceConfig.PeerIdentityProjects, r.resolver)
+		if err != nil && instance.ApiKey != "" {
+			r.warningLogger.Warningf("fail to resolve report consumer, fall back to API key: %v", err)
+			consumerID = generateConsumerIDFromAPIKey(instance.ApiKey)
+		}
+		op.ConsumerId = consumerID
+	}
+
+	builder := &reportBuilder{
+		supportedMetrics:   supportedMetrics,
+		instance:           instance,
+		resolver:           r.resolver,
+		anonymizer:         r.anonymizer,
+		timeAlignment:      r.timeAlignment,
+		metricTransformers: r.metricTransformers,
+	}
+	builder.build(op)
+
+	if attribute := r.serviceConfig.RequestStateAttribute; attribute != "" && op.Labels != nil {
+		op.Labels[requestStateLabel] = generateRequestState(instance, attribute)
+	}
+	for label, attribute := range map[string]string{
+		sourceWorkloadLabel:      r.serviceConfig.SourceWorkloadAttribute,
+		destinationWorkloadLabel: r.serviceConfig.DestinationWorkloadAttribute,
+	} {
+		if workload := instance.Attributes[attribute]; attribute != "" && workload != "" && op.Labels != nil {
+			op.Labels[label] = workload
+		}
+	}
+	for header, label := range r.headerLabels {
+		if value := instance.RequestHeaders[header]; value != "" && op.Labels != nil {
+			op.Labels[label] = value
+		}
+	}
+	for trailer, label := range r.trailerLabels {
+		if value := instance.ResponseTrailers[trailer]; value != "" && op.Labels != nil {
+			op.Labels[label] = value
+		}
+	}
+	return op
+}
+
+// missingRequiredLabels returns required labels that are absent from an operation.
+func missingRequiredLabels(op *sc.Operation, requiredLabels []string) []string {
+	var missing []string
+	for _, label := range requiredLabels {
+		if _, found := op.Labels[label]; !found {
+			missing = append(missing, label)
+		}
+	}
+	return missing
+}
+
+// newTrailerLabels returns a map from lowercase trailer name to label name.
+func newTrailerLabels(trailerLabels map[string]string) map[string]string {
+	labels := make(map[string]string, len(trailerLabels))
+	for trailer, label := range trailerLabels {
+		labels[strings.ToLower(trailer)] = label
+	}
+	return labels
+}
+
+// newHeaderLabels returns a map from lowercase header name to label name, keeping only
+// allowed headers. Returns nil if header labels aren't configured.
+func newHeaderLabels(cfg *config.HeaderLabels) map[string]string {
+	if cfg == nil {
+		return nil
+	}
+	allowed := make(map[string]bool, len(cfg.AllowedHeaders))
+	for _, header := range cfg.AllowedHeaders {
+		allowed[strings.ToLower(header)] = true
+	}
+	headerLabels := make(map[string]string, len(cfg.Labels))
+	for header, label := range cfg.Labels {
+		if header = strings.ToLower(header); allowed[header] {
+			headerLabels[header] = label
+		}
+	}
+	return headerLabels
+}
+
+func newReportProcessor(meshServiceName string, ctx *handlerContext,
+	resolver consumerProjectIDResolver) (*reportImpl, error) {
+	serviceConfig, client, err := ctx.service(meshServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	throttle, err := newReportThrottle(ctx.config.RuntimeConfig.AdaptiveReportThrottling)
+	if err != nil {
+		return nil, err
+	}
+
+	metricTransformers, err := newMetricTransformers(serviceConfig.MetricTransforms)
+	if err != nil {
+		return nil, err
+	}
+
+	var timeAlignment time.Duration
+	if ctx.config.RuntimeConfig.MetricTimeAlignment != nil {
+		timeAlignment = toDuration(ctx.config.RuntimeConfig.MetricTimeAlignment)
+	}
+
+	r := &reportImpl{
+		ctx.env,
+		serviceConfig,
+		client,
+		resolver,
+		ctx.warningLogger,
+		throttle,
+		newConsumerAnonymizer(serviceConfig.ConsumerAnonymization),
+		newHeaderLabels(serviceConfig.HeaderLabels),
+		newTrailerLabels(serviceConfig.TrailerLabels),
+		timeAlignment,
+		metricTransformers,
+		nil,
+	}
+	if ctx.reportFlushPool != nil {
+		r.batcher = newReportBatcher(serviceConfig.GoogleServiceName, ctx.reportFlushPool,
+			int(ctx.config.RuntimeConfig.MaxReportBatchSize), ctx.config.RuntimeConfig.MaxReportBufferBytes,
+			r.send, ctx.warningLogger)
+	}
+	return r, nil
+}
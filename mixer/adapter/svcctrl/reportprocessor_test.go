@@ -0,0 +1,418 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pbtypes "github.com/gogo/protobuf/types"
+	"google.golang.org/api/googleapi"
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+	"istio.io/istio/mixer/adapter/svcctrl/template/svcctrlreport"
+	at "istio.io/istio/mixer/pkg/adapter/test"
+)
+
+type reportProcessorTest struct {
+	testConfig config.Params
+	mockClient *mockSvcctrlClient
+	reportProc *reportImpl
+}
+
+func reportProcessorTestSetup(t *testing.T) *reportProcessorTest {
+	test := &reportProcessorTest{
+		testConfig: config.Params{
+			RuntimeConfig: &config.RuntimeConfig{
+				CheckCacheSize: 10,
+				CheckResultExpiration: &pbtypes.Duration{
+					Seconds: 300,
+				},
+			},
+			ServiceConfigs: []*config.GcpServiceSetting{
+				{
+					MeshServiceName:   meshServiceName,
+					GoogleServiceName: gcpServiceName,
+				},
+			},
+		},
+		mockClient: &mockSvcctrlClient{},
+	}
+
+	ctx, err := initializeHandlerContext(at.NewEnv(t), &test.testConfig, test.mockClient)
+	if err != nil {
+		t.Fatalf(`fail to initialize handleContext %v`, err)
+	}
+
+	reportProc, err := newReportProcessor(meshServiceName, ctx, &mockConsumerProjectIDResolver{
+		consumerProjectID: "test_consumer_project",
+	})
+	if err != nil {
+		t.Fatalf(`fail to create test reportProcessor %v`, err)
+	}
+
+	test.reportProc = reportProc
+	test.mockClient.setReportResponse(&sc.ReportResponse{
+		ServerResponse: googleapi.ServerResponse{
+			HTTPStatusCode: 200,
+		},
+	})
+	return test
+}
+
+func getTestReportInstance() *svcctrlreport.Instance {
+	requestTime, _ := time.Parse(time.RFC3339Nano, "2017-10-21T17:09:05.000Z")
+	responseTime, _ := time.Parse(time.RFC3339Nano, "2017-10-21T17:09:05.100Z")
+	return &svcctrlreport.Instance{
+		ApiVersion:      "v1.0",
+		ApiOperation:    "echo",
+		ApiProtocol:     "REST",
+		ApiService:      "echo.test.com",
+		ApiKey:          "test_key",
+		RequestTime:     requestTime,
+		RequestMethod:   "POST",
+		RequestPath:     "echo.test.com/echo",
+		RequestBytes:    10,
+		ResponseTime:    responseTime,
+		ResponseCode:    200,
+		ResponseBytes:   1024,
+		ResponseLatency: 1 * time.Microsecond,
+	}
+}
+
+func TestProcessReport(t *testing.T) {
+	test := reportProcessorTestSetup(t)
+	instance := getTestReportInstance()
+	if err := test.reportProc.ProcessReport(context.Background(),
+		[]*svcctrlreport.Instance{instance}); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+
+	if test.mockClient.serviceName != gcpServiceName {
+		t.Errorf(`expect service name %v, but get %v`, gcpServiceName, test.mockClient.serviceName)
+	}
+	request := test.mockClient.reportRequest
+	if request == nil || len(request.Operations) != 1 {
+		t.Fatalf(`expect a report request with 1 operation, but get %v`, request)
+	}
+	op := request.Operations[0]
+	if op.OperationName != "echo" || op.ConsumerId != "api_key:test_key" {
+		t.Errorf(`unexpected operation %v`, *op)
+	}
+	if len(op.MetricValueSets) == 0 || len(op.LogEntries) != 1 {
+		t.Errorf(`expect metrics and log entry in operation, but get %v`, *op)
+	}
+}
+
+func TestProcessReportWithError(t *testing.T) {
+	test := reportProcessorTestSetup(t)
+	test.mockClient.setReportResponse(nil)
+	if err := test.reportProc.ProcessReport(context.Background(),
+		[]*svcctrlreport.Instance{getTestReportInstance()}); err == nil {
+		t.Error(`expect error from ProcessReport(), but get nil`)
+	}
+}
+
+func TestProcessReportRequiredLabels(t *testing.T) {
+	test := reportProcessorTestSetup(t)
+	test.reportProc.serviceConfig.RequiredLabels = []string{"/consumer_id", "/protocol"}
+
+	// All required labels are present.
+	err := test.reportProc.ProcessReport(context.Background(),
+		[]*svcctrlreport.Instance{getTestReportInstance()})
+	if err != nil || test.mockClient.reportRequest == nil ||
+		len(test.mockClient.reportRequest.Operations) != 1 {
+		t.Fatalf(`expect operation to be reported, but get request %v, error %v`,
+			test.mockClient.reportRequest, err)
+	}
+
+	// Operation without a protocol label is dropped.
+	test.mockClient.reportRequest = nil
+	dropped := getCounterValue(droppedOperationCount, gcpServiceName, dropReasonMissingLabel)
+	incomplete := getTestReportInstance()
+	incomplete.ApiProtocol = ""
+	err = test.reportProc.ProcessReport(context.Background(),
+		[]*svcctrlreport.Instance{getTestReportInstance(), incomplete})
+	if err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+	if len(test.mockClient.reportRequest.Operations) != 1 {
+		t.Errorf(`expect 1 operation to be reported, but get %d`,
+			len(test.mockClient.reportRequest.Operations))
+	}
+	if actual := getCounterValue(droppedOperationCount, gcpServiceName, dropReasonMissingLabel); actual != dropped+1 {
+		t.Errorf(`expect dropped operation count %v, but get %v`, dropped+1, actual)
+	}
+
+	// Nothing is sent if every operation is dropped.
+	test.mockClient.reportRequest = nil
+	err = test.reportProc.ProcessReport(context.Background(), []*svcctrlreport.Instance{incomplete})
+	if err != nil || test.mockClient.reportRequest != nil {
+		t.Errorf(`expect no report request, but get request %v, error %v`,
+			test.mockClient.reportRequest, err)
+	}
+}
+
+func TestProcessReportConsumerAnonymization(t *testing.T) {
+	test := reportProcessorTestSetup(t)
+	test.reportProc.anonymizer = newConsumerAnonymizer(&config.ConsumerAnonymization{Salt: "test_salt"})
+	instances := []*svcctrlreport.Instance{getTestReportInstance(), getTestReportInstance()}
+	if err := test.reportProc.ProcessReport(context.Background(), instances); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+
+	ops := test.mockClient.reportRequest.Operations
+	hashedKey := test.reportProc.anonymizer.anonymize("test_key")
+	if hashedKey == "test_key" || ops[0].ConsumerId != "api_key:test_key" || ops[1].ConsumerId != ops[0].ConsumerId {
+		t.Errorf(`expect consumer to keep API key, but get %v and %v`, ops[0].ConsumerId, ops[1].ConsumerId)
+	}
+	consumerID := ops[0].ConsumerId
+	ops[0].ConsumerId = ""
+	opJSON, err := toFormattedJSON(ops[0])
+	ops[0].ConsumerId = consumerID
+	if err != nil {
+		t.Fatalf(`fail to marshal operation: %v`, err)
+	}
+	if strings.Contains(opJSON, "test_key") {
+		t.Errorf(`expect API key to be anonymized outside consumer, but get operation %s`, opJSON)
+	}
+	if !strings.Contains(opJSON, hashedKey) {
+		t.Errorf(`expect hashed API key in labels and log, but get operation %s`, opJSON)
+	}
+
+	// A different salt produces different hash.
+	otherAnonymizer := newConsumerAnonymizer(&config.ConsumerAnonymization{Salt: "other_salt"})
+	if otherAnonymizer.anonymize("test_key") == hashedKey {
+		t.Error(`expect different hash with different salt`)
+	}
+
+	// Consumer project is reported as is.
+	test.reportProc.serviceConfig.ReportConsumer = config.CONSUMER_PROJECT
+	if err := test.reportProc.ProcessReport(context.Background(), instances[:1]); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+	if actual := test.mockClient.reportRequest.Operations[0].ConsumerId; actual != "test_consumer_project" {
+		t.Errorf(`expect consumer project, but get %v`, actual)
+	}
+}
+
+func TestProcessReportRequestState(t *testing.T) {
+	testCases := []struct {
+		attributes    map[string]string
+		expectedState string
+	}{
+		{map[string]string{"request.state": "completed"}, requestStateCompleted},
+		{map[string]string{"request.state": "ABORTED"}, requestStateAborted},
+		{map[string]string{"request.state": "cancelled"}, requestStateUnknown},
+		{nil, requestStateUnknown},
+	}
+
+	test := reportProcessorTestSetup(t)
+	test.reportProc.serviceConfig.RequestStateAttribute = "request.state"
+	for _, tc := range testCases {
+		instance := getTestReportInstance()
+		instance.Attributes = tc.attributes
+		if err := test.reportProc.ProcessReport(context.Background(),
+			[]*svcctrlreport.Instance{instance}); err != nil {
+			t.Fatalf(`ProcessReport() failed with %v`, err)
+		}
+		op := test.mockClient.reportRequest.Operations[0]
+		if actual := op.Labels[requestStateLabel]; actual != tc.expectedState {
+			t.Errorf(`expect request state %v for attributes %v, but get %v`, tc.expectedState, tc.attributes, actual)
+		}
+	}
+
+	// No request state label when attribute isn't configured.
+	test.reportProc.serviceConfig.RequestStateAttribute = ""
+	if err := test.reportProc.ProcessReport(context.Background(),
+		[]*svcctrlreport.Instance{getTestReportInstance()}); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+	if _, found := test.mockClient.reportRequest.Operations[0].Labels[requestStateLabel]; found {
+		t.Error(`expect no request state label`)
+	}
+}
+
+func TestProcessReportHeaderLabels(t *testing.T) {
+	test := reportProcessorTestSetup(t)
+	test.reportProc.headerLabels = newHeaderLabels(&config.HeaderLabels{
+		AllowedHeaders: []string{"User-Agent", "x-client-version"},
+		Labels: map[string]string{
+			"user-agent":       "/user_agent",
+			"X-Client-Version": "/client_version",
+			// Not in the allowlist, never reported.
+			"authorization": "/authorization",
+		},
+	})
+
+	instance := getTestReportInstance()
+	instance.RequestHeaders = map[string]string{
+		"user-agent":       "test-agent",
+		"x-client-version": "1.0",
+		"authorization":    "Bearer secret",
+	}
+	if err := test.reportProc.ProcessReport(context.Background(),
+		[]*svcctrlreport.Instance{instance}); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+
+	labels := test.mockClient.reportRequest.Operations[0].Labels
+	if labels["/user_agent"] != "test-agent" || labels["/client_version"] != "1.0" {
+		t.Errorf(`expect header labels, but get %v`, labels)
+	}
+	if _, found := labels["/authorization"]; found {
+		t.Errorf(`expect header not in allowlist to be dropped, but get %v`, labels)
+	}
+
+	// Missing headers, and empty ones defaulted by `| ""`, are not reported.
+	instance = getTestReportInstance()
+	instance.RequestHeaders = map[string]string{"x-client-version": ""}
+	if err := test.reportProc.ProcessReport(context.Background(),
+		[]*svcctrlreport.Instance{instance}); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+	labels = test.mockClient.reportRequest.Operations[0].Labels
+	if _, found := labels["/user_agent"]; found {
+		t.Error(`expect no label of missing header`)
+	}
+	if _, found := labels["/client_version"]; found {
+		t.Error(`expect no label of empty header`)
+	}
+}
+
+func TestProcessReportSuppressed(t *testing.T) {
+	test := reportProcessorTestSetup(t)
+	test.reportProc.serviceConfig.SuppressReportAttribute = "request.synthetic"
+	suppressed := getTestReportInstance()
+	suppressed.ApiOperation = "probe"
+	suppressed.Attributes = map[string]string{"request.synthetic": "true"}
+	notSuppressed := getTestReportInstance()
+	notSuppressed.Attributes = map[string]string{"request.synthetic": "false"}
+
+	count := getCounterValue(suppressedCallCount, gcpServiceName, methodReport)
+	if err := test.reportProc.ProcessReport(context.Background(),
+		[]*svcctrlreport.Instance{suppressed, notSuppressed, getTestReportInstance()}); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+	ops := test.mockClient.reportRequest.Operations
+	if len(ops) != 2 || ops[0].OperationName != "echo" || ops[1].OperationName != "echo" {
+		t.Errorf(`expect suppressed operation to be skipped, but get %v`, ops)
+	}
+	if actual := getCounterValue(suppressedCallCount, gcpServiceName, methodReport); actual != count+1 {
+		t.Errorf(`expect %v suppressed operations, but get %v`, count+1, actual)
+	}
+
+	// Nothing is reported when all operations are suppressed.
+	test.mockClient.reset()
+	if err := test.reportProc.ProcessReport(context.Background(),
+		[]*svcctrlreport.Instance{suppressed}); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+	if test.mockClient.reportRequest != nil {
+		t.Errorf(`expect no report request, but get %v`, *test.mockClient.reportRequest)
+	}
+}
+
+func TestProcessReportTimeAlignment(t *testing.T) {
+	test := reportProcessorTestSetup(t)
+	test.reportProc.timeAlignment = time.Minute
+	if err := test.reportProc.ProcessReport(context.Background(),
+		[]*svcctrlreport.Instance{getTestReportInstance()}); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+
+	alignedTime := "2017-10-21T17:09:00Z"
+	op := test.mockClient.reportRequest.Operations[0]
+	if op.StartTime != alignedTime {
+		t.Errorf(`expect operation start time %v, but get %v`, alignedTime, op.StartTime)
+	}
+	if op.EndTime != "2017-10-21T17:09:05.1Z" {
+		t.Errorf(`expect operation end time as is, but get %v`, op.EndTime)
+	}
+	for _, metricSet := range op.MetricValueSets {
+		for _, value := range metricSet.MetricValues {
+			if value.StartTime != alignedTime {
+				t.Errorf(`expect %v start time %v, but get %v`, metricSet.MetricName, alignedTime, value.StartTime)
+			}
+		}
+	}
+	if len(op.LogEntries) > 0 && op.LogEntries[0].Timestamp != "2017-10-21T17:09:05Z" {
+		t.Errorf(`expect log entry timestamp as is, but get %v`, op.LogEntries[0].Timestamp)
+	}
+}
+
+func TestProcessReportTrailerLabels(t *testing.T) {
+	test := reportProcessorTestSetup(t)
+	test.reportProc.trailerLabels = newTrailerLabels(map[string]string{
+		"Grpc-Status":  "/grpc_status",
+		"grpc-message": "/grpc_message",
+	})
+
+	instance := getTestReportInstance()
+	instance.ResponseTrailers = map[string]string{
+		"grpc-status": "14",
+		// Defaulted by `| ""` when the trailer is missing.
+		"grpc-message": "",
+	}
+	if err := test.reportProc.ProcessReport(context.Background(),
+		[]*svcctrlreport.Instance{instance}); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+
+	labels := test.mockClient.reportRequest.Operations[0].Labels
+	if labels["/grpc_status"] != "14" {
+		t.Errorf(`expect trailer label, but get %v`, labels)
+	}
+	if _, found := labels["/grpc_message"]; found {
+		t.Errorf(`expect no label of missing trailer, but get %v`, labels)
+	}
+}
+
+func TestProcessReportWorkloadLabels(t *testing.T) {
+	test := reportProcessorTestSetup(t)
+	test.reportProc.serviceConfig.SourceWorkloadAttribute = "source.name"
+	test.reportProc.serviceConfig.DestinationWorkloadAttribute = "destination.name"
+
+	instance := getTestReportInstance()
+	instance.Attributes = map[string]string{
+		"source.name":      "client-v1",
+		"destination.name": "echo-v2",
+	}
+	if err := test.reportProc.ProcessReport(context.Background(),
+		[]*svcctrlreport.Instance{instance}); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+	labels := test.mockClient.reportRequest.Operations[0].Labels
+	if labels[sourceWorkloadLabel] != "client-v1" || labels[destinationWorkloadLabel] != "echo-v2" {
+		t.Errorf(`expect workload labels, but get %v`, labels)
+	}
+
+	// Missing or empty workloads are not reported.
+	instance.Attributes = map[string]string{"source.name": ""}
+	if err := test.reportProc.ProcessReport(context.Background(),
+		[]*svcctrlreport.Instance{instance}); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+	labels = test.mockClient.reportRequest.Operations[0].Labels
+	if _, found := labels[sourceWorkloadLabel]; found {
+		t.Errorf(`expect no source workload label, but get %v`, labels)
+	}
+	if _, found := labels[destinationWorkloadLabel]; found {
+		t.Errorf(`expect no destination workload label, but get %v`, labels)
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+)
+
+const (
+	// Limit of RetryPolicy.MaxAttempts.
+	maxRetryAttempts = 5
+
+	// Defaults of retry policy settings when they're not configured.
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = time.Second
+	defaultBudgetRatio    = 0.1
+
+	// Retries allowed beyond the budget ratio, so that a burst of failures right after
+	// start can be retried.
+	retryBudgetBurst = 10
+
+	// Operation.Importance of checks escalated on their last attempt.
+	importanceLow  = "LOW"
+	importanceHigh = "HIGH"
+)
+
+// errCircuitOpen is returned by calls rejected by an open circuit breaker.
+var errCircuitOpen = errors.New("circuit breaker is open, Google ServiceControl is unreachable")
+
+type (
+	// retryBudget allows retries while they stay within a ratio of calls.
+	retryBudget struct {
+		ratio float64
+
+		lock   sync.Mutex // guards tokens
+		tokens float64
+	}
+
+	// circuitBreaker opens after consecutive failed calls, and lets a single probe through
+	// once it's been open for openDuration.
+	circuitBreaker struct {
+		failureThreshold int
+		openDuration     time.Duration
+		now              func() time.Time
+
+		lock     sync.Mutex // guards fields below
+		failures int
+		openedAt time.Time
+		probing  bool
+	}
+
+	// resilientClient retries calls of client failing because Google ServiceControl is
+	// unreachable, and rejects calls of a method while the circuit breaker of the method is
+	// open. Methods have breakers of their own, since they may be served by different endpoints.
+	resilientClient struct {
+		client         serviceControlClient
+		maxAttempts    int
+		initialBackoff time.Duration
+		maxBackoff     time.Duration
+		rand           func() float64
+		// Sleeps for a backoff, returns the error of ctx if it's done first.
+		sleep func(ctx context.Context, backoff time.Duration) error
+		// Nil when calls are not retried.
+		budget *retryBudget
+		// Circuit breakers keyed by method, nil when circuit breaker is disabled.
+		breakers map[string]*circuitBreaker
+	}
+)
+
+// deposit earns a fraction of a retry for a call.
+func (b *retryBudget) deposit() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.tokens += b.ratio
+	if b.tokens > retryBudgetBurst {
+		b.tokens = retryBudgetBurst
+	}
+}
+
+// available returns true if the budget allows a retry, without spending it.
+func (b *retryBudget) available() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.tokens >= 1
+}
+
+// withdraw returns true and spends a retry if the budget allows one.
+func (b *retryBudget) withdraw() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// allow returns true if a call should be made.
+func (b *circuitBreaker) allow() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.failures < b.failureThreshold {
+		return true
+	}
+	if b.probing || b.now().Sub(b.openedAt) < b.openDuration {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// opensOnFailure returns true if the breaker opens once the next call fails.
+func (b *circuitBreaker) opensOnFailure() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.failures+1 >= b.failureThreshold
+}
+
+// record records outcome of a call, the breaker opens again if a probe fails.
+func (b *circuitBreaker) record(failed bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.probing = false
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.failureThreshold {
+		b.openedAt = b.now()
+	}
+}
+
+// Check escalates LOW importance checks to HIGH on the last attempt.
+func (c *resilientClient) Check(serviceName string, request *sc.CheckRequest) (*sc.CheckResponse, error) {
+	var response *sc.CheckResponse
+	escalate := request.Operation != nil && request.Operation.Importance == importanceLow
+	err := c.call(context.Background(), serviceName, methodCheck, func(last bool) (err error) {
+		if escalate && last {
+			request.Operation.Importance = importanceHigh
+		}
+		response, err = c.client.Check(serviceName, request)
+		return err
+	})
+	return response, err
+}
+
+func (c *resilientClient) Report(serviceName string, request *sc.ReportRequest) (*sc.ReportResponse, error) {
+	var response *sc.ReportResponse
+	err := c.call(context.Background(), serviceName, methodReport, func(bool) (err error) {
+		response, err = c.client.Report(serviceName, request)
+		return err
+	})
+	return response, err
+}
+
+func (c *resilientClient) AllocateQuota(ctx context.Context, serviceName string,
+	request *sc.AllocateQuotaRequest) (*sc.AllocateQuotaResponse, error) {
+	var response *sc.AllocateQuotaResponse
+	err := c.call(ctx, serviceName, methodQuota, func(bool) (err error) {
+		response, err = c.client.AllocateQuota(ctx, serviceName, request)
+		return err
+	})
+	return response, err
+}
+
+// call makes a call with fn, retrying it while Google ServiceControl is unreachable and the
+// attempts and the retry budget allow. Retried calls reuse the request, so that Google
+// ServiceControl deduplicates them by operation id. Retries stop once ctx is done. fn is told
+// whether its attempt is known to be the last, see lastAttempt.
+func (c *resilientClient) call(ctx context.Context, serviceName, method string, fn func(last bool) error) error {
+	if c.budget != nil {
+		c.budget.deposit()
+	}
+	breaker := c.breakers[method]
+	for attempt := 1; ; attempt++ {
+		if breaker != nil && !breaker.allow() {
+			circuitBreakerRejectedCount.WithLabelValues(serviceName, method).Inc()
+			return errCircuitOpen
+		}
+		err := fn(c.lastAttempt(attempt, breaker))
+		unreachable := isUnreachable(err)
+		if breaker != nil {
+			breaker.record(unreachable)
+		}
+		if !unreachable || attempt >= c.maxAttempts || c.budget == nil || !c.budget.withdraw() {
+			return err
+		}
+		retryCount.WithLabelValues(serviceName, method).Inc()
+		if err := c.sleep(ctx, c.backoff(attempt)); err != nil {
+			return err
+		}
+	}
+}
+
+// lastAttempt returns true if attempt won't be retried once it fails: attempts run out, the
+// retry budget can't fund a retry, or the failure opens the circuit breaker. The budget and the
+// breaker are shared by concurrent calls, which may fund or take a retry meanwhile.
+func (c *resilientClient) lastAttempt(attempt int, breaker *circuitBreaker) bool {
+	return attempt >= c.maxAttempts || c.budget == nil || !c.budget.available() ||
+		(breaker != nil && breaker.opensOnFailure())
+}
+
+// sleepContext sleeps for backoff, returns the error of ctx if it's done first.
+func sleepContext(ctx context.Context, backoff time.Duration) error {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// backoff returns a random backoff up to initialBackoff doubled for every retry before attempt.
+func (c *resilientClient) backoff(attempt int) time.Duration {
+	backoff := c.maxBackoff
+	if shift := uint(attempt - 1); shift < 32 && c.initialBackoff<<shift < c.maxBackoff {
+		backoff = c.initialBackoff << shift
+	}
+	return time.Duration(c.rand() * float64(backoff))
+}
+
+// isUnreachable returns true if a call failed because Google ServiceControl is unavailable,
+// rather than rejecting the call.
+func isUnreachable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if err == errCircuitOpen {
+		return true
+	}
+	if apiErr, ok := err.(*googleapi.Error); ok {
+		return apiErr.Code >= http.StatusInternalServerError
+	}
+	_, ok := err.(net.Error)
+	return ok
+}
+
+// newResilientClient wraps client with the retry policy and circuit breaker of cfg, returns
+// client as is if neither is configured.
+func newResilientClient(client serviceControlClient, cfg *config.RuntimeConfig) serviceControlClient {
+	if cfg.RetryPolicy == nil && cfg.CircuitBreaker == nil {
+		return client
+	}
+	c := &resilientClient{
+		client:         client,
+		maxAttempts:    1,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+		rand:           rand.Float64,
+		sleep:          sleepContext,
+	}
+	if retry := cfg.RetryPolicy; retry != nil {
+		c.maxAttempts = int(retry.MaxAttempts)
+		if retry.InitialBackoff != nil {
+			c.initialBackoff = toDuration(retry.InitialBackoff)
+		}
+		if retry.MaxBackoff != nil {
+			c.maxBackoff = toDuration(retry.MaxBackoff)
+		}
+		ratio := retry.BudgetRatio
+		if ratio == 0 {
+			ratio = defaultBudgetRatio
+		}
+		c.budget = &retryBudget{ratio: ratio, tokens: retryBudgetBurst}
+	}
+	if breaker := cfg.CircuitBreaker; breaker != nil {
+		c.breakers = make(map[string]*circuitBreaker)
+		for _, method := range []string{methodCheck, methodReport, methodQuota} {
+			c.breakers[method] = &circuitBreaker{
+				failureThreshold: int(breaker.FailureThreshold),
+				openDuration:     toDuration(breaker.OpenDuration),
+				now:              time.Now,
+			}
+		}
+	}
+	return c
+}
@@ -0,0 +1,315 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	pbtypes "github.com/gogo/protobuf/types"
+	"google.golang.org/api/googleapi"
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+)
+
+// flakyClient fails calls with errs in order, and succeeds once they run out.
+type flakyClient struct {
+	errs  []error
+	calls int
+	// Operation.Importance of checks in order.
+	importances []string
+}
+
+func (c *flakyClient) result() error {
+	c.calls++
+	if len(c.errs) == 0 {
+		return nil
+	}
+	err := c.errs[0]
+	c.errs = c.errs[1:]
+	return err
+}
+
+func (c *flakyClient) Check(serviceName string, request *sc.CheckRequest) (*sc.CheckResponse, error) {
+	if request.Operation != nil {
+		c.importances = append(c.importances, request.Operation.Importance)
+	}
+	if err := c.result(); err != nil {
+		return nil, err
+	}
+	return &sc.CheckResponse{}, nil
+}
+
+func (c *flakyClient) Report(serviceName string, request *sc.ReportRequest) (*sc.ReportResponse, error) {
+	if err := c.result(); err != nil {
+		return nil, err
+	}
+	return &sc.ReportResponse{}, nil
+}
+
+func (c *flakyClient) AllocateQuota(ctx context.Context, serviceName string,
+	request *sc.AllocateQuotaRequest) (*sc.AllocateQuotaResponse, error) {
+	if err := c.result(); err != nil {
+		return nil, err
+	}
+	return &sc.AllocateQuotaResponse{}, nil
+}
+
+func newTestResilientClient(client serviceControlClient, cfg *config.RuntimeConfig,
+	backoffs *[]time.Duration) *resilientClient {
+	c := newResilientClient(client, cfg).(*resilientClient)
+	c.rand = func() float64 { return 0.5 }
+	c.sleep = func(ctx context.Context, backoff time.Duration) error {
+		*backoffs = append(*backoffs, backoff)
+		return ctx.Err()
+	}
+	return c
+}
+
+func TestResilientClientRetry(t *testing.T) {
+	unavailable := &googleapi.Error{Code: 503}
+	cfg := &config.RuntimeConfig{
+		RetryPolicy: &config.RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: &pbtypes.Duration{Nanos: int32(100 * time.Millisecond)},
+			MaxBackoff:     &pbtypes.Duration{Nanos: int32(150 * time.Millisecond)},
+		},
+	}
+	testCases := []struct {
+		name     string
+		errs     []error
+		calls    int
+		backoffs []time.Duration
+		err      error
+	}{
+		{"success", nil, 1, nil, nil},
+		{"recovered", []error{unavailable, unavailable}, 3,
+			[]time.Duration{50 * time.Millisecond, 75 * time.Millisecond}, nil},
+		{"attempts exceeded", []error{unavailable, unavailable, unavailable}, 3,
+			[]time.Duration{50 * time.Millisecond, 75 * time.Millisecond}, unavailable},
+		{"rejected", []error{&googleapi.Error{Code: 403}}, 1, nil, &googleapi.Error{Code: 403}},
+	}
+	for _, tc := range testCases {
+		flaky := &flakyClient{errs: tc.errs}
+		var backoffs []time.Duration
+		c := newTestResilientClient(flaky, cfg, &backoffs)
+		_, err := c.Check(gcpServiceName, &sc.CheckRequest{})
+		if !reflect.DeepEqual(err, tc.err) || flaky.calls != tc.calls || !reflect.DeepEqual(backoffs, tc.backoffs) {
+			t.Errorf(`%s: expect %v after %d calls and backoffs %v, but get %v after %d calls and backoffs %v`,
+				tc.name, tc.err, tc.calls, tc.backoffs, err, flaky.calls, backoffs)
+		}
+	}
+
+	before := getCounterValue(retryCount, gcpServiceName, methodQuota)
+	var backoffs []time.Duration
+	c := newTestResilientClient(&flakyClient{errs: []error{&net.OpError{Op: "dial", Err: errors.New("refused")}}},
+		cfg, &backoffs)
+	if _, err := c.AllocateQuota(context.Background(), gcpServiceName, &sc.AllocateQuotaRequest{}); err != nil {
+		t.Errorf(`expect network error to be retried, but get %v`, err)
+	}
+	if actual := getCounterValue(retryCount, gcpServiceName, methodQuota); actual != before+1 {
+		t.Errorf(`expect %v retries, but get %v`, before+1, actual)
+	}
+
+	// Retries stop once the context of the call is done.
+	flaky := &flakyClient{errs: []error{unavailable, unavailable}}
+	c = newTestResilientClient(flaky, cfg, &backoffs)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.AllocateQuota(ctx, gcpServiceName, &sc.AllocateQuotaRequest{}); err != context.Canceled ||
+		flaky.calls != 1 {
+		t.Errorf(`expect cancelled call not to be retried, but get %v after %d calls`, err, flaky.calls)
+	}
+}
+
+func TestResilientClientEscalateImportance(t *testing.T) {
+	unavailable := &googleapi.Error{Code: 503}
+	cfg := &config.RuntimeConfig{
+		RetryPolicy: &config.RetryPolicy{MaxAttempts: 3},
+	}
+	testCases := []struct {
+		name        string
+		importance  string
+		errs        []error
+		importances []string
+	}{
+		{"success", importanceLow, nil, []string{importanceLow}},
+		{"retried", importanceLow, []error{unavailable}, []string{importanceLow, importanceLow}},
+		{"last attempt", importanceLow, []error{unavailable, unavailable},
+			[]string{importanceLow, importanceLow, importanceHigh}},
+		{"not escalated", "", []error{unavailable, unavailable}, []string{"", "", ""}},
+	}
+	for _, tc := range testCases {
+		flaky := &flakyClient{errs: tc.errs}
+		var backoffs []time.Duration
+		c := newTestResilientClient(flaky, cfg, &backoffs)
+		if _, err := c.Check(gcpServiceName, &sc.CheckRequest{
+			Operation: &sc.Operation{OperationName: "echo", Importance: tc.importance},
+		}); err != nil {
+			t.Errorf(`%s: Check() failed with %v`, tc.name, err)
+		}
+		if !reflect.DeepEqual(flaky.importances, tc.importances) {
+			t.Errorf(`%s: expect importances %v, but get %v`, tc.name, tc.importances, flaky.importances)
+		}
+	}
+
+	// Attempts are the last once the retry budget or the circuit breaker stops retries early.
+	cfg.CircuitBreaker = &config.CircuitBreaker{FailureThreshold: 3, OpenDuration: &pbtypes.Duration{Seconds: 10}}
+	for _, tc := range []struct {
+		name        string
+		setup       func(c *resilientClient)
+		err         error
+		importances []string
+	}{
+		{"budget exhausted", func(c *resilientClient) { c.budget.tokens = 0 },
+			unavailable, []string{importanceHigh}},
+		{"budget for one retry", func(c *resilientClient) { c.budget.tokens = 1 },
+			unavailable, []string{importanceLow, importanceHigh}},
+		{"breaker opens", func(c *resilientClient) { c.breakers[methodCheck].failures = 1 },
+			errCircuitOpen, []string{importanceLow, importanceHigh}},
+	} {
+		flaky := &flakyClient{errs: []error{unavailable, unavailable, unavailable}}
+		var backoffs []time.Duration
+		c := newTestResilientClient(flaky, cfg, &backoffs)
+		tc.setup(c)
+		if _, err := c.Check(gcpServiceName, &sc.CheckRequest{
+			Operation: &sc.Operation{OperationName: "echo", Importance: importanceLow},
+		}); err != tc.err {
+			t.Errorf(`%s: expect %v, but get %v`, tc.name, tc.err, err)
+		}
+		if !reflect.DeepEqual(flaky.importances, tc.importances) {
+			t.Errorf(`%s: expect importances %v, but get %v`, tc.name, tc.importances, flaky.importances)
+		}
+	}
+}
+
+func TestResilientClientRetryBudget(t *testing.T) {
+	unavailable := &googleapi.Error{Code: 503}
+	var backoffs []time.Duration
+	flaky := &flakyClient{}
+	c := newTestResilientClient(flaky, &config.RuntimeConfig{
+		RetryPolicy: &config.RetryPolicy{
+			MaxAttempts: 2,
+			BudgetRatio: 0.5,
+		},
+	}, &backoffs)
+	c.budget.tokens = 0
+
+	flaky.errs = []error{unavailable, unavailable}
+	if _, err := c.Report(gcpServiceName, &sc.ReportRequest{}); err != unavailable || flaky.calls != 1 {
+		t.Errorf(`expect no retry beyond the budget, but get %v after %d calls`, err, flaky.calls)
+	}
+	// Every call earns half a retry.
+	flaky.calls = 0
+	if _, err := c.Report(gcpServiceName, &sc.ReportRequest{}); err != nil || flaky.calls != 2 {
+		t.Errorf(`expect retry within the budget, but get %v after %d calls`, err, flaky.calls)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	var backoffs []time.Duration
+	flaky := &flakyClient{}
+	c := newTestResilientClient(flaky, &config.RuntimeConfig{
+		CircuitBreaker: &config.CircuitBreaker{
+			FailureThreshold: 2,
+			OpenDuration:     &pbtypes.Duration{Seconds: 10},
+		},
+	}, &backoffs)
+	breaker := c.breakers[methodCheck]
+	breaker.now = func() time.Time { return now }
+
+	unavailable := &googleapi.Error{Code: 500}
+	flaky.errs = []error{unavailable, &googleapi.Error{Code: 403}, unavailable, unavailable}
+	for i := 0; i < 4; i++ {
+		if _, err := c.Check(gcpServiceName, &sc.CheckRequest{}); err == nil {
+			t.Fatalf(`expect call %d to fail`, i)
+		}
+	}
+	if flaky.calls != 4 {
+		t.Errorf(`expect rejected calls not to count as failures, but get %d calls`, flaky.calls)
+	}
+
+	// Other methods keep their own breakers closed.
+	if _, err := c.Report(gcpServiceName, &sc.ReportRequest{}); err != nil || flaky.calls != 5 {
+		t.Errorf(`expect report not to be rejected by check breaker, but get %v after %d calls`, err, flaky.calls)
+	}
+
+	rejected := getCounterValue(circuitBreakerRejectedCount, gcpServiceName, methodCheck)
+	if _, err := c.Check(gcpServiceName, &sc.CheckRequest{}); err != errCircuitOpen || flaky.calls != 5 {
+		t.Errorf(`expect open breaker to reject calls, but get %v after %d calls`, err, flaky.calls)
+	}
+	if actual := getCounterValue(circuitBreakerRejectedCount, gcpServiceName, methodCheck); actual != rejected+1 {
+		t.Errorf(`expect %v rejected calls, but get %v`, rejected+1, actual)
+	}
+
+	// A failed probe opens the breaker again.
+	now = now.Add(10 * time.Second)
+	flaky.errs = []error{unavailable}
+	if _, err := c.Check(gcpServiceName, &sc.CheckRequest{}); err != unavailable || flaky.calls != 6 {
+		t.Errorf(`expect probe call, but get %v after %d calls`, err, flaky.calls)
+	}
+	if _, err := c.Check(gcpServiceName, &sc.CheckRequest{}); err != errCircuitOpen {
+		t.Errorf(`expect breaker to open again after failed probe, but get %v`, err)
+	}
+
+	// A successful probe closes the breaker.
+	now = now.Add(10 * time.Second)
+	if !breaker.allow() || breaker.allow() {
+		t.Error(`expect a single probe once open duration passes`)
+	}
+	breaker.record(false)
+	if _, err := c.Check(gcpServiceName, &sc.CheckRequest{}); err != nil {
+		t.Errorf(`expect closed breaker to allow calls, but get %v`, err)
+	}
+}
+
+func TestIsUnreachable(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected bool
+	}{
+		{nil, false},
+		{errCircuitOpen, true},
+		{&googleapi.Error{Code: 503}, true},
+		{&googleapi.Error{Code: 429}, false},
+		{&net.OpError{Op: "dial", Err: errors.New("refused")}, true},
+		{errors.New("injected error"), false},
+	}
+	for _, tc := range testCases {
+		if actual := isUnreachable(tc.err); actual != tc.expected {
+			t.Errorf(`expect isUnreachable(%v) %v, but get %v`, tc.err, tc.expected, actual)
+		}
+	}
+}
+
+func TestNewResilientClient(t *testing.T) {
+	client := &mockSvcctrlClient{}
+	if c := newResilientClient(client, &config.RuntimeConfig{}); c != client {
+		t.Errorf(`expect client as is without retry policy and circuit breaker, but get %v`, c)
+	}
+	c := newResilientClient(client, &config.RuntimeConfig{
+		RetryPolicy: &config.RetryPolicy{MaxAttempts: 2},
+	}).(*resilientClient)
+	if c.initialBackoff != defaultInitialBackoff || c.maxBackoff != defaultMaxBackoff ||
+		c.budget.ratio != defaultBudgetRatio || c.breakers != nil {
+		t.Errorf(`expect default retry policy, but get %v`, *c)
+	}
+}
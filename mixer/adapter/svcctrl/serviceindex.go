@@ -0,0 +1,144 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gogo/protobuf/jsonpb"
+	multierror "github.com/hashicorp/go-multierror"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+)
+
+// Placeholders of google_service_name replaced with parts of the mesh service name matched by
+// a pattern.
+var servicePlaceholder = regexp.MustCompile(`\{([0-9]+)\}`)
+
+type (
+	// serviceIndex resolves mesh service names to service configs. Exact names take precedence
+	// over patterns, which are matched in config order.
+	serviceIndex struct {
+		exact    map[string]*config.GcpServiceSetting
+		patterns []servicePattern
+	}
+
+	servicePattern struct {
+		setting *config.GcpServiceSetting
+		regexp  *regexp.Regexp
+	}
+)
+
+// isServicePattern returns true if a mesh service name is a pattern.
+func isServicePattern(meshServiceName string) bool {
+	return strings.Contains(meshServiceName, "*")
+}
+
+// compileServicePattern compiles a mesh service name pattern, where each "*" matches and
+// captures a non-empty part of a name without ".".
+func compileServicePattern(pattern string) (*regexp.Regexp, error) {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.Compile("^" + strings.Join(parts, "([^.]+)") + "$")
+}
+
+// validateServiceName returns an error if the google service name of setting references a part
+// its mesh service name doesn't match.
+func validateServiceName(setting *config.GcpServiceSetting) error {
+	captures := strings.Count(setting.MeshServiceName, "*")
+	for _, match := range servicePlaceholder.FindAllStringSubmatch(setting.GoogleServiceName, -1) {
+		if n, err := strconv.Atoi(match[1]); err != nil || n < 1 || n > captures {
+			return fmt.Errorf("GoogleServiceName %s references %s, but MeshServiceName %s has %d patterns",
+				setting.GoogleServiceName, match[0], setting.MeshServiceName, captures)
+		}
+	}
+	return nil
+}
+
+// expandServiceName replaces placeholders of a google service name with captures, where
+// captures[0] is the whole match.
+func expandServiceName(googleServiceName string, captures []string) string {
+	return servicePlaceholder.ReplaceAllStringFunc(googleServiceName, func(placeholder string) string {
+		n, _ := strconv.Atoi(placeholder[1 : len(placeholder)-1])
+		return captures[n]
+	})
+}
+
+func newServiceIndex(settings []*config.GcpServiceSetting) (*serviceIndex, error) {
+	index := &serviceIndex{
+		exact: make(map[string]*config.GcpServiceSetting, len(settings)),
+	}
+	for _, setting := range settings {
+		if !isServicePattern(setting.MeshServiceName) {
+			index.exact[setting.MeshServiceName] = setting
+			continue
+		}
+		re, err := compileServicePattern(setting.MeshServiceName)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MeshServiceName pattern %s: %v", setting.MeshServiceName, err)
+		}
+		index.patterns = append(index.patterns, servicePattern{setting, re})
+	}
+	return index, nil
+}
+
+// lookup returns the service config of a mesh service, and the mesh service name it's
+// configured with. Configs matched by patterns are copies named after the mesh service, with
+// their google service name expanded.
+func (i *serviceIndex) lookup(meshServiceName string) (*config.GcpServiceSetting, string, bool) {
+	if setting, found := i.exact[meshServiceName]; found {
+		return setting, meshServiceName, true
+	}
+	for _, p := range i.patterns {
+		captures := p.regexp.FindStringSubmatch(meshServiceName)
+		if captures == nil {
+			continue
+		}
+		setting := *p.setting
+		setting.MeshServiceName = meshServiceName
+		setting.GoogleServiceName = expandServiceName(p.setting.GoogleServiceName, captures)
+		return &setting, p.setting.MeshServiceName, true
+	}
+	return nil, "", false
+}
+
+// hasPatterns returns true if any service config is matched by a pattern.
+func (i *serviceIndex) hasPatterns() bool {
+	return len(i.patterns) > 0
+}
+
+// parseServiceConfigs decodes and validates the service configs of a service configs file,
+// as they would be used in cfg.
+func parseServiceConfigs(content []byte, cfg *config.Params) ([]*config.GcpServiceSetting, error) {
+	var loaded config.Params
+	if err := jsonpb.Unmarshal(bytes.NewReader(content), &loaded); err != nil {
+		return nil, fmt.Errorf("fail to decode service configs: %v", err)
+	}
+	effective := *cfg
+	effective.ServiceConfigs = loaded.ServiceConfigs
+	result := validateGcpServiceSetting(loaded.ServiceConfigs)
+	result = multierror.Append(result, validateLocalQuotas(&effective))
+	result = multierror.Append(result, validateConsumerProjects(&effective))
+	if err := result.ErrorOrNil(); err != nil {
+		return nil, fmt.Errorf("invalid service configs: %v", err)
+	}
+	return loaded.ServiceConfigs, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"strings"
+	"testing"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+)
+
+func getTestServicePatterns() []*config.GcpServiceSetting {
+	return []*config.GcpServiceSetting{
+		{
+			MeshServiceName:   "*.payments.svc.cluster.local",
+			GoogleServiceName: "{1}.payments.cloud.goog",
+		},
+		{
+			MeshServiceName:   "ledger.payments.svc.cluster.local",
+			GoogleServiceName: "ledger.cloud.goog",
+		},
+		{
+			MeshServiceName:   "*.*.svc.cluster.local",
+			GoogleServiceName: "{2}-{1}.cloud.goog",
+			CredentialPath:    "default.json",
+		},
+	}
+}
+
+func TestServiceIndex(t *testing.T) {
+	index, err := newServiceIndex(getTestServicePatterns())
+	if err != nil {
+		t.Fatalf(`newServiceIndex() failed with %v`, err)
+	}
+	testCases := []struct {
+		meshServiceName   string
+		googleServiceName string
+		configuredName    string
+	}{
+		{"ledger.payments.svc.cluster.local", "ledger.cloud.goog", "ledger.payments.svc.cluster.local"},
+		{"wallet.payments.svc.cluster.local", "wallet.payments.cloud.goog", "*.payments.svc.cluster.local"},
+		{"echo.default.svc.cluster.local", "default-echo.cloud.goog", "*.*.svc.cluster.local"},
+		{"echo.svc.cluster.local", "", ""},
+		{"a.echo.default.svc.cluster.local", "", ""},
+		{"echo.default.svc.cluster.local.evil", "", ""},
+	}
+	for _, tc := range testCases {
+		setting, configuredName, found := index.lookup(tc.meshServiceName)
+		if found != (tc.googleServiceName != "") || configuredName != tc.configuredName {
+			t.Errorf(`expect %s to be configured as "%s", but get "%s", %v`,
+				tc.meshServiceName, tc.configuredName, configuredName, found)
+			continue
+		}
+		if found && (setting.MeshServiceName != tc.meshServiceName || setting.GoogleServiceName != tc.googleServiceName) {
+			t.Errorf(`expect %s to be served by %s, but get %v`, tc.meshServiceName, tc.googleServiceName, *setting)
+		}
+	}
+
+	// Settings matched by patterns are copies, so that configs are not modified.
+	setting, _, _ := index.lookup("echo.default.svc.cluster.local")
+	if setting.CredentialPath != "default.json" || index.patterns[1].setting.GoogleServiceName != "{2}-{1}.cloud.goog" {
+		t.Errorf(`expect a copy of the pattern config, but get %v`, *setting)
+	}
+}
+
+func TestValidateServiceName(t *testing.T) {
+	testCases := []struct {
+		meshServiceName   string
+		googleServiceName string
+		valid             bool
+	}{
+		{"echo.default.svc.cluster.local", "echo.cloud.goog", true},
+		{"*.*.svc.cluster.local", "{2}-{1}.cloud.goog", true},
+		{"*.svc.cluster.local", "{0}.cloud.goog", false},
+		{"*.svc.cluster.local", "{2}.cloud.goog", false},
+		{"echo.default.svc.cluster.local", "{1}.cloud.goog", false},
+	}
+	for _, tc := range testCases {
+		err := validateServiceName(&config.GcpServiceSetting{
+			MeshServiceName:   tc.meshServiceName,
+			GoogleServiceName: tc.googleServiceName,
+		})
+		if (err == nil) != tc.valid {
+			t.Errorf(`expect %s => %s valid %v, but get %v`, tc.meshServiceName, tc.googleServiceName, tc.valid, err)
+		}
+	}
+}
+
+func TestParseServiceConfigs(t *testing.T) {
+	cfg := getTestAdapterConfig()
+	settings, err := parseServiceConfigs([]byte(`{
+		"serviceConfigs": [
+			{
+				"meshServiceName": "*.payments.svc.cluster.local",
+				"googleServiceName": "{1}.payments.cloud.goog",
+				"quotas": [{"name": "ratelimit", "googleQuotaMetricName": "read-requests", "expiration": "60s"}]
+			}
+		]
+	}`), cfg)
+	if err != nil {
+		t.Fatalf(`parseServiceConfigs() failed with %v`, err)
+	}
+	if len(settings) != 1 || settings[0].GoogleServiceName != "{1}.payments.cloud.goog" ||
+		toDuration(settings[0].Quotas[0].Expiration).Seconds() != 60 {
+		t.Errorf(`unexpected service configs %v`, settings)
+	}
+
+	for _, content := range []string{
+		`{"serviceConfigs": [`,
+		`{"serviceConfigs": []}`,
+		`{"serviceConfigs": [{"meshServiceName": "*.svc.cluster.local", "googleServiceName": "{2}.cloud.goog"}]}`,
+	} {
+		if _, err := parseServiceConfigs([]byte(content), cfg); err == nil ||
+			!strings.Contains(err.Error(), "service configs") {
+			t.Errorf(`expect %s to be rejected, but get %v`, content, err)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"bytes"
+	"container/list"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sync"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+)
+
+const (
+	// Quota dimension and logentry variable naming the mesh service of routed requests.
+	apiServiceDimension = "api_service"
+
+	// Default of RuntimeConfig.MaxServiceProcessors when it's not configured.
+	defaultMaxServiceProcessors = 1000
+)
+
+// errNoMeshService is returned for routed requests whose instance names no mesh service.
+var errNoMeshService = errors.New("instance names no mesh service")
+
+type (
+	// serviceRouter routes requests to processors of the mesh services their instances name.
+	// Processors are built on the first request of a service, and replaced once its service
+	// config changes on reload. Processors of the least recently used services are retired
+	// once there are more than maxProcessors, since mesh service names come from attributes.
+	serviceRouter struct {
+		ctx *handlerContext
+		// Empty when service configs are not loaded from a file.
+		path string
+		// Clients of credential paths of services, which reloaded services may use.
+		credentialClients map[string]serviceControlClient
+		// Replaced processors are closed after this delay, so that requests in flight finish.
+		retireDelay   time.Duration
+		maxProcessors int
+		newProcessor  func(meshServiceName string, ctx *handlerContext) (*serviceProcessor, error)
+		done          chan struct{}
+		closeOnce     sync.Once // closes done once, however many times the router is closed
+
+		lock sync.Mutex // guards fields below, and serializes reloads with building processors
+		// Content of the service configs file last loaded.
+		content    []byte
+		processors map[string]*routedProcessor
+		// Mesh service names of processors, the most recently used first.
+		recentlyUsed *list.List
+	}
+
+	routedProcessor struct {
+		*serviceProcessor
+		// Service config and client the processor was built with.
+		setting *config.GcpServiceSetting
+		client  serviceControlClient
+		// Element of the mesh service name in recentlyUsed.
+		element *list.Element
+	}
+)
+
+// dynamicServiceRouting returns true if requests of cfg are routed to the mesh services named by
+// their instances, rather than all served by the first service config.
+func dynamicServiceRouting(cfg *config.Params) bool {
+	if cfg.ServiceConfigsPath != "" {
+		return true
+	}
+	for _, setting := range cfg.ServiceConfigs {
+		if isServicePattern(setting.MeshServiceName) {
+			return true
+		}
+	}
+	return false
+}
+
+// processor returns the processor of a mesh service, and builds one if there is none yet.
+func (r *serviceRouter) processor(meshServiceName string) (*serviceProcessor, error) {
+	if meshServiceName == "" {
+		return nil, errNoMeshService
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if p, found := r.processors[meshServiceName]; found {
+		r.recentlyUsed.MoveToFront(p.element)
+		return p.serviceProcessor, nil
+	}
+	setting, client, err := r.ctx.service(meshServiceName)
+	if err != nil {
+		return nil, err
+	}
+	proc, err := r.newProcessor(meshServiceName, r.ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.processors[meshServiceName] = &routedProcessor{proc, setting, client, r.recentlyUsed.PushFront(meshServiceName)}
+	for r.recentlyUsed.Len() > r.maxProcessors {
+		r.remove(r.recentlyUsed.Back().Value.(string))
+	}
+	return proc, nil
+}
+
+// remove retires the processor of a mesh service. It must be called with lock held.
+func (r *serviceRouter) remove(meshServiceName string) {
+	p := r.processors[meshServiceName]
+	delete(r.processors, meshServiceName)
+	r.recentlyUsed.Remove(p.element)
+	r.retire(p.serviceProcessor)
+}
+
+// reload re-reads the service configs file, and swaps its service configs in if it changed.
+// Processors of services whose service config or client changed are replaced, others are kept.
+// Operations buffered by replaced processors are flushed right away, rather than once they're
+// retired, so that they're reported with the service config they were built with.
+func (r *serviceRouter) reload() error {
+	content, err := ioutil.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("fail to read service configs: %v", err)
+	}
+	replaced, err := r.swap(content)
+	if err != nil {
+		return err
+	}
+	// Flushed without lock held, so that routing isn't blocked on reports.
+	for meshServiceName, proc := range replaced {
+		if flushed := proc.flushReports(); flushed > 0 {
+			r.ctx.env.Logger().Infof("flushed %d pending report operations of replaced service processor of %s",
+				flushed, meshServiceName)
+		}
+	}
+	return nil
+}
+
+// swap swaps in service configs of content if it changed, and returns the replaced processors
+// keyed by mesh service name.
+func (r *serviceRouter) swap(content []byte) (map[string]*serviceProcessor, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if bytes.Equal(content, r.content) {
+		return nil, nil
+	}
+	settings, err := parseServiceConfigs(content, r.ctx.config)
+	if err != nil {
+		return nil, err
+	}
+	services, err := newServiceIndex(settings)
+	if err != nil {
+		return nil, err
+	}
+	serviceClients, err := r.serviceClients(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	r.ctx.setServices(services, serviceClients)
+	r.content = content
+	replaced := make(map[string]*serviceProcessor)
+	for meshServiceName, p := range r.processors {
+		setting, client, err := r.ctx.service(meshServiceName)
+		if err == nil && client == p.client && reflect.DeepEqual(setting, p.setting) {
+			continue
+		}
+		replaced[meshServiceName] = p.serviceProcessor
+		r.remove(meshServiceName)
+	}
+	r.ctx.env.Logger().Infof("reloaded %d service configs from %s, replaced %d service processors",
+		len(settings), r.path, len(replaced))
+	return replaced, nil
+}
+
+// serviceClients returns clients of reloaded services with their own credential, which must be
+// among credentials loaded when the handler was built.
+func (r *serviceRouter) serviceClients(settings []*config.GcpServiceSetting) (map[string]serviceControlClient, error) {
+	cfg := *r.ctx.config
+	cfg.ServiceConfigs = settings
+	_, serviceClients, err := newServiceClients(&cfg,
+		func() (serviceControlClient, error) {
+			if r.ctx.client == nil {
+				return nil, errors.New("services without their own credential require a handler-level credential")
+			}
+			return r.ctx.client, nil
+		},
+		func(credentialPath string) (serviceControlClient, error) {
+			if c, found := r.credentialClients[credentialPath]; found {
+				return c, nil
+			}
+			return nil, errors.New("credential is not loaded, the handler must be rebuilt to add it")
+		})
+	return serviceClients, err
+}
+
+// retire closes a replaced processor once requests in flight are done with it, flushing
+// operations those requests buffered.
+func (r *serviceRouter) retire(proc *serviceProcessor) {
+	time.AfterFunc(r.retireDelay, func() {
+		r.ctx.env.ScheduleWork(func() {
+			if err := proc.Close(); err != nil {
+				r.ctx.warningLogger.Warningf("fail to close replaced service processor: %v", err)
+			}
+		})
+	})
+}
+
+// run reloads service configs on interval until the router is closed.
+func (r *serviceRouter) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.reload(); err != nil {
+				r.ctx.warningLogger.Warningf("keep current service configs, reload failed: %v", err)
+			}
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// close stops reloading service configs, and closes processors of all services. Closing the
+// router again is a no-op.
+func (r *serviceRouter) close() error {
+	r.closeOnce.Do(func() { close(r.done) })
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var result *multierror.Error
+	for meshServiceName, p := range r.processors {
+		if err := p.Close(); err != nil {
+			result = multierror.Append(result, err)
+		}
+		delete(r.processors, meshServiceName)
+	}
+	r.recentlyUsed.Init()
+	return result.ErrorOrNil()
+}
+
+// newServiceRouter creates a router of ctx, whose processors are retired after retireDelay.
+func newServiceRouter(ctx *handlerContext, retireDelay time.Duration) *serviceRouter {
+	credentialClients := make(map[string]serviceControlClient)
+	for _, setting := range ctx.config.ServiceConfigs {
+		if c, found := ctx.serviceClients[setting.MeshServiceName]; found {
+			credentialClients[setting.CredentialPath] = c
+		}
+	}
+	maxProcessors := int(ctx.config.RuntimeConfig.MaxServiceProcessors)
+	if maxProcessors <= 0 {
+		maxProcessors = defaultMaxServiceProcessors
+	}
+	return &serviceRouter{
+		ctx:               ctx,
+		path:              ctx.config.ServiceConfigsPath,
+		credentialClients: credentialClients,
+		retireDelay:       retireDelay,
+		maxProcessors:     maxProcessors,
+		newProcessor:      newServiceProcessor,
+		done:              make(chan struct{}),
+		content:           ctx.serviceConfigsContent,
+		processors:        make(map[string]*routedProcessor),
+		recentlyUsed:      list.New(),
+	}
+}
@@ -0,0 +1,391 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pbtypes "github.com/gogo/protobuf/types"
+	"google.golang.org/api/googleapi"
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+	"istio.io/istio/mixer/adapter/svcctrl/template/svcctrlreport"
+	at "istio.io/istio/mixer/pkg/adapter/test"
+	"istio.io/istio/mixer/pkg/status"
+	"istio.io/istio/mixer/template/apikey"
+)
+
+// recordingReportProcessor records the mesh services of reported instances.
+type recordingReportProcessor struct {
+	mockReportProcessor
+	reported *[]string
+}
+
+func (p *recordingReportProcessor) ProcessReport(ctx context.Context, instances []*svcctrlreport.Instance) error {
+	for _, instance := range instances {
+		*p.reported = append(*p.reported, instance.ApiService)
+	}
+	return nil
+}
+
+// closingReportProcessor sends its mesh service to closed once it's closed.
+type closingReportProcessor struct {
+	mockReportProcessor
+	meshServiceName string
+	closed          chan string
+}
+
+func (p *closingReportProcessor) Close() error {
+	p.closed <- p.meshServiceName
+	return nil
+}
+
+// logEntryCountingClient counts log entries of reported operations by Google service name.
+type logEntryCountingClient struct {
+	mockSvcctrlClient
+	lock       sync.Mutex
+	logEntries map[string]int
+}
+
+func (c *logEntryCountingClient) Report(serviceName string, request *sc.ReportRequest) (*sc.ReportResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, op := range request.Operations {
+		c.logEntries[serviceName] += len(op.LogEntries)
+	}
+	return &sc.ReportResponse{}, nil
+}
+
+func (c *logEntryCountingClient) reported(serviceName string) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.logEntries[serviceName]
+}
+
+func TestHandlerRouting(t *testing.T) {
+	adapterCfg := &config.Params{
+		RuntimeConfig: &config.RuntimeConfig{
+			CheckResultExpiration: &pbtypes.Duration{Seconds: 300},
+		},
+		ServiceConfigs: getTestServicePatterns(),
+	}
+	mockClient := &mockSvcctrlClient{}
+	ctx, err := initializeHandlerContext(at.NewEnv(t), adapterCfg, mockClient)
+	if err != nil {
+		t.Fatalf(`initializeHandlerContext() failed with %v`, err)
+	}
+	h, err := newHandler(ctx)
+	if err != nil || h.router == nil {
+		t.Fatalf(`expect handler with service router, but get %v, %v`, h, err)
+	}
+
+	mockClient.setCheckResponse(&sc.CheckResponse{
+		ServerResponse: googleapi.ServerResponse{HTTPStatusCode: 200},
+	})
+	for i := 0; i < 2; i++ {
+		result, err := h.HandleApiKey(context.Background(), &apikey.Instance{
+			Api:          "wallet.payments.svc.cluster.local",
+			ApiKey:       "test_key",
+			ApiOperation: "echo",
+			Timestamp:    time.Now(),
+		})
+		if err != nil || !status.IsOK(result.Status) || mockClient.serviceName != "wallet.payments.cloud.goog" {
+			t.Errorf(`expect check of wallet.payments.cloud.goog, but get %v, %v of %s`,
+				result, err, mockClient.serviceName)
+		}
+	}
+	if len(h.router.processors) != 1 {
+		t.Errorf(`expect a processor per mesh service, but get %v`, h.router.processors)
+	}
+
+	if _, err := h.HandleApiKey(context.Background(), &apikey.Instance{}); err != errNoMeshService {
+		t.Errorf(`expect %v, but get %v`, errNoMeshService, err)
+	}
+	if _, err := h.HandleApiKey(context.Background(), &apikey.Instance{
+		Api: "echo.svc.cluster.local",
+	}); err == nil || !strings.Contains(err.Error(), "unknown mesh service") {
+		t.Errorf(`expect unknown mesh service, but get %v`, err)
+	}
+	if err := h.Close(); err != nil {
+		t.Errorf(`Close() failed with %v`, err)
+	}
+}
+
+func TestHandlerRouteReports(t *testing.T) {
+	ctx, err := initializeHandlerContext(at.NewEnv(t), &config.Params{
+		RuntimeConfig:  &config.RuntimeConfig{},
+		ServiceConfigs: getTestServicePatterns(),
+	}, &mockSvcctrlClient{})
+	if err != nil {
+		t.Fatalf(`initializeHandlerContext() failed with %v`, err)
+	}
+	router := newServiceRouter(ctx, 0)
+	reported := make(map[string]*[]string)
+	router.newProcessor = func(meshServiceName string, ctx *handlerContext) (*serviceProcessor, error) {
+		reported[meshServiceName] = &[]string{}
+		return &serviceProcessor{
+			reportProcessor: &recordingReportProcessor{reported: reported[meshServiceName]},
+		}, nil
+	}
+	h := &handler{ctx: ctx, router: router}
+
+	wallet := "wallet.payments.svc.cluster.local"
+	echo := "echo.default.svc.cluster.local"
+	err = h.HandleSvcctrlReport(context.Background(), []*svcctrlreport.Instance{
+		{ApiService: wallet}, {ApiService: echo}, {ApiService: ""}, {ApiService: wallet},
+	})
+	if err == nil || !strings.Contains(err.Error(), errNoMeshService.Error()) {
+		t.Errorf(`expect instance without mesh service to fail, but get %v`, err)
+	}
+	if !reflect.DeepEqual(*reported[wallet], []string{wallet, wallet}) || !reflect.DeepEqual(*reported[echo], []string{echo}) {
+		t.Errorf(`expect instances to be reported by their services, but get %v, %v`, *reported[wallet], *reported[echo])
+	}
+}
+
+func TestServiceRouterEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx, err := initializeHandlerContext(at.NewEnv(t), &config.Params{
+		RuntimeConfig: &config.RuntimeConfig{
+			MaxServiceProcessors: 2,
+		},
+		ServiceConfigs: getTestServicePatterns(),
+	}, &mockSvcctrlClient{})
+	if err != nil {
+		t.Fatalf(`initializeHandlerContext() failed with %v`, err)
+	}
+	router := newServiceRouter(ctx, 0)
+	closed := make(chan string, 3)
+	router.newProcessor = func(meshServiceName string, ctx *handlerContext) (*serviceProcessor, error) {
+		return &serviceProcessor{
+			reportProcessor: &closingReportProcessor{meshServiceName: meshServiceName, closed: closed},
+		}, nil
+	}
+
+	wallet := "wallet.payments.svc.cluster.local"
+	ledger := "ledger.payments.svc.cluster.local"
+	echo := "echo.default.svc.cluster.local"
+	for _, meshServiceName := range []string{wallet, ledger, wallet, echo} {
+		if _, err := router.processor(meshServiceName); err != nil {
+			t.Fatalf(`processor() failed with %v`, err)
+		}
+	}
+	select {
+	case evicted := <-closed:
+		if evicted != ledger {
+			t.Errorf(`expect least recently used %s to be closed, but get %s`, ledger, evicted)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal(`expect evicted processor to be closed`)
+	}
+	if len(router.processors) != 2 || router.processors[wallet] == nil || router.processors[echo] == nil {
+		t.Errorf(`expect processors of recently used services, but get %v`, router.processors)
+	}
+	for i := 0; i < 2; i++ {
+		if err := router.close(); err != nil {
+			t.Errorf(`close() %d failed with %v`, i, err)
+		}
+	}
+}
+
+func TestServiceRouterReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "svcctrl")
+	if err != nil {
+		t.Fatalf(`fail to create temp dir: %v`, err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "services.json")
+	writeServiceConfigs := func(echoGoogleServiceName, echoCredentialPath string) []byte {
+		content := []byte(`{"serviceConfigs": [
+			{"meshServiceName": "echo.default.svc.cluster.local", "googleServiceName": "` + echoGoogleServiceName +
+			`", "credentialPath": "` + echoCredentialPath + `"},
+			{"meshServiceName": "*.payments.svc.cluster.local", "googleServiceName": "{1}.payments.cloud.goog"}
+		]}`)
+		if err := ioutil.WriteFile(path, content, 0600); err != nil {
+			t.Fatalf(`fail to write service configs: %v`, err)
+		}
+		return content
+	}
+
+	adapterCfg := &config.Params{
+		RuntimeConfig: &config.RuntimeConfig{
+			CheckResultExpiration: &pbtypes.Duration{Seconds: 300},
+		},
+		ServiceConfigsPath: path,
+	}
+	content := writeServiceConfigs("echo.cloud.goog", "")
+	settings, err := parseServiceConfigs(content, adapterCfg)
+	if err != nil {
+		t.Fatalf(`parseServiceConfigs() failed with %v`, err)
+	}
+	adapterCfg.ServiceConfigs = settings
+	env := at.NewEnv(t)
+	ctx, err := initializeHandlerContext(env, adapterCfg, &mockSvcctrlClient{})
+	if err != nil {
+		t.Fatalf(`initializeHandlerContext() failed with %v`, err)
+	}
+	ctx.serviceConfigsContent = content
+	router := newServiceRouter(ctx, 0)
+	defer func() {
+		if err := router.close(); err != nil {
+			t.Errorf(`close() failed with %v`, err)
+		}
+	}()
+
+	echo, err := router.processor("echo.default.svc.cluster.local")
+	if err != nil {
+		t.Fatalf(`processor() failed with %v`, err)
+	}
+	wallet, err := router.processor("wallet.payments.svc.cluster.local")
+	if err != nil {
+		t.Fatalf(`processor() failed with %v`, err)
+	}
+	if err := router.reload(); err != nil || len(env.GetLogs()) != 0 {
+		t.Errorf(`expect unchanged file not to be reloaded, but get %v, %v`, err, env.GetLogs())
+	}
+
+	// Only processors of changed services are replaced.
+	writeServiceConfigs("echo-v2.cloud.goog", "")
+	if err := router.reload(); err != nil {
+		t.Fatalf(`reload() failed with %v`, err)
+	}
+	if p, _ := router.processor("echo.default.svc.cluster.local"); p == echo {
+		t.Error(`expect processor of changed service to be replaced`)
+	}
+	if p, _ := router.processor("wallet.payments.svc.cluster.local"); p != wallet {
+		t.Error(`expect processor of unchanged service to be kept`)
+	}
+	if setting, _, err := ctx.service("echo.default.svc.cluster.local"); err != nil ||
+		setting.GoogleServiceName != "echo-v2.cloud.goog" {
+		t.Errorf(`expect reloaded service config, but get %v, %v`, setting, err)
+	}
+
+	// Invalid service configs are not swapped in.
+	for _, tc := range [][2]string{
+		{"{1}.cloud.goog", ""},
+		{"echo-v3.cloud.goog", "echo.json"},
+	} {
+		content := writeServiceConfigs(tc[0], tc[1])
+		if err := router.reload(); err == nil {
+			t.Errorf(`expect reload of %s to fail`, content)
+		}
+	}
+	if setting, _, _ := ctx.service("echo.default.svc.cluster.local"); setting.GoogleServiceName != "echo-v2.cloud.goog" {
+		t.Errorf(`expect service config to be kept, but get %v`, *setting)
+	}
+}
+
+func TestServiceRouterReloadFlushesPendingReports(t *testing.T) {
+	dir, err := ioutil.TempDir("", "svcctrl")
+	if err != nil {
+		t.Fatalf(`fail to create temp dir: %v`, err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "services.json")
+	writeServiceConfigs := func(googleServiceName string) []byte {
+		content := []byte(`{"serviceConfigs": [{"meshServiceName": "echo.default.svc.cluster.local", ` +
+			`"googleServiceName": "` + googleServiceName + `"}]}`)
+		if err := ioutil.WriteFile(path, content, 0600); err != nil {
+			t.Fatalf(`fail to write service configs: %v`, err)
+		}
+		return content
+	}
+
+	adapterCfg := &config.Params{
+		RuntimeConfig: &config.RuntimeConfig{
+			CheckResultExpiration: &pbtypes.Duration{Seconds: 300},
+			EnableReport:          true,
+			ReportFlushInterval:   &pbtypes.Duration{Seconds: 3600},
+		},
+		ServiceConfigsPath: path,
+	}
+	content := writeServiceConfigs("echo.cloud.goog")
+	settings, err := parseServiceConfigs(content, adapterCfg)
+	if err != nil {
+		t.Fatalf(`parseServiceConfigs() failed with %v`, err)
+	}
+	adapterCfg.ServiceConfigs = settings
+	client := &logEntryCountingClient{logEntries: make(map[string]int)}
+	ctx, err := initializeHandlerContext(at.NewEnv(t), adapterCfg, client)
+	if err != nil {
+		t.Fatalf(`initializeHandlerContext() failed with %v`, err)
+	}
+	defer ctx.reportFlushPool.close()
+	ctx.serviceConfigsContent = content
+	// Replaced processors are not closed during the test.
+	router := newServiceRouter(ctx, time.Hour)
+	report := func(proc *serviceProcessor, count int) {
+		for i := 0; i < count; i++ {
+			if err := proc.ProcessReport(context.Background(),
+				[]*svcctrlreport.Instance{getTestReportInstance()}); err != nil {
+				t.Fatalf(`ProcessReport() failed with %v`, err)
+			}
+		}
+	}
+
+	old, err := router.processor("echo.default.svc.cluster.local")
+	if err != nil {
+		t.Fatalf(`processor() failed with %v`, err)
+	}
+	report(old, 3)
+	if reported := client.reported("echo.cloud.goog"); reported != 0 {
+		t.Fatalf(`expect operations to be buffered, but get %d reported`, reported)
+	}
+
+	writeServiceConfigs("echo-v2.cloud.goog")
+	if err := router.reload(); err != nil {
+		t.Fatalf(`reload() failed with %v`, err)
+	}
+	if reported := client.reported("echo.cloud.goog"); reported != 3 {
+		t.Errorf(`expect pending operations to be flushed on reload, but get %d reported`, reported)
+	}
+
+	// Requests in flight on the replaced processor, and new requests on its replacement.
+	report(old, 1)
+	current, err := router.processor("echo.default.svc.cluster.local")
+	if err != nil || current == old {
+		t.Fatalf(`expect processor to be replaced, but get %v`, err)
+	}
+	report(current, 2)
+	if err := old.Close(); err != nil {
+		t.Errorf(`Close() failed with %v`, err)
+	}
+	if err := router.close(); err != nil {
+		t.Errorf(`close() failed with %v`, err)
+	}
+	if reported := client.reported("echo.cloud.goog"); reported != 4 {
+		t.Errorf(`expect 4 operations reported with the old service config, but get %d`, reported)
+	}
+	if reported := client.reported("echo-v2.cloud.goog"); reported != 2 {
+		t.Errorf(`expect 2 operations reported with the new service config, but get %d`, reported)
+	}
+}
+
+func TestBuildWithMissingServiceConfigs(t *testing.T) {
+	b := getTestBuilder()
+	b.config.ServiceConfigs = nil
+	b.config.ServiceConfigsPath = filepath.Join(os.TempDir(), "svcctrl-missing", "services.json")
+	if _, err := b.Build(context.Background(), at.NewEnv(t)); err == nil ||
+		!strings.Contains(err.Error(), "fail to read service configs") {
+		t.Errorf(`expect Build() to fail on missing service configs, but get %v`, err)
+	}
+}
@@ -18,6 +18,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"strings"
+	"time"
 
 	pbtypes "github.com/gogo/protobuf/types"
 	multierror "github.com/hashicorp/go-multierror"
@@ -26,15 +31,33 @@ import (
 	"istio.io/istio/mixer/adapter/svcctrl/template/svcctrlreport"
 	"istio.io/istio/mixer/pkg/adapter"
 	"istio.io/istio/mixer/template/apikey"
+	"istio.io/istio/mixer/template/logentry"
 	"istio.io/istio/mixer/template/quota"
 )
 
+const (
+	// Quota deadline must be shorter than typical request deadlines.
+	maxQuotaDeadline = 10 * time.Second
+
+	// Quota coalescing adds up to its window to quota latency.
+	maxQuotaCoalescingWindow = time.Second
+
+	// Metric time alignment must evenly divide this period.
+	metricTimeAlignmentPeriod = time.Hour
+
+	// Limits of quotas per service. A warning is logged above the soft limit, and config
+	// above the hard limit is rejected.
+	softMaxQuotasPerService = 100
+	hardMaxQuotasPerService = 1000
+)
+
 // svcctrl adapter builder
 type builder struct {
-	config          *config.Params // Handler config
-	checkDataShape  map[string]*apikey.Type
-	reportDataShape map[string]*svcctrlreport.Type
-	quotaDataShape  map[string]*quota.Type
+	config            *config.Params // Handler config
+	checkDataShape    map[string]*apikey.Type
+	reportDataShape   map[string]*svcctrlreport.Type
+	quotaDataShape    map[string]*quota.Type
+	logEntryDataShape map[string]*logentry.Type
 }
 
 ////// Builder method from supported template //////
@@ -54,6 +77,11 @@ func (b *builder) SetQuotaTypes(types map[string]*quota.Type) {
 	b.quotaDataShape = types
 }
 
+// SetLogEntryTypes sets logentry template data type.
+func (b *builder) SetLogEntryTypes(types map[string]*logentry.Type) {
+	b.logEntryDataShape = types
+}
+
 ////// adapter.HandlerBuilder interface //////
 
 // SetAdapterConfig sets adapter config on builder.
@@ -67,13 +95,175 @@ func (b *builder) SetAdapterConfig(cfg adapter.Config) {
 // Validate validates adapter config.
 func (b *builder) Validate() *adapter.ConfigErrors {
 	result := validateRuntimeConfig(b.config.RuntimeConfig)
-	result = multierror.Append(result, validateGcpServiceSetting(b.config.ServiceConfigs))
+	result = multierror.Append(result, validateCredential(b.config))
+	result = multierror.Append(result, validateLocalQuotas(b.config))
+	result = multierror.Append(result, validateConsumerProjects(b.config))
+	result = multierror.Append(result, validateServiceConfigsPath(b.config))
+	if !allowEmptyServiceConfigs(b.config) && b.config.ServiceConfigsPath == "" {
+		result = multierror.Append(result, validateGcpServiceSetting(b.config.ServiceConfigs))
+	}
 	if result.ErrorOrNil() != nil {
 		return &adapter.ConfigErrors{Multi: result}
 	}
 	return nil
 }
 
+// allowEmptyServiceConfigs returns true if cfg has no service configs and allows so.
+func allowEmptyServiceConfigs(cfg *config.Params) bool {
+	return len(cfg.ServiceConfigs) == 0 && cfg.RuntimeConfig != nil &&
+		cfg.RuntimeConfig.AllowEmptyServiceConfigs
+}
+
+func validateCredential(cfg *config.Params) *multierror.Error {
+	var result *multierror.Error
+	if _, found := config.Params_CredentialMode_name[int32(cfg.CredentialMode)]; !found {
+		result = multierror.Append(result, fmt.Errorf("unknown CredentialMode %v", cfg.CredentialMode))
+	}
+	if cfg.CredentialMode != config.JSON_KEY_FILE && cfg.CredentialPath != "" {
+		result = multierror.Append(result,
+			fmt.Errorf("CredentialPath must not be set with CredentialMode %v", cfg.CredentialMode))
+	}
+	if cfg.RuntimeConfig == nil || cfg.RuntimeConfig.MetadataServerEndpoint == "" {
+		return result
+	}
+	endpoint := cfg.RuntimeConfig.MetadataServerEndpoint
+	if cfg.CredentialMode != config.METADATA_SERVER {
+		result = multierror.Append(result,
+			fmt.Errorf("MetadataServerEndpoint must not be set with CredentialMode %v", cfg.CredentialMode))
+	}
+	if u, err := url.Parse(endpoint); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") ||
+		strings.Trim(u.Path, "/") != "" {
+		result = multierror.Append(result,
+			fmt.Errorf("MetadataServerEndpoint must be an http or https URL without a path, but get %q", endpoint))
+	}
+	return result
+}
+
+func validateQuotaAllocation(qCfg *config.Quota) *multierror.Error {
+	var result *multierror.Error
+	if _, found := config.Quota_AllocationMode_name[int32(qCfg.AllocationMode)]; !found {
+		result = multierror.Append(result,
+			fmt.Errorf("unknown AllocationMode %v of quota %s", qCfg.AllocationMode, qCfg.Name))
+	}
+	if qCfg.PrefetchAmount < 0 {
+		result = multierror.Append(result,
+			fmt.Errorf("expect non-negative PrefetchAmount of quota %s, but get %v", qCfg.Name, qCfg.PrefetchAmount))
+	}
+	if qCfg.ReconcileInterval != nil {
+		interval, err := pbtypes.DurationFromProto(qCfg.ReconcileInterval)
+		if err != nil {
+			result = multierror.Append(result, err)
+		} else if interval <= 0 {
+			result = multierror.Append(result,
+				fmt.Errorf("expect positive ReconcileInterval of quota %s, but get %v", qCfg.Name, interval))
+		}
+	}
+	if qCfg.AllocationMode != config.BEST_EFFORT_LOCAL && (qCfg.PrefetchAmount != 0 || qCfg.ReconcileInterval != nil) {
+		result = multierror.Append(result,
+			fmt.Errorf("PrefetchAmount and ReconcileInterval of quota %s require BEST_EFFORT_LOCAL", qCfg.Name))
+	}
+	return result
+}
+
+// validateLocalQuotas rejects local quotas with quota settings which need a Mixer call per allocation.
+func validateLocalQuotas(cfg *config.Params) *multierror.Error {
+	var result *multierror.Error
+	if cfg.RuntimeConfig == nil || !cfg.RuntimeConfig.UseDedupIdAsOperationId {
+		return result
+	}
+	for _, setting := range cfg.ServiceConfigs {
+		for _, qCfg := range setting.Quotas {
+			if qCfg.AllocationMode == config.BEST_EFFORT_LOCAL {
+				result = multierror.Append(result,
+					fmt.Errorf("quota %s in BEST_EFFORT_LOCAL mode can't be used with UseDedupIdAsOperationId", qCfg.Name))
+			}
+		}
+	}
+	return result
+}
+
+// validateServiceConfigsPath validates loading service configs from ServiceConfigsPath.
+func validateServiceConfigsPath(cfg *config.Params) *multierror.Error {
+	var result *multierror.Error
+	if cfg.ServiceConfigsPath != "" && len(cfg.ServiceConfigs) > 0 {
+		result = multierror.Append(result,
+			errors.New("ServiceConfigs must not be set along with ServiceConfigsPath"))
+	}
+	if cfg.RuntimeConfig == nil || cfg.RuntimeConfig.ServiceConfigsReloadInterval == nil {
+		return result
+	}
+	if cfg.ServiceConfigsPath == "" {
+		result = multierror.Append(result,
+			errors.New("ServiceConfigsReloadInterval requires ServiceConfigsPath"))
+	}
+	if interval, err := pbtypes.DurationFromProto(cfg.RuntimeConfig.ServiceConfigsReloadInterval); err != nil {
+		result = multierror.Append(result, err)
+	} else if interval <= 0 {
+		result = multierror.Append(result,
+			fmt.Errorf("ServiceConfigsReloadInterval must be positive, but get %v", interval))
+	}
+	return result
+}
+
+func validateAdaptiveFailOpen(config *config.AdaptiveFailOpen) *multierror.Error {
+	var result *multierror.Error
+	if config.ErrorRateThreshold <= 0 || config.ErrorRateThreshold > 1 {
+		result = multierror.Append(result,
+			fmt.Errorf("expect ErrorRateThreshold in (0, 1], but get %v", config.ErrorRateThreshold))
+	}
+
+	if config.Window == nil {
+		result = multierror.Append(result, errors.New("AdaptiveFailOpen.Window is nil"))
+		return result
+	}
+	window, err := pbtypes.DurationFromProto(config.Window)
+	if err != nil {
+		result = multierror.Append(result, err)
+	} else if window <= 0 {
+		result = multierror.Append(
+			result, fmt.Errorf("expect positive AdaptiveFailOpen.Window, but get %v", window))
+	}
+	return result
+}
+
+func validateNetworkFailPolicy(policy config.RuntimeConfig_NetworkFailPolicy) error {
+	if _, found := config.RuntimeConfig_NetworkFailPolicy_name[int32(policy)]; !found {
+		return fmt.Errorf("unknown NetworkFailPolicy %v", policy)
+	}
+	return nil
+}
+
+func validateRetryPolicy(config *config.RetryPolicy) *multierror.Error {
+	var result *multierror.Error
+	if config.MaxAttempts < 1 || config.MaxAttempts > maxRetryAttempts {
+		result = multierror.Append(result,
+			fmt.Errorf("expect MaxAttempts in [1, %d], but get %v", maxRetryAttempts, config.MaxAttempts))
+	}
+	if config.BudgetRatio < 0 || config.BudgetRatio > 1 {
+		result = multierror.Append(result, fmt.Errorf("expect BudgetRatio in (0, 1], or 0 for the default, but get %v", config.BudgetRatio))
+	}
+
+	initialBackoff, maxBackoff := defaultInitialBackoff, defaultMaxBackoff
+	var err error
+	if config.InitialBackoff != nil {
+		if initialBackoff, err = pbtypes.DurationFromProto(config.InitialBackoff); err != nil {
+			result = multierror.Append(result, err)
+		} else if initialBackoff <= 0 {
+			result = multierror.Append(result, fmt.Errorf("expect positive InitialBackoff, but get %v", initialBackoff))
+		}
+	}
+	if config.MaxBackoff != nil {
+		if maxBackoff, err = pbtypes.DurationFromProto(config.MaxBackoff); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	if maxBackoff < initialBackoff {
+		result = multierror.Append(result,
+			fmt.Errorf("expect MaxBackoff at least InitialBackoff %v, but get %v", initialBackoff, maxBackoff))
+	}
+	return result
+}
+
 func validateRuntimeConfig(config *config.RuntimeConfig) *multierror.Error {
 	var result *multierror.Error
 	if config == nil {
@@ -81,6 +271,195 @@ func validateRuntimeConfig(config *config.RuntimeConfig) *multierror.Error {
 		return result
 	}
 
+	if config.WarningLogRate < 0 {
+		result = multierror.Append(result,
+			fmt.Errorf("expect non-negative WarningLogRate, but get %v", config.WarningLogRate))
+	}
+
+	if config.MaxServiceProcessors < 0 {
+		result = multierror.Append(result,
+			fmt.Errorf("expect non-negative MaxServiceProcessors, but get %v", config.MaxServiceProcessors))
+	}
+
+	if config.CheckCacheSize < 0 {
+		result = multierror.Append(result,
+			fmt.Errorf("expect non-negative CheckCacheSize, but get %v", config.CheckCacheSize))
+	}
+	if config.CacheShards < 0 {
+		result = multierror.Append(result,
+			fmt.Errorf("expect non-negative CacheShards, but get %v", config.CacheShards))
+	} else if config.CacheShards > 0 && config.CheckCacheSize <= 0 {
+		result = multierror.Append(result, errors.New("CacheShards requires CheckCacheSize"))
+	} else if config.CacheShards > config.CheckCacheSize {
+		result = multierror.Append(result, fmt.Errorf("CacheShards %d must be at most CheckCacheSize %d",
+			config.CacheShards, config.CheckCacheSize))
+	}
+
+	if config.ConsumerMetricsMaxConsumers < 0 {
+		result = multierror.Append(result, fmt.Errorf(
+			"expect non-negative ConsumerMetricsMaxConsumers, but get %v", config.ConsumerMetricsMaxConsumers))
+	}
+
+	if config.AdaptiveFailOpen != nil {
+		result = multierror.Append(result, validateAdaptiveFailOpen(config.AdaptiveFailOpen))
+	}
+
+	for _, code := range config.TransientCheckErrors {
+		if code == "" {
+			result = multierror.Append(result, errors.New("TransientCheckErrors contains empty code"))
+		}
+	}
+
+	for _, code := range config.AdvisoryCheckErrors {
+		if code == "" {
+			result = multierror.Append(result, errors.New("AdvisoryCheckErrors contains empty code"))
+		} else if isDenialCheckError(code) {
+			result = multierror.Append(result, fmt.Errorf("AdvisoryCheckErrors contains %s, which denies consumers", code))
+		}
+	}
+
+	if config.QuotaCoalescingWindow != nil {
+		window, err := pbtypes.DurationFromProto(config.QuotaCoalescingWindow)
+		if err != nil {
+			result = multierror.Append(result, err)
+		} else if window <= 0 || window > maxQuotaCoalescingWindow {
+			result = multierror.Append(result, fmt.Errorf(
+				"expect QuotaCoalescingWindow in (0, %v], but get %v", maxQuotaCoalescingWindow, window))
+		}
+		if config.UseDedupIdAsOperationId {
+			result = multierror.Append(result,
+				errors.New("QuotaCoalescingWindow can't be used with UseDedupIdAsOperationId"))
+		}
+	}
+
+	if config.CloseGracePeriod != nil {
+		period, err := pbtypes.DurationFromProto(config.CloseGracePeriod)
+		if err != nil {
+			result = multierror.Append(result, err)
+		} else if period <= 0 {
+			result = multierror.Append(result, fmt.Errorf("expect positive CloseGracePeriod, but get %v", period))
+		}
+	}
+
+	if config.Endpoints != nil {
+		result = multierror.Append(result, validateEndpoints(config.Endpoints))
+	}
+
+	if config.CredentialReloadInterval != nil {
+		interval, err := pbtypes.DurationFromProto(config.CredentialReloadInterval)
+		if err != nil {
+			result = multierror.Append(result, err)
+		} else if interval <= 0 {
+			result = multierror.Append(
+				result, fmt.Errorf("expect positive CredentialReloadInterval, but get %v", interval))
+		}
+	}
+
+	if config.NegativeCheckResultExpiration != nil {
+		exp, err := pbtypes.DurationFromProto(config.NegativeCheckResultExpiration)
+		if err != nil {
+			result = multierror.Append(result, err)
+		} else if exp <= 0 {
+			result = multierror.Append(
+				result, fmt.Errorf("expect positive NegativeCheckResultExpiration, but get %v", exp))
+		}
+	}
+
+	if config.QuotaDeadline != nil {
+		deadline, err := pbtypes.DurationFromProto(config.QuotaDeadline)
+		if err != nil {
+			result = multierror.Append(result, err)
+		} else if deadline <= 0 || deadline > maxQuotaDeadline {
+			result = multierror.Append(
+				result, fmt.Errorf("expect QuotaDeadline in (0, %v], but get %v", maxQuotaDeadline, deadline))
+		}
+	}
+
+	if config.MetricTimeAlignment != nil {
+		alignment, err := pbtypes.DurationFromProto(config.MetricTimeAlignment)
+		if err != nil {
+			result = multierror.Append(result, err)
+		} else if alignment <= 0 || metricTimeAlignmentPeriod%alignment != 0 {
+			result = multierror.Append(result, fmt.Errorf(
+				"expect positive MetricTimeAlignment evenly dividing %v, but get %v", metricTimeAlignmentPeriod, alignment))
+		}
+	}
+
+	if config.OperationIdNamespace != "" && !config.UseDedupIdAsOperationId {
+		result = multierror.Append(result,
+			errors.New("OperationIdNamespace requires UseDedupIdAsOperationId"))
+	}
+
+	if throttling := config.AdaptiveReportThrottling; throttling != nil {
+		if throttling.BackoffFactor <= 0 || throttling.BackoffFactor >= 1 {
+			result = multierror.Append(result,
+				fmt.Errorf("expect BackoffFactor in (0, 1), but get %v", throttling.BackoffFactor))
+		}
+		if throttling.RecoveryStep <= 0 || throttling.RecoveryStep > 1 {
+			result = multierror.Append(result,
+				fmt.Errorf("expect RecoveryStep in (0, 1], but get %v", throttling.RecoveryStep))
+		}
+		for _, exemption := range throttling.Exemptions {
+			if exemption.OperationPattern == "" && exemption.Label == "" {
+				result = multierror.Append(result,
+					errors.New("throttling exemption must set OperationPattern or Label"))
+			}
+			if exemption.LabelValuePattern != "" && exemption.Label == "" {
+				result = multierror.Append(result,
+					errors.New("throttling exemption LabelValuePattern requires Label"))
+			}
+			if _, err := compileThrottlingExemption(exemption); err != nil {
+				result = multierror.Append(result, fmt.Errorf("invalid throttling exemption: %v", err))
+			}
+		}
+	}
+
+	if config.ReportFlushInterval != nil {
+		interval, err := pbtypes.DurationFromProto(config.ReportFlushInterval)
+		if err != nil {
+			result = multierror.Append(result, err)
+		} else if interval <= 0 {
+			result = multierror.Append(result,
+				fmt.Errorf("expect positive ReportFlushInterval, but get %v", interval))
+		}
+	}
+	if config.MaxReportBatchSize < 0 {
+		result = multierror.Append(result,
+			fmt.Errorf("expect non-negative MaxReportBatchSize, but get %v", config.MaxReportBatchSize))
+	} else if config.MaxReportBatchSize > 0 && config.ReportFlushInterval == nil {
+		result = multierror.Append(result, errors.New("MaxReportBatchSize requires ReportFlushInterval"))
+	}
+	if config.MaxReportBufferBytes < 0 {
+		result = multierror.Append(result,
+			fmt.Errorf("expect non-negative MaxReportBufferBytes, but get %v", config.MaxReportBufferBytes))
+	} else if config.MaxReportBufferBytes > 0 && config.ReportFlushInterval == nil {
+		result = multierror.Append(result, errors.New("MaxReportBufferBytes requires ReportFlushInterval"))
+	}
+	if config.ReportFlushWorkers < 0 {
+		result = multierror.Append(result,
+			fmt.Errorf("expect non-negative ReportFlushWorkers, but get %v", config.ReportFlushWorkers))
+	} else if config.ReportFlushWorkers > 0 && config.ReportFlushInterval == nil {
+		result = multierror.Append(result, errors.New("ReportFlushWorkers requires ReportFlushInterval"))
+	}
+
+	if config.RetryPolicy != nil {
+		result = multierror.Append(result, validateRetryPolicy(config.RetryPolicy))
+	}
+	if breaker := config.CircuitBreaker; breaker != nil {
+		if breaker.FailureThreshold <= 0 {
+			result = multierror.Append(result,
+				fmt.Errorf("expect positive FailureThreshold, but get %v", breaker.FailureThreshold))
+		}
+		if breaker.OpenDuration == nil {
+			result = multierror.Append(result, errors.New("CircuitBreaker.OpenDuration is nil"))
+		} else if duration, err := pbtypes.DurationFromProto(breaker.OpenDuration); err != nil {
+			result = multierror.Append(result, err)
+		} else if duration <= 0 {
+			result = multierror.Append(result, fmt.Errorf("expect positive OpenDuration, but get %v", duration))
+		}
+	}
+	result = multierror.Append(result, validateNetworkFailPolicy(config.NetworkFailPolicy))
+
 	if config.CheckResultExpiration == nil {
 		result = multierror.Append(result, errors.New("RuntimeConfig.CheckResultExpiration is nil"))
 		return result
@@ -96,17 +475,207 @@ func validateRuntimeConfig(config *config.RuntimeConfig) *multierror.Error {
 	return result
 }
 
+func validateEndpoints(endpoints *config.ServiceControlEndpoints) *multierror.Error {
+	var result *multierror.Error
+	for _, e := range []struct {
+		method, endpoint string
+	}{
+		{methodCheck, endpoints.Check},
+		{methodReport, endpoints.Report},
+		{methodQuota, endpoints.Quota},
+	} {
+		if e.endpoint == "" {
+			continue
+		}
+		u, err := url.Parse(e.endpoint)
+		if err != nil || u.Host == "" || (u.Scheme != "https" && (u.Scheme != "http" || !isLoopbackHost(u.Hostname()))) {
+			result = multierror.Append(result,
+				fmt.Errorf("%s endpoint must be an https URL, or an http URL of a loopback host, but get %q",
+					e.method, e.endpoint))
+		}
+	}
+	return result
+}
+
+// isLoopbackHost returns true if host is localhost or a loopback IP, which plaintext endpoints
+// are limited to since requests carry credentials.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func validateHeaderLabels(headerLabels *config.HeaderLabels) *multierror.Error {
+	var result *multierror.Error
+	if len(headerLabels.AllowedHeaders) == 0 {
+		result = multierror.Append(result, errors.New("HeaderLabels.AllowedHeaders must be non-empty"))
+	}
+	for _, header := range headerLabels.AllowedHeaders {
+		if header == "" {
+			result = multierror.Append(result, errors.New("HeaderLabels.AllowedHeaders contains empty header"))
+		}
+	}
+	for header, label := range headerLabels.Labels {
+		if label == "" || isKnownLabel(label) {
+			result = multierror.Append(result,
+				fmt.Errorf("header %s must be mapped to a non-empty label other than known labels, but get %q",
+					header, label))
+		}
+	}
+	return result
+}
+
+// validateConsumerProjects validates that consumer projects of services can be resolved, which
+// are read from cached check results.
+func validateConsumerProjects(cfg *config.Params) *multierror.Error {
+	var result *multierror.Error
+	if cfg.RuntimeConfig != nil && cfg.RuntimeConfig.CheckCacheSize > 0 {
+		return result
+	}
+	for _, setting := range cfg.ServiceConfigs {
+		if setting.QuotaConsumer == config.CONSUMER_PROJECT || setting.ReportConsumer == config.CONSUMER_PROJECT {
+			result = multierror.Append(result,
+				fmt.Errorf("CONSUMER_PROJECT consumer of service %s requires CheckCacheSize", setting.MeshServiceName))
+		}
+	}
+	return result
+}
+
 func validateGcpServiceSetting(settings []*config.GcpServiceSetting) *multierror.Error {
 	var result *multierror.Error
 	if settings == nil || len(settings) == 0 {
 		result = multierror.Append(result, errors.New("ServiceConfigs is nil or empty"))
 		return result
 	}
+	// Mesh services index service configs, but several of them may share a Google service.
+	meshServices := make(map[string]bool, len(settings))
 	for _, setting := range settings {
 		if setting.MeshServiceName == "" || setting.GoogleServiceName == "" {
 			result = multierror.Append(result,
 				errors.New("MeshServiceName and GoogleServiceName must be non-empty"))
 		}
+		if setting.MeshServiceName != "" && meshServices[setting.MeshServiceName] {
+			result = multierror.Append(result,
+				fmt.Errorf("duplicate MeshServiceName %s", setting.MeshServiceName))
+		}
+		meshServices[setting.MeshServiceName] = true
+		if err := validateServiceName(setting); err != nil {
+			result = multierror.Append(result, err)
+		}
+
+		// DEFAULT_CONSUMER identifies consumers by API key, so it doesn't conflict with API_KEY.
+		if consumerSource(setting.QuotaConsumer) != consumerSource(setting.ReportConsumer) &&
+			(setting.QuotaConsumer == config.DEFAULT_CONSUMER || setting.ReportConsumer == config.DEFAULT_CONSUMER) {
+			result = multierror.Append(result,
+				errors.New("QuotaConsumer and ReportConsumer must both be set when they differ"))
+		}
+
+		if setting.ConsumerAnonymization != nil && setting.ConsumerAnonymization.Salt == "" {
+			result = multierror.Append(result,
+				errors.New("ConsumerAnonymization.Salt must be non-empty"))
+		}
+
+		if limit := setting.ApiKeyRateLimit; limit != nil &&
+			(limit.RequestsPerSecond <= 0 || limit.Burst <= 0 || limit.MaxTrackedKeys <= 0) {
+			result = multierror.Append(result,
+				errors.New("ApiKeyRateLimit.RequestsPerSecond, Burst and MaxTrackedKeys must be positive"))
+		}
+
+		if setting.PeerIdentityAttribute != "" && !isValidAttributeName(setting.PeerIdentityAttribute) {
+			result = multierror.Append(result,
+				fmt.Errorf("PeerIdentityAttribute %s is not a valid attribute name", setting.PeerIdentityAttribute))
+		}
+		if (setting.PeerIdentityAttribute == "" || len(setting.PeerIdentityProjects) == 0) &&
+			(setting.QuotaConsumer == config.PEER_IDENTITY || setting.ReportConsumer == config.PEER_IDENTITY) {
+			result = multierror.Append(result, errors.New(
+				"PeerIdentityAttribute and PeerIdentityProjects must be set when consumer is identified by peer identity"))
+		}
+		for peerIdentity, projectID := range setting.PeerIdentityProjects {
+			if projectID == "" {
+				result = multierror.Append(result,
+					fmt.Errorf("project of peer identity %s must be non-empty", peerIdentity))
+			}
+		}
+
+		if _, found := config.GcpServiceSetting_ConsumerResolutionFailurePolicy_name[int32(
+			setting.ConsumerResolutionFailurePolicy)]; !found {
+			result = multierror.Append(result,
+				fmt.Errorf("unknown ConsumerResolutionFailurePolicy %v", setting.ConsumerResolutionFailurePolicy))
+		}
+
+		for instance, export := range setting.LogEntryExports {
+			if export.LogName == "" {
+				result = multierror.Append(result,
+					fmt.Errorf("LogName of log entry export %s must be non-empty", instance))
+			}
+		}
+
+		for _, transform := range setting.MetricTransforms {
+			if !isTransformableMetric(transform.MetricName) {
+				result = multierror.Append(result,
+					fmt.Errorf("metric %s of transform is not a supported metric with int64 or double values",
+						transform.MetricName))
+			}
+			if _, err := newMetricTransformer(transform); err != nil {
+				result = multierror.Append(result,
+					fmt.Errorf("invalid transform of metric %s: %v", transform.MetricName, err))
+			}
+		}
+
+		if setting.HeaderLabels != nil {
+			result = multierror.Append(result, validateHeaderLabels(setting.HeaderLabels))
+		}
+
+		for trailer, label := range setting.TrailerLabels {
+			if trailer == "" || label == "" || isKnownLabel(label) {
+				result = multierror.Append(result,
+					fmt.Errorf("trailer %q must be mapped to a non-empty label other than known labels, but get %q",
+						trailer, label))
+			}
+		}
+
+		if setting.SuppressReportAttribute != "" && !isValidAttributeName(setting.SuppressReportAttribute) {
+			result = multierror.Append(result,
+				fmt.Errorf("SuppressReportAttribute %s is not a valid attribute name", setting.SuppressReportAttribute))
+		}
+
+		if setting.SourceWorkloadAttribute != "" && !isValidAttributeName(setting.SourceWorkloadAttribute) {
+			result = multierror.Append(result,
+				fmt.Errorf("SourceWorkloadAttribute %s is not a valid attribute name", setting.SourceWorkloadAttribute))
+		}
+
+		if setting.DestinationWorkloadAttribute != "" && !isValidAttributeName(setting.DestinationWorkloadAttribute) {
+			result = multierror.Append(result,
+				fmt.Errorf("DestinationWorkloadAttribute %s is not a valid attribute name",
+					setting.DestinationWorkloadAttribute))
+		}
+
+		if setting.RequestStateAttribute != "" && !isValidAttributeName(setting.RequestStateAttribute) {
+			result = multierror.Append(result,
+				fmt.Errorf("RequestStateAttribute %s is not a valid attribute name", setting.RequestStateAttribute))
+		}
+
+		for _, label := range setting.RequiredLabels {
+			if !isKnownLabel(label) {
+				result = multierror.Append(result,
+					fmt.Errorf("required label %s is not a known label", label))
+			}
+		}
+
+		for _, pattern := range append(append([]string{}, setting.ConsumerAllowlist...), setting.ConsumerDenylist...) {
+			if _, err := compileConsumerPattern(pattern); err != nil {
+				result = multierror.Append(result,
+					fmt.Errorf("invalid consumer pattern %s in consumer allowlist or denylist: %v", pattern, err))
+			}
+		}
+
+		if len(setting.Quotas) > hardMaxQuotasPerService {
+			result = multierror.Append(result,
+				fmt.Errorf("service %s has %d quotas, exceeding the limit of %d",
+					setting.MeshServiceName, len(setting.Quotas), hardMaxQuotasPerService))
+		}
 
 		if setting.Quotas != nil {
 			for _, qCfg := range setting.Quotas {
@@ -125,6 +694,28 @@ func validateGcpServiceSetting(settings []*config.GcpServiceSetting) *multierror
 								`quota must have postive expiration, but get %v`, expiration))
 					}
 				}
+				result = multierror.Append(result, validateQuotaAllocation(qCfg))
+				for _, override := range qCfg.ExpirationOverrides {
+					if _, err := compileConsumerPattern(override.ConsumerPattern); err != nil {
+						result = multierror.Append(result,
+							fmt.Errorf("invalid consumer pattern %s in quota %s: %v",
+								override.ConsumerPattern, qCfg.Name, err))
+					}
+					if override.Expiration == nil {
+						result = multierror.Append(result,
+							fmt.Errorf("expiration override for %s is nil", override.ConsumerPattern))
+						continue
+					}
+					expiration, err := pbtypes.DurationFromProto(override.Expiration)
+					if err != nil {
+						result = multierror.Append(result, err)
+					} else if expiration <= 0 {
+						result = multierror.Append(
+							result, fmt.Errorf(
+								`expiration override for %s must be positive, but get %v`,
+								override.ConsumerPattern, expiration))
+					}
+				}
 			}
 		}
 	}
@@ -136,34 +727,174 @@ func (b *builder) Build(context context.Context, env adapter.Env) (adapter.Handl
 	var _ apikey.HandlerBuilder = (*builder)(nil)
 	var _ svcctrlreport.HandlerBuilder = (*builder)(nil)
 	var _ quota.HandlerBuilder = (*builder)(nil)
+	var _ logentry.HandlerBuilder = (*builder)(nil)
+
+	cfg := b.config
+	var serviceConfigsContent []byte
+	if b.config.ServiceConfigsPath != "" {
+		content, err := ioutil.ReadFile(b.config.ServiceConfigsPath)
+		if err != nil {
+			return nil, fmt.Errorf("fail to read service configs: %v", err)
+		}
+		settings, err := parseServiceConfigs(content, b.config)
+		if err != nil {
+			return nil, err
+		}
+		loaded := *b.config
+		loaded.ServiceConfigs = settings
+		cfg = &loaded
+		serviceConfigsContent = content
+	}
 
-	client, err := newClient(b.config.CredentialPath)
+	var credentialReloadInterval time.Duration
+	if cfg.RuntimeConfig.CredentialReloadInterval != nil {
+		credentialReloadInterval = toDuration(cfg.RuntimeConfig.CredentialReloadInterval)
+	}
+	ctx, err := initializeHandlerContext(env, cfg, nil)
 	if err != nil {
 		return nil, err
 	}
-
-	ctx, err := initializeHandlerContext(env, b.config, client)
+	client, serviceClients, err := newServiceClients(cfg,
+		func() (serviceControlClient, error) {
+			c, err := newDefaultClient(cfg, credentialReloadInterval, ctx.warningLogger)
+			if err != nil {
+				return nil, err
+			}
+			return newResilientClient(c, cfg.RuntimeConfig), nil
+		},
+		func(credentialPath string) (serviceControlClient, error) {
+			c, err := newClient(credentialPath, credentialReloadInterval, cfg.RuntimeConfig.Endpoints, ctx.warningLogger)
+			if err != nil {
+				return nil, err
+			}
+			return newResilientClient(c, cfg.RuntimeConfig), nil
+		})
 	if err != nil {
 		return nil, err
 	}
+	ctx.client = client
+	ctx.serviceClients = serviceClients
+	ctx.serviceConfigsContent = serviceConfigsContent
 	ctx.checkDataShape = b.checkDataShape
 	ctx.reportDataShape = b.reportDataShape
-	return newHandler(ctx)
+	h, err := newHandler(ctx)
+	if err != nil {
+		return nil, err
+	}
+	logEffectiveSettings(env, cfg)
+	return h, nil
+}
+
+// logEffectiveSettings logs a one-line summary of effective settings as key=value pairs.
+func logEffectiveSettings(env adapter.Env, cfg *config.Params) {
+	runtimeConfig := cfg.RuntimeConfig
+	failOpen := "off"
+	if f := runtimeConfig.AdaptiveFailOpen; f != nil {
+		failOpen = fmt.Sprintf("%v/%v", f.ErrorRateThreshold, toDuration(f.Window))
+	}
+	reportThrottling := "off"
+	if r := runtimeConfig.AdaptiveReportThrottling; r != nil {
+		reportThrottling = fmt.Sprintf("%v/%v", r.BackoffFactor, r.RecoveryStep)
+	}
+	negativeExpiration := "off"
+	if runtimeConfig.NegativeCheckResultExpiration != nil {
+		negativeExpiration = toDuration(runtimeConfig.NegativeCheckResultExpiration).String()
+	}
+	credentialReload := "off"
+	if runtimeConfig.CredentialReloadInterval != nil {
+		credentialReload = toDuration(runtimeConfig.CredentialReloadInterval).String()
+	}
+	reportBatching := "off"
+	if runtimeConfig.ReportFlushInterval != nil {
+		batchSize := int(runtimeConfig.MaxReportBatchSize)
+		if batchSize <= 0 {
+			batchSize = defaultMaxReportBatchSize
+		}
+		workers := int(runtimeConfig.ReportFlushWorkers)
+		if workers <= 0 {
+			workers = defaultReportFlushWorkers
+		}
+		reportBatching = fmt.Sprintf("%d/%v/%d", batchSize, toDuration(runtimeConfig.ReportFlushInterval), workers)
+	}
+	retryPolicy := "off"
+	if r := runtimeConfig.RetryPolicy; r != nil {
+		initialBackoff, maxBackoff, ratio := defaultInitialBackoff, defaultMaxBackoff, r.BudgetRatio
+		if r.InitialBackoff != nil {
+			initialBackoff = toDuration(r.InitialBackoff)
+		}
+		if r.MaxBackoff != nil {
+			maxBackoff = toDuration(r.MaxBackoff)
+		}
+		if ratio == 0 {
+			ratio = defaultBudgetRatio
+		}
+		retryPolicy = fmt.Sprintf("%d/%v/%v/%v", r.MaxAttempts, initialBackoff, maxBackoff, ratio)
+	}
+	circuitBreaker := "off"
+	if b := runtimeConfig.CircuitBreaker; b != nil {
+		circuitBreaker = fmt.Sprintf("%d/%v", b.FailureThreshold, toDuration(b.OpenDuration))
+	}
+	endpoints := &config.ServiceControlEndpoints{}
+	if runtimeConfig.Endpoints != nil {
+		endpoints = runtimeConfig.Endpoints
+	}
+	endpoint := func(e string) string {
+		if e == "" {
+			return "default"
+		}
+		return e
+	}
+	services := make([]string, 0, len(cfg.ServiceConfigs))
+	for _, setting := range cfg.ServiceConfigs {
+		services = append(services, setting.MeshServiceName+"=>"+setting.GoogleServiceName)
+	}
+
+	cacheShards := runtimeConfig.CacheShards
+	if cacheShards <= 0 {
+		cacheShards = 1
+	}
+	env.Logger().Infof("svcctrl effective settings: check_cache_size=%d cache_shards=%d check_result_expiration=%v "+
+		"negative_check_result_expiration=%s adaptive_fail_open=%s transient_check_errors=%s adaptive_report_throttling=%s "+
+		"report_batching=%s retry_policy=%s circuit_breaker=%s endpoints=check:%s,report:%s,quota:%s "+
+		"network_fail_policy=%v credential_mode=%v credential_reload_interval=%s services=%s",
+		runtimeConfig.CheckCacheSize, cacheShards, toDuration(runtimeConfig.CheckResultExpiration),
+		negativeExpiration, failOpen, strings.Join(runtimeConfig.TransientCheckErrors, ","), reportThrottling,
+		reportBatching, retryPolicy, circuitBreaker,
+		endpoint(endpoints.Check), endpoint(endpoints.Report), endpoint(endpoints.Quota),
+		runtimeConfig.NetworkFailPolicy, cfg.CredentialMode, credentialReload, strings.Join(services, ","))
 }
 
 func initializeHandlerContext(env adapter.Env, adapterCfg *config.Params,
 	client serviceControlClient) (*handlerContext, error) {
 
-	configIndex := make(map[string]*config.GcpServiceSetting, len(adapterCfg.ServiceConfigs))
+	services, err := newServiceIndex(adapterCfg.ServiceConfigs)
+	if err != nil {
+		return nil, err
+	}
+	warningLogger := newRateLimitedLogger(env.Logger(), adapterCfg.RuntimeConfig.WarningLogRate)
 	for _, cfg := range adapterCfg.ServiceConfigs {
-		configIndex[cfg.MeshServiceName] = cfg
+		if len(cfg.Quotas) > softMaxQuotasPerService {
+			warningLogger.Warningf("service %s has %d quotas, more than the recommended %d",
+				cfg.MeshServiceName, len(cfg.Quotas), softMaxQuotasPerService)
+		}
+	}
+
+	var reportFlushPool *reportFlushPool
+	if flushInterval := adapterCfg.RuntimeConfig.ReportFlushInterval; flushInterval != nil {
+		reportFlushPool = newReportFlushPool(toDuration(flushInterval), int(adapterCfg.RuntimeConfig.ReportFlushWorkers))
+		reportFlushPool.start(env)
 	}
 
 	return &handlerContext{
-		env:                env,
-		config:             adapterCfg,
-		serviceConfigIndex: configIndex,
-		client:             client,
+		env:             env,
+		config:          adapterCfg,
+		services:        services,
+		client:          client,
+		warningLogger:   warningLogger,
+		consumerMetrics: newConsumerMetrics(int(adapterCfg.RuntimeConfig.ConsumerMetricsMaxConsumers)),
+		checkCache: newCheckCache(int(adapterCfg.RuntimeConfig.CheckCacheSize),
+			int(adapterCfg.RuntimeConfig.CacheShards), adapterCfg.RuntimeConfig.NetworkFailPolicy == config.FAIL_OPEN),
+		reportFlushPool: reportFlushPool,
 	}, nil
 }
 
@@ -177,6 +908,7 @@ func GetInfo() adapter.Info {
 			apikey.TemplateName,
 			svcctrlreport.TemplateName,
 			quota.TemplateName,
+			logentry.TemplateName,
 		},
 		DefaultConfig: &config.Params{},
 		NewBuilder:    func() adapter.HandlerBuilder { return &builder{} },
@@ -15,7 +15,9 @@
 package svcctrl
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	pbtypes "github.com/gogo/protobuf/types"
@@ -24,6 +26,7 @@ import (
 	"istio.io/istio/mixer/adapter/svcctrl/template/svcctrlreport"
 	at "istio.io/istio/mixer/pkg/adapter/test"
 	"istio.io/istio/mixer/template/apikey"
+	"istio.io/istio/mixer/template/logentry"
 	"istio.io/istio/mixer/template/quota"
 )
 
@@ -38,9 +41,29 @@ func TestInitializeHandlerContext(t *testing.T) {
 		"service_a": adapterCfg.ServiceConfigs[0],
 		"service_b": adapterCfg.ServiceConfigs[1],
 	}
-	if !reflect.DeepEqual(expectedIdx, ctx.serviceConfigIndex) {
+	if !reflect.DeepEqual(expectedIdx, ctx.services.exact) || ctx.services.hasPatterns() {
 		t.Errorf("expect serviceConfigIndex :%v, but get %v",
-			expectedIdx, ctx.serviceConfigIndex)
+			expectedIdx, ctx.services.exact)
+	}
+}
+
+func TestInitializeHandlerContextManyQuotas(t *testing.T) {
+	adapterCfg := getTestAdapterConfig()
+	adapterCfg.ServiceConfigs[0].Quotas = getTestQuotas(softMaxQuotasPerService)
+	env := at.NewEnv(t)
+	if _, err := initializeHandlerContext(env, adapterCfg, &mockSvcctrlClient{}); err != nil {
+		t.Fatalf("initializeHandlerContext() failed with %v", err)
+	}
+	if logs := env.GetLogs(); len(logs) != 0 {
+		t.Errorf(`expect no warning at the soft limit, but get %v`, logs)
+	}
+
+	adapterCfg.ServiceConfigs[0].Quotas = getTestQuotas(softMaxQuotasPerService + 1)
+	if _, err := initializeHandlerContext(env, adapterCfg, &mockSvcctrlClient{}); err != nil {
+		t.Fatalf("initializeHandlerContext() failed with %v", err)
+	}
+	if logs := env.GetLogs(); len(logs) != 1 || !strings.Contains(logs[0], "service_a has 101 quotas") {
+		t.Errorf(`expect warning above the soft limit, but get %v`, logs)
 	}
 }
 
@@ -54,12 +77,447 @@ func TestConfigValidation(t *testing.T) {
 		}
 	}
 
+	{
+		b := getTestBuilder()
+		b.config.ServiceConfigs[0].Quotas = getTestQuotas(hardMaxQuotasPerService)
+		if err := b.Validate(); err != nil {
+			t.Errorf(`expect quotas at the hard limit to be valid, but get error %v`, err.Multi)
+		}
+	}
+
+	{
+		b := getTestBuilder()
+		b.config.RuntimeConfig.Endpoints = &config.ServiceControlEndpoints{
+			Check:  "https://servicecontrol.example.com/",
+			Report: "http://localhost:8080",
+			Quota:  "http://127.0.0.1:8080/",
+		}
+		if err := b.Validate(); err != nil {
+			t.Errorf(`expect https and loopback http endpoints to be valid, but get error %v`, err.Multi)
+		}
+	}
+
+	{
+		b := getTestBuilder()
+		b.config.ServiceConfigs = nil
+		b.config.RuntimeConfig.AllowEmptyServiceConfigs = true
+		if err := b.Validate(); err != nil {
+			t.Errorf(`expect allowed empty ServiceConfigs to be valid, but get error %v`, err.Multi)
+		}
+	}
+
+	{
+		b := getTestBuilder()
+		b.config.ServiceConfigs[0].QuotaConsumer = config.API_KEY
+		if err := b.Validate(); err != nil {
+			t.Errorf(`expect API_KEY and DEFAULT_CONSUMER not to conflict, but get error %v`, err.Multi)
+		}
+	}
+
+	{
+		b := getTestBuilder()
+		b.config.ServiceConfigs[1].GoogleServiceName = b.config.ServiceConfigs[0].GoogleServiceName
+		if err := b.Validate(); err != nil {
+			t.Errorf(`expect mesh services sharing a Google service to be valid, but get error %v`, err.Multi)
+		}
+	}
+
+	{
+		b := getTestBuilder()
+		b.config.ServiceConfigs[1].MeshServiceName = b.config.ServiceConfigs[0].MeshServiceName
+		err := b.Validate()
+		if err == nil || !strings.Contains(err.Error(), "duplicate MeshServiceName service_a") {
+			t.Errorf(`expect error of duplicate MeshServiceName, but get %v`, err)
+		}
+	}
+
+	{
+		b := getTestBuilder()
+		b.config.CredentialMode = config.METADATA_SERVER
+		b.config.ServiceConfigs[1].CredentialPath = "/path/to/token.json"
+		if err := b.Validate(); err != nil {
+			t.Errorf(`expect service credential with metadata server credential to be valid, but get error %v`, err.Multi)
+		}
+	}
+
+	{
+		b := getTestBuilder()
+		b.config.RuntimeConfig.CheckCacheSize = 100
+		b.config.RuntimeConfig.CacheShards = 16
+		if err := b.Validate(); err != nil {
+			t.Errorf(`expect sharded check cache to be valid, but get error %v`, err.Multi)
+		}
+	}
+
+	{
+		b := getTestBuilder()
+		b.config.CredentialMode = config.METADATA_SERVER
+		b.config.RuntimeConfig.MetadataServerEndpoint = "http://127.0.0.1:8080/"
+		if err := b.Validate(); err != nil {
+			t.Errorf(`expect metadata server endpoint to be valid, but get error %v`, err.Multi)
+		}
+	}
+
+	{
+		b := getTestBuilder()
+		quota := b.config.ServiceConfigs[0].Quotas[0]
+		quota.AllocationMode = config.BEST_EFFORT_LOCAL
+		quota.PrefetchAmount = 50
+		quota.ReconcileInterval = &pbtypes.Duration{Seconds: 1}
+		if err := b.Validate(); err != nil {
+			t.Errorf(`expect local quota to be valid, but get error %v`, err.Multi)
+		}
+	}
+
+	{
+		b := getTestBuilder()
+		b.config.RuntimeConfig.RetryPolicy = &config.RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: &pbtypes.Duration{Nanos: 50000000},
+			MaxBackoff:     &pbtypes.Duration{Seconds: 2},
+			BudgetRatio:    0.2,
+		}
+		b.config.RuntimeConfig.CircuitBreaker = &config.CircuitBreaker{
+			FailureThreshold: 5,
+			OpenDuration:     &pbtypes.Duration{Seconds: 30},
+		}
+		b.config.RuntimeConfig.NetworkFailPolicy = config.FAIL_OPEN
+		if err := b.Validate(); err != nil {
+			t.Errorf(`expect retry policy and circuit breaker to be valid, but get error %v`, err.Multi)
+		}
+	}
+
+	{
+		b := getTestBuilder()
+		b.config.ServiceConfigs[1].MeshServiceName = "*.*.svc.cluster.local"
+		b.config.ServiceConfigs[1].GoogleServiceName = "{1}.{2}.googleapi.com"
+		if err := b.Validate(); err != nil {
+			t.Errorf(`expect mesh service pattern to be valid, but get error %v`, err.Multi)
+		}
+
+		b.config.ServiceConfigs = nil
+		b.config.ServiceConfigsPath = "/etc/svcctrl/services.json"
+		b.config.RuntimeConfig.ServiceConfigsReloadInterval = &pbtypes.Duration{Seconds: 30}
+		if err := b.Validate(); err != nil {
+			t.Errorf(`expect reloaded service configs file to be valid, but get error %v`, err.Multi)
+		}
+	}
+
 	invalidBuilders := []*builder{
+		func() *builder {
+			b := getTestBuilder()
+			b.config.CredentialMode = config.Params_CredentialMode(99)
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.CredentialMode = config.APPLICATION_DEFAULT
+			b.config.CredentialPath = "/path/to/token.json"
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.MetadataServerEndpoint = "http://127.0.0.1:8080"
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.CredentialMode = config.METADATA_SERVER
+			b.config.RuntimeConfig.MetadataServerEndpoint = "127.0.0.1:8080"
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.CredentialMode = config.METADATA_SERVER
+			b.config.RuntimeConfig.MetadataServerEndpoint = "http://127.0.0.1:8080/computeMetadata/v1"
+			return b
+		}(),
 		func() *builder {
 			b := getTestBuilder()
 			b.config.RuntimeConfig = nil
 			return b
 		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.WarningLogRate = -1
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.CheckCacheSize = -1
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.CacheShards = -1
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.CheckCacheSize = 0
+			b.config.RuntimeConfig.CacheShards = 4
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.CheckCacheSize = 4
+			b.config.RuntimeConfig.CacheShards = 8
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.MaxServiceProcessors = -1
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.ReportFlushInterval = &pbtypes.Duration{}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.ReportFlushInterval = &pbtypes.Duration{Seconds: 1}
+			b.config.RuntimeConfig.MaxReportBatchSize = -1
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.MaxReportBatchSize = 100
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.ReportFlushInterval = &pbtypes.Duration{Seconds: 1}
+			b.config.RuntimeConfig.ReportFlushWorkers = -1
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.ReportFlushWorkers = 8
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.ReportFlushInterval = &pbtypes.Duration{Seconds: 1}
+			b.config.RuntimeConfig.MaxReportBufferBytes = -1
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.MaxReportBufferBytes = 1 << 20
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.RetryPolicy = &config.RetryPolicy{}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.RetryPolicy = &config.RetryPolicy{MaxAttempts: maxRetryAttempts + 1}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.RetryPolicy = &config.RetryPolicy{MaxAttempts: 2, BudgetRatio: 1.5}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.RetryPolicy = &config.RetryPolicy{
+				MaxAttempts:    2,
+				InitialBackoff: &pbtypes.Duration{Seconds: 2},
+			}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.CircuitBreaker = &config.CircuitBreaker{
+				OpenDuration: &pbtypes.Duration{Seconds: 30},
+			}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.CircuitBreaker = &config.CircuitBreaker{FailureThreshold: 5}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.NetworkFailPolicy = config.RuntimeConfig_NetworkFailPolicy(99)
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.CredentialReloadInterval = &pbtypes.Duration{Seconds: -1}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.NegativeCheckResultExpiration = &pbtypes.Duration{}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.ConsumerMetricsMaxConsumers = -1
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.QuotaDeadline = &pbtypes.Duration{Seconds: 30}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.MetricTimeAlignment = &pbtypes.Duration{Seconds: 7}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].ConsumerDenylist = []string{"api_key:("}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.AdvisoryCheckErrors = []string{""}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.AdvisoryCheckErrors = []string{"SERVICE_STATUS_UNAVAILABLE", "API_KEY_INVALID"}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.AdvisoryCheckErrors = []string{"SERVICE_NOT_ACTIVATED"}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].TrailerLabels = map[string]string{"grpc-status": ""}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].TrailerLabels = map[string]string{"grpc-status": "/response_code"}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.Endpoints = &config.ServiceControlEndpoints{Report: "localhost:8080"}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.Endpoints = &config.ServiceControlEndpoints{Report: "http://aggregator.example.com/"}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.CloseGracePeriod = &pbtypes.Duration{}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].SourceWorkloadAttribute = "source workload"
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].DestinationWorkloadAttribute = "destination workload"
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.QuotaCoalescingWindow = &pbtypes.Duration{Seconds: 2}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.QuotaCoalescingWindow = &pbtypes.Duration{Nanos: 1000000}
+			b.config.RuntimeConfig.UseDedupIdAsOperationId = true
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].ConsumerResolutionFailurePolicy = 10
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].MetricTransforms = []*config.MetricTransform{{
+				MetricName:  "serviceruntime.googleapis.com/api/producer/backend_latencies",
+				Transformer: "scale",
+				Factor:      2,
+			}}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].MetricTransforms = []*config.MetricTransform{{
+				MetricName:  "serviceruntime.googleapis.com/api/producer/request_count",
+				Transformer: "bucket",
+			}}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.OperationIdNamespace = "istio-"
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.TransientCheckErrors = []string{"NAMESPACE_LOOKUP_UNAVAILABLE", ""}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.AdaptiveFailOpen = &config.AdaptiveFailOpen{
+				ErrorRateThreshold: 1.5,
+				Window:             &pbtypes.Duration{Seconds: 10},
+			}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.AdaptiveFailOpen = &config.AdaptiveFailOpen{
+				ErrorRateThreshold: 0.5,
+			}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.AdaptiveReportThrottling = &config.AdaptiveReportThrottling{
+				BackoffFactor: 1,
+				RecoveryStep:  0.1,
+			}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.AdaptiveReportThrottling = &config.AdaptiveReportThrottling{
+				BackoffFactor: 0.5,
+			}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.AdaptiveReportThrottling = &config.AdaptiveReportThrottling{
+				BackoffFactor: 0.5,
+				RecoveryStep:  0.1,
+				Exemptions:    []*config.ThrottlingExemption{{OperationPattern: "billing("}},
+			}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.AdaptiveReportThrottling = &config.AdaptiveReportThrottling{
+				BackoffFactor: 0.5,
+				RecoveryStep:  0.1,
+				Exemptions:    []*config.ThrottlingExemption{{LabelValuePattern: "5xx"}},
+			}
+			return b
+		}(),
 		func() *builder {
 			b := getTestBuilder()
 			b.config.ServiceConfigs = []*config.GcpServiceSetting{}
@@ -80,6 +538,11 @@ func TestConfigValidation(t *testing.T) {
 			b.config.ServiceConfigs[0].GoogleServiceName = ""
 			return b
 		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].Quotas = getTestQuotas(hardMaxQuotasPerService + 1)
+			return b
+		}(),
 		func() *builder {
 			b := getTestBuilder()
 			b.config.ServiceConfigs[0].Quotas[0].Name = ""
@@ -90,6 +553,114 @@ func TestConfigValidation(t *testing.T) {
 			b.config.ServiceConfigs[0].Quotas[0].Expiration = nil
 			return b
 		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].LogEntryExports = map[string]*config.LogEntryExport{
+				"accesslog.logentry.istio-system": {},
+			}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].Quotas[0].AllocationMode = config.Quota_AllocationMode(99)
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].Quotas[0].AllocationMode = config.BEST_EFFORT_LOCAL
+			b.config.ServiceConfigs[0].Quotas[0].PrefetchAmount = -1
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].Quotas[0].AllocationMode = config.BEST_EFFORT_LOCAL
+			b.config.ServiceConfigs[0].Quotas[0].ReconcileInterval = &pbtypes.Duration{}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].Quotas[0].PrefetchAmount = 50
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].Quotas[0].AllocationMode = config.BEST_EFFORT_LOCAL
+			b.config.RuntimeConfig.UseDedupIdAsOperationId = true
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].RequiredLabels = []string{"/consumer_id", "unknown_label"}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].ConsumerAnonymization = &config.ConsumerAnonymization{}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].SuppressReportAttribute = "synthetic probe"
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].RequestStateAttribute = "request state"
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].ApiKeyRateLimit = &config.ApiKeyRateLimit{
+				RequestsPerSecond: 10,
+				Burst:             10,
+			}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].PeerIdentityAttribute = "source user"
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].QuotaConsumer = config.PEER_IDENTITY
+			b.config.ServiceConfigs[0].ReportConsumer = config.PEER_IDENTITY
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].QuotaConsumer = config.PEER_IDENTITY
+			b.config.ServiceConfigs[0].ReportConsumer = config.PEER_IDENTITY
+			b.config.ServiceConfigs[0].PeerIdentityAttribute = "source.user"
+			b.config.ServiceConfigs[0].PeerIdentityProjects = map[string]string{"spiffe://client": ""}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].HeaderLabels = &config.HeaderLabels{
+				Labels: map[string]string{"user-agent": "/user_agent"},
+			}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].HeaderLabels = &config.HeaderLabels{
+				AllowedHeaders: []string{"user-agent"},
+				Labels:         map[string]string{"user-agent": "/consumer_id"},
+			}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].QuotaConsumer = config.CONSUMER_PROJECT
+			return b
+		}(),
+		func() *builder {
+			// service_b reports consumer projects, which are resolved from cached check results.
+			b := getTestBuilder()
+			b.config.RuntimeConfig.CheckCacheSize = 0
+			return b
+		}(),
 		func() *builder {
 			b := getTestBuilder()
 			expiration := b.config.ServiceConfigs[0].Quotas[0].Expiration
@@ -97,6 +668,48 @@ func TestConfigValidation(t *testing.T) {
 			expiration.Nanos = 0
 			return b
 		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].Quotas[0].ExpirationOverrides = []*config.Quota_ExpirationOverride{
+				{ConsumerPattern: "project_number:(", Expiration: &pbtypes.Duration{Seconds: 10}},
+			}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].Quotas[0].ExpirationOverrides = []*config.Quota_ExpirationOverride{
+				{ConsumerPattern: "project_number:.*", Expiration: &pbtypes.Duration{Seconds: -10}},
+			}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].MeshServiceName = "*.svc.cluster.local"
+			b.config.ServiceConfigs[0].GoogleServiceName = "{2}.googleapi.com"
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs[0].GoogleServiceName = "{1}.googleapi.com"
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigsPath = "/etc/svcctrl/services.json"
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.RuntimeConfig.ServiceConfigsReloadInterval = &pbtypes.Duration{Seconds: 30}
+			return b
+		}(),
+		func() *builder {
+			b := getTestBuilder()
+			b.config.ServiceConfigs = nil
+			b.config.ServiceConfigsPath = "/etc/svcctrl/services.json"
+			b.config.RuntimeConfig.ServiceConfigsReloadInterval = &pbtypes.Duration{}
+			return b
+		}(),
 	}
 
 	for _, b := range invalidBuilders {
@@ -107,12 +720,61 @@ func TestConfigValidation(t *testing.T) {
 	}
 }
 
+func TestLogEffectiveSettings(t *testing.T) {
+	adapterCfg := getTestAdapterConfig()
+	adapterCfg.RuntimeConfig.AdaptiveFailOpen = &config.AdaptiveFailOpen{
+		ErrorRateThreshold: 0.5,
+		Window:             &pbtypes.Duration{Seconds: 10},
+	}
+	adapterCfg.RuntimeConfig.TransientCheckErrors = []string{"NAMESPACE_LOOKUP_UNAVAILABLE"}
+	adapterCfg.RuntimeConfig.ReportFlushInterval = &pbtypes.Duration{Seconds: 1}
+	adapterCfg.RuntimeConfig.RetryPolicy = &config.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: &pbtypes.Duration{Nanos: 50000000},
+	}
+	adapterCfg.RuntimeConfig.CircuitBreaker = &config.CircuitBreaker{
+		FailureThreshold: 5,
+		OpenDuration:     &pbtypes.Duration{Seconds: 30},
+	}
+	adapterCfg.RuntimeConfig.Endpoints = &config.ServiceControlEndpoints{
+		Quota: "https://quota.example.com/",
+	}
+	env := at.NewEnv(t)
+	logEffectiveSettings(env, adapterCfg)
+
+	logs := env.GetLogs()
+	if len(logs) != 1 {
+		t.Fatalf(`expect one summary log, but get %v`, logs)
+	}
+	for _, expected := range []string{
+		"check_cache_size=10",
+		"cache_shards=1",
+		"check_result_expiration=10s",
+		"adaptive_fail_open=0.5/10s",
+		"transient_check_errors=NAMESPACE_LOOKUP_UNAVAILABLE",
+		"adaptive_report_throttling=off",
+		"report_batching=1000/1s/4",
+		"retry_policy=3/50ms/1s/0.1",
+		"circuit_breaker=5/30s",
+		"endpoints=check:default,report:default,quota:https://quota.example.com/",
+		"network_fail_policy=FAIL_CLOSED",
+		"credential_mode=JSON_KEY_FILE",
+		"credential_reload_interval=off",
+		"services=service_a=>service_a.googleapi.com,service_b=>service_b.googleapi.com",
+	} {
+		if !strings.Contains(logs[0], expected) {
+			t.Errorf(`expect %s in summary, but get %s`, expected, logs[0])
+		}
+	}
+}
+
 func TestGetInfo(t *testing.T) {
 	info := GetInfo()
 	expectedSupportedTemplate := []string{
 		apikey.TemplateName,
 		svcctrlreport.TemplateName,
 		quota.TemplateName,
+		logentry.TemplateName,
 	}
 	if !reflect.DeepEqual(expectedSupportedTemplate, info.SupportedTemplates) {
 		t.Errorf("expected supported templates: %v, but get %v",
@@ -143,13 +805,29 @@ func getTestAdapterConfig() *config.Params {
 				},
 			},
 			{
-				MeshServiceName:   "service_b",
-				GoogleServiceName: "service_b.googleapi.com",
+				MeshServiceName:       "service_b",
+				GoogleServiceName:     "service_b.googleapi.com",
+				RequiredLabels:        []string{"/consumer_id", "serviceruntime.googleapis.com/api_method"},
+				QuotaConsumer:         config.API_KEY,
+				ReportConsumer:        config.CONSUMER_PROJECT,
+				RequestStateAttribute: "request.state",
 			},
 		},
 	}
 }
 
+func getTestQuotas(count int) []*config.Quota {
+	quotas := make([]*config.Quota, 0, count)
+	for i := 0; i < count; i++ {
+		quotas = append(quotas, &config.Quota{
+			Name:                  fmt.Sprintf("quota-%d", i),
+			GoogleQuotaMetricName: fmt.Sprintf("metric-%d", i),
+			Expiration:            &pbtypes.Duration{Seconds: 10},
+		})
+	}
+	return quotas
+}
+
 func getTestBuilder() *builder {
 	return &builder{
 		config: getTestAdapterConfig(),
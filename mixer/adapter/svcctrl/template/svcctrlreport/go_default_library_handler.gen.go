@@ -53,6 +53,12 @@ const TemplateName = "svcctrlreport"
 //   response_code : response.code | 520
 //   response_bytes : response.size | 0
 //   response_latency : response.duration | "0ms"
+//   attributes:
+//     request.state: request.headers["x-request-state"] | "completed"
+//   request_headers:
+//     user-agent: request.headers["user-agent"] | ""
+//   response_trailers:
+//     grpc-status: response.headers["grpc-status"] | ""
 // ```
 type Instance struct {
 	// Name of the instance as specified in configuration.
@@ -83,6 +89,20 @@ type Instance struct {
 	ResponseBytes int64
 
 	ResponseLatency time.Duration
+
+	// Additional string attributes of the request keyed by name, which are read by
+	// adapter features configured with an attribute name.
+	Attributes map[string]string
+
+	// Request headers keyed by lowercase header name, which are reported as labels
+	// when allowed by the adapter config. Empty values are taken as missing headers and
+	// not reported, so that defaults like `| ""` don't report absent headers.
+	RequestHeaders map[string]string
+
+	// Response trailers keyed by lowercase trailer name, e.g. "grpc-status", which are
+	// reported as labels when mapped by the adapter config. Empty values are taken as
+	// missing trailers and not reported.
+	ResponseTrailers map[string]string
 }
 
 // HandlerBuilder must be implemented by adapters if they want to
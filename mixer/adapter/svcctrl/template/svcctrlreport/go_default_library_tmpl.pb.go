@@ -20,6 +20,7 @@ import _ "istio.io/api/mixer/v1/template"
 
 import strings "strings"
 import reflect "reflect"
+import github_com_gogo_protobuf_sortkeys "github.com/gogo/protobuf/sortkeys"
 
 import io "io"
 
@@ -59,6 +60,12 @@ const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 //   response_code : response.code | 520
 //   response_bytes : response.size | 0
 //   response_latency : response.duration | "0ms"
+//   attributes:
+//     request.state: request.headers["x-request-state"] | "completed"
+//   request_headers:
+//     user-agent: request.headers["user-agent"] | ""
+//   response_trailers:
+//     grpc-status: response.headers["grpc-status"] | ""
 // ```
 type Type struct {
 }
@@ -68,19 +75,22 @@ func (*Type) ProtoMessage()               {}
 func (*Type) Descriptor() ([]byte, []int) { return fileDescriptorGoDefaultLibraryTmpl, []int{0} }
 
 type InstanceParam struct {
-	ApiVersion      string `protobuf:"bytes,1,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
-	ApiOperation    string `protobuf:"bytes,2,opt,name=api_operation,json=apiOperation,proto3" json:"api_operation,omitempty"`
-	ApiProtocol     string `protobuf:"bytes,3,opt,name=api_protocol,json=apiProtocol,proto3" json:"api_protocol,omitempty"`
-	ApiService      string `protobuf:"bytes,4,opt,name=api_service,json=apiService,proto3" json:"api_service,omitempty"`
-	ApiKey          string `protobuf:"bytes,5,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
-	RequestTime     string `protobuf:"bytes,6,opt,name=request_time,json=requestTime,proto3" json:"request_time,omitempty"`
-	RequestMethod   string `protobuf:"bytes,7,opt,name=request_method,json=requestMethod,proto3" json:"request_method,omitempty"`
-	RequestPath     string `protobuf:"bytes,8,opt,name=request_path,json=requestPath,proto3" json:"request_path,omitempty"`
-	RequestBytes    string `protobuf:"bytes,9,opt,name=request_bytes,json=requestBytes,proto3" json:"request_bytes,omitempty"`
-	ResponseTime    string `protobuf:"bytes,10,opt,name=response_time,json=responseTime,proto3" json:"response_time,omitempty"`
-	ResponseCode    string `protobuf:"bytes,11,opt,name=response_code,json=responseCode,proto3" json:"response_code,omitempty"`
-	ResponseBytes   string `protobuf:"bytes,12,opt,name=response_bytes,json=responseBytes,proto3" json:"response_bytes,omitempty"`
-	ResponseLatency string `protobuf:"bytes,13,opt,name=response_latency,json=responseLatency,proto3" json:"response_latency,omitempty"`
+	ApiVersion       string            `protobuf:"bytes,1,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	ApiOperation     string            `protobuf:"bytes,2,opt,name=api_operation,json=apiOperation,proto3" json:"api_operation,omitempty"`
+	ApiProtocol      string            `protobuf:"bytes,3,opt,name=api_protocol,json=apiProtocol,proto3" json:"api_protocol,omitempty"`
+	ApiService       string            `protobuf:"bytes,4,opt,name=api_service,json=apiService,proto3" json:"api_service,omitempty"`
+	ApiKey           string            `protobuf:"bytes,5,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	RequestTime      string            `protobuf:"bytes,6,opt,name=request_time,json=requestTime,proto3" json:"request_time,omitempty"`
+	RequestMethod    string            `protobuf:"bytes,7,opt,name=request_method,json=requestMethod,proto3" json:"request_method,omitempty"`
+	RequestPath      string            `protobuf:"bytes,8,opt,name=request_path,json=requestPath,proto3" json:"request_path,omitempty"`
+	RequestBytes     string            `protobuf:"bytes,9,opt,name=request_bytes,json=requestBytes,proto3" json:"request_bytes,omitempty"`
+	ResponseTime     string            `protobuf:"bytes,10,opt,name=response_time,json=responseTime,proto3" json:"response_time,omitempty"`
+	ResponseCode     string            `protobuf:"bytes,11,opt,name=response_code,json=responseCode,proto3" json:"response_code,omitempty"`
+	ResponseBytes    string            `protobuf:"bytes,12,opt,name=response_bytes,json=responseBytes,proto3" json:"response_bytes,omitempty"`
+	ResponseLatency  string            `protobuf:"bytes,13,opt,name=response_latency,json=responseLatency,proto3" json:"response_latency,omitempty"`
+	Attributes       map[string]string `protobuf:"bytes,14,rep,name=attributes" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	RequestHeaders   map[string]string `protobuf:"bytes,15,rep,name=request_headers,json=requestHeaders" json:"request_headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ResponseTrailers map[string]string `protobuf:"bytes,16,rep,name=response_trailers,json=responseTrailers" json:"response_trailers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (m *InstanceParam) Reset()      { *m = InstanceParam{} }
@@ -180,6 +190,27 @@ func (m *InstanceParam) GetResponseLatency() string {
 	return ""
 }
 
+func (m *InstanceParam) GetAttributes() map[string]string {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+func (m *InstanceParam) GetRequestHeaders() map[string]string {
+	if m != nil {
+		return m.RequestHeaders
+	}
+	return nil
+}
+
+func (m *InstanceParam) GetResponseTrailers() map[string]string {
+	if m != nil {
+		return m.ResponseTrailers
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Type)(nil), "svcctrlreport.Type")
 	proto.RegisterType((*InstanceParam)(nil), "svcctrlreport.InstanceParam")
@@ -275,6 +306,30 @@ func (this *InstanceParam) Equal(that interface{}) bool {
 	if this.ResponseLatency != that1.ResponseLatency {
 		return false
 	}
+	if len(this.Attributes) != len(that1.Attributes) {
+		return false
+	}
+	for i := range this.Attributes {
+		if this.Attributes[i] != that1.Attributes[i] {
+			return false
+		}
+	}
+	if len(this.RequestHeaders) != len(that1.RequestHeaders) {
+		return false
+	}
+	for i := range this.RequestHeaders {
+		if this.RequestHeaders[i] != that1.RequestHeaders[i] {
+			return false
+		}
+	}
+	if len(this.ResponseTrailers) != len(that1.ResponseTrailers) {
+		return false
+	}
+	for i := range this.ResponseTrailers {
+		if this.ResponseTrailers[i] != that1.ResponseTrailers[i] {
+			return false
+		}
+	}
 	return true
 }
 func (this *Type) GoString() string {
@@ -290,7 +345,7 @@ func (this *InstanceParam) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 17)
+	s := make([]string, 0, 20)
 	s = append(s, "&svcctrlreport.InstanceParam{")
 	s = append(s, "ApiVersion: "+fmt.Sprintf("%#v", this.ApiVersion)+",\n")
 	s = append(s, "ApiOperation: "+fmt.Sprintf("%#v", this.ApiOperation)+",\n")
@@ -305,6 +360,45 @@ func (this *InstanceParam) GoString() string {
 	s = append(s, "ResponseCode: "+fmt.Sprintf("%#v", this.ResponseCode)+",\n")
 	s = append(s, "ResponseBytes: "+fmt.Sprintf("%#v", this.ResponseBytes)+",\n")
 	s = append(s, "ResponseLatency: "+fmt.Sprintf("%#v", this.ResponseLatency)+",\n")
+	keysForAttributes := make([]string, 0, len(this.Attributes))
+	for k, _ := range this.Attributes {
+		keysForAttributes = append(keysForAttributes, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForAttributes)
+	mapStringForAttributes := "map[string]string{"
+	for _, k := range keysForAttributes {
+		mapStringForAttributes += fmt.Sprintf("%#v: %#v,", k, this.Attributes[k])
+	}
+	mapStringForAttributes += "}"
+	if this.Attributes != nil {
+		s = append(s, "Attributes: "+mapStringForAttributes+",\n")
+	}
+	keysForRequestHeaders := make([]string, 0, len(this.RequestHeaders))
+	for k, _ := range this.RequestHeaders {
+		keysForRequestHeaders = append(keysForRequestHeaders, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForRequestHeaders)
+	mapStringForRequestHeaders := "map[string]string{"
+	for _, k := range keysForRequestHeaders {
+		mapStringForRequestHeaders += fmt.Sprintf("%#v: %#v,", k, this.RequestHeaders[k])
+	}
+	mapStringForRequestHeaders += "}"
+	if this.RequestHeaders != nil {
+		s = append(s, "RequestHeaders: "+mapStringForRequestHeaders+",\n")
+	}
+	keysForResponseTrailers := make([]string, 0, len(this.ResponseTrailers))
+	for k, _ := range this.ResponseTrailers {
+		keysForResponseTrailers = append(keysForResponseTrailers, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForResponseTrailers)
+	mapStringForResponseTrailers := "map[string]string{"
+	for _, k := range keysForResponseTrailers {
+		mapStringForResponseTrailers += fmt.Sprintf("%#v: %#v,", k, this.ResponseTrailers[k])
+	}
+	mapStringForResponseTrailers += "}"
+	if this.ResponseTrailers != nil {
+		s = append(s, "ResponseTrailers: "+mapStringForResponseTrailers+",\n")
+	}
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -427,6 +521,59 @@ func (m *InstanceParam) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintGoDefaultLibraryTmpl(dAtA, i, uint64(len(m.ResponseLatency)))
 		i += copy(dAtA[i:], m.ResponseLatency)
 	}
+	if len(m.Attributes) > 0 {
+		for k, _ := range m.Attributes {
+			dAtA[i] = 0x72
+			i++
+			v := m.Attributes[k]
+			mapSize := 1 + len(k) + sovGoDefaultLibraryTmpl(uint64(len(k))) + 1 + len(v) + sovGoDefaultLibraryTmpl(uint64(len(v)))
+			i = encodeVarintGoDefaultLibraryTmpl(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintGoDefaultLibraryTmpl(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintGoDefaultLibraryTmpl(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	if len(m.RequestHeaders) > 0 {
+		for k, _ := range m.RequestHeaders {
+			dAtA[i] = 0x7a
+			i++
+			v := m.RequestHeaders[k]
+			mapSize := 1 + len(k) + sovGoDefaultLibraryTmpl(uint64(len(k))) + 1 + len(v) + sovGoDefaultLibraryTmpl(uint64(len(v)))
+			i = encodeVarintGoDefaultLibraryTmpl(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintGoDefaultLibraryTmpl(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintGoDefaultLibraryTmpl(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	if len(m.ResponseTrailers) > 0 {
+		for k, _ := range m.ResponseTrailers {
+			dAtA[i] = 0x82
+			i++
+			dAtA[i] = 0x1
+			i++
+			v := m.ResponseTrailers[k]
+			mapSize := 1 + len(k) + sovGoDefaultLibraryTmpl(uint64(len(k))) + 1 + len(v) + sovGoDefaultLibraryTmpl(uint64(len(v)))
+			i = encodeVarintGoDefaultLibraryTmpl(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintGoDefaultLibraryTmpl(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintGoDefaultLibraryTmpl(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
 	return i, nil
 }
 
@@ -500,6 +647,30 @@ func (m *InstanceParam) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovGoDefaultLibraryTmpl(uint64(l))
 	}
+	if len(m.Attributes) > 0 {
+		for k, v := range m.Attributes {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovGoDefaultLibraryTmpl(uint64(len(k))) + 1 + len(v) + sovGoDefaultLibraryTmpl(uint64(len(v)))
+			n += mapEntrySize + 1 + sovGoDefaultLibraryTmpl(uint64(mapEntrySize))
+		}
+	}
+	if len(m.RequestHeaders) > 0 {
+		for k, v := range m.RequestHeaders {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovGoDefaultLibraryTmpl(uint64(len(k))) + 1 + len(v) + sovGoDefaultLibraryTmpl(uint64(len(v)))
+			n += mapEntrySize + 1 + sovGoDefaultLibraryTmpl(uint64(mapEntrySize))
+		}
+	}
+	if len(m.ResponseTrailers) > 0 {
+		for k, v := range m.ResponseTrailers {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovGoDefaultLibraryTmpl(uint64(len(k))) + 1 + len(v) + sovGoDefaultLibraryTmpl(uint64(len(v)))
+			n += mapEntrySize + 2 + sovGoDefaultLibraryTmpl(uint64(mapEntrySize))
+		}
+	}
 	return n
 }
 
@@ -529,6 +700,36 @@ func (this *InstanceParam) String() string {
 	if this == nil {
 		return "nil"
 	}
+	keysForAttributes := make([]string, 0, len(this.Attributes))
+	for k, _ := range this.Attributes {
+		keysForAttributes = append(keysForAttributes, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForAttributes)
+	mapStringForAttributes := "map[string]string{"
+	for _, k := range keysForAttributes {
+		mapStringForAttributes += fmt.Sprintf("%v: %v,", k, this.Attributes[k])
+	}
+	mapStringForAttributes += "}"
+	keysForRequestHeaders := make([]string, 0, len(this.RequestHeaders))
+	for k, _ := range this.RequestHeaders {
+		keysForRequestHeaders = append(keysForRequestHeaders, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForRequestHeaders)
+	mapStringForRequestHeaders := "map[string]string{"
+	for _, k := range keysForRequestHeaders {
+		mapStringForRequestHeaders += fmt.Sprintf("%v: %v,", k, this.RequestHeaders[k])
+	}
+	mapStringForRequestHeaders += "}"
+	keysForResponseTrailers := make([]string, 0, len(this.ResponseTrailers))
+	for k, _ := range this.ResponseTrailers {
+		keysForResponseTrailers = append(keysForResponseTrailers, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForResponseTrailers)
+	mapStringForResponseTrailers := "map[string]string{"
+	for _, k := range keysForResponseTrailers {
+		mapStringForResponseTrailers += fmt.Sprintf("%v: %v,", k, this.ResponseTrailers[k])
+	}
+	mapStringForResponseTrailers += "}"
 	s := strings.Join([]string{`&InstanceParam{`,
 		`ApiVersion:` + fmt.Sprintf("%v", this.ApiVersion) + `,`,
 		`ApiOperation:` + fmt.Sprintf("%v", this.ApiOperation) + `,`,
@@ -543,6 +744,9 @@ func (this *InstanceParam) String() string {
 		`ResponseCode:` + fmt.Sprintf("%v", this.ResponseCode) + `,`,
 		`ResponseBytes:` + fmt.Sprintf("%v", this.ResponseBytes) + `,`,
 		`ResponseLatency:` + fmt.Sprintf("%v", this.ResponseLatency) + `,`,
+		`Attributes:` + mapStringForAttributes + `,`,
+		`RequestHeaders:` + mapStringForRequestHeaders + `,`,
+		`ResponseTrailers:` + mapStringForResponseTrailers + `,`,
 		`}`,
 	}, "")
 	return s
@@ -1011,6 +1215,360 @@ func (m *InstanceParam) Unmarshal(dAtA []byte) error {
 			}
 			m.ResponseLatency = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGoDefaultLibraryTmpl
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGoDefaultLibraryTmpl
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Attributes == nil {
+				m.Attributes = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGoDefaultLibraryTmpl
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGoDefaultLibraryTmpl
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthGoDefaultLibraryTmpl
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGoDefaultLibraryTmpl
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthGoDefaultLibraryTmpl
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipGoDefaultLibraryTmpl(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthGoDefaultLibraryTmpl
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Attributes[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestHeaders", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGoDefaultLibraryTmpl
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGoDefaultLibraryTmpl
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RequestHeaders == nil {
+				m.RequestHeaders = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGoDefaultLibraryTmpl
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGoDefaultLibraryTmpl
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthGoDefaultLibraryTmpl
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGoDefaultLibraryTmpl
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthGoDefaultLibraryTmpl
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipGoDefaultLibraryTmpl(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthGoDefaultLibraryTmpl
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.RequestHeaders[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResponseTrailers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGoDefaultLibraryTmpl
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGoDefaultLibraryTmpl
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ResponseTrailers == nil {
+				m.ResponseTrailers = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGoDefaultLibraryTmpl
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGoDefaultLibraryTmpl
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthGoDefaultLibraryTmpl
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGoDefaultLibraryTmpl
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthGoDefaultLibraryTmpl
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipGoDefaultLibraryTmpl(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthGoDefaultLibraryTmpl
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.ResponseTrailers[mapkey] = mapvalue
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGoDefaultLibraryTmpl(dAtA[iNdEx:])
@@ -1142,33 +1700,42 @@ func init() {
 }
 
 var fileDescriptorGoDefaultLibraryTmpl = []byte{
-	// 444 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x91, 0xb1, 0x6e, 0xd4, 0x30,
-	0x1c, 0xc6, 0xcf, 0x6d, 0xb9, 0x52, 0xb7, 0x01, 0x94, 0x05, 0x8b, 0xc1, 0xc0, 0x21, 0x24, 0x18,
-	0x4a, 0x04, 0xbc, 0x41, 0x99, 0x10, 0x20, 0x4e, 0x50, 0xb1, 0x46, 0x4e, 0xf2, 0xbf, 0x9e, 0x85,
-	0x13, 0x1b, 0xfb, 0x7f, 0xa7, 0x86, 0x89, 0x47, 0x40, 0xe2, 0x25, 0x78, 0x14, 0xc6, 0x8a, 0x89,
-	0xb1, 0x17, 0x18, 0x18, 0x3b, 0x32, 0x22, 0xdb, 0x49, 0x7b, 0x37, 0xe6, 0x97, 0x9f, 0xbf, 0xef,
-	0xb3, 0x4c, 0xeb, 0x42, 0x7c, 0x06, 0x75, 0xa8, 0x17, 0x98, 0x29, 0x5d, 0x0a, 0x75, 0x38, 0x13,
-	0x0e, 0x8b, 0x85, 0x54, 0x55, 0x76, 0x02, 0xcd, 0x4c, 0x2a, 0x70, 0x59, 0x2d, 0x4f, 0xc1, 0x66,
-	0xa2, 0x12, 0x06, 0xc1, 0x66, 0x6e, 0x59, 0x96, 0x68, 0x55, 0x86, 0x50, 0x1b, 0x25, 0x10, 0x06,
-	0x60, 0xc1, 0x68, 0x8b, 0xd9, 0x89, 0xce, 0x2b, 0x98, 0x89, 0x85, 0xc2, 0x5c, 0xc9, 0xc2, 0x0a,
-	0xdb, 0xe6, 0x58, 0x1b, 0xf5, 0xc4, 0x58, 0x8d, 0x3a, 0x4d, 0x36, 0xe4, 0x3b, 0x93, 0x18, 0xbd,
-	0x7c, 0x7a, 0x95, 0x06, 0xa7, 0x08, 0x8d, 0x93, 0xba, 0x71, 0xf1, 0xc8, 0x64, 0x4c, 0x77, 0x8e,
-	0x5b, 0x03, 0x93, 0xf3, 0x6d, 0x9a, 0xbc, 0x6c, 0x1c, 0x8a, 0xa6, 0x84, 0xa9, 0xb0, 0xa2, 0x4e,
-	0xef, 0xd2, 0x7d, 0x61, 0x64, 0xbe, 0x04, 0xeb, 0x7d, 0x46, 0xee, 0x91, 0x47, 0x7b, 0xef, 0xa8,
-	0x30, 0xf2, 0x43, 0x24, 0xe9, 0x03, 0x9a, 0x78, 0x41, 0x1b, 0xb0, 0x02, 0xbd, 0xb2, 0x15, 0x94,
-	0x03, 0x61, 0xe4, 0xdb, 0x81, 0xa5, 0xf7, 0xa9, 0xff, 0xce, 0x43, 0x59, 0xa9, 0x15, 0xdb, 0x0e,
-	0x8e, 0x4f, 0x9e, 0xf6, 0x68, 0x28, 0x72, 0x60, 0x97, 0xb2, 0x04, 0xb6, 0x73, 0x59, 0xf4, 0x3e,
-	0x92, 0xf4, 0x36, 0xdd, 0xf5, 0xc2, 0x47, 0x68, 0xd9, 0xb5, 0xf0, 0x73, 0x2c, 0x8c, 0x7c, 0x05,
-	0xad, 0x0f, 0xb7, 0xf0, 0x69, 0x01, 0x0e, 0x73, 0x94, 0x35, 0xb0, 0x71, 0x0c, 0xef, 0xd9, 0xb1,
-	0xac, 0x21, 0x7d, 0x48, 0x6f, 0x0c, 0x4a, 0x0d, 0x38, 0xd7, 0x15, 0xdb, 0x0d, 0x52, 0xd2, 0xd3,
-	0x37, 0x01, 0xae, 0x27, 0x19, 0x81, 0x73, 0x76, 0x7d, 0x23, 0x69, 0x2a, 0x70, 0xee, 0xaf, 0x3b,
-	0x28, 0x45, 0x8b, 0xe0, 0xd8, 0x5e, 0xbc, 0x6e, 0x0f, 0x8f, 0x3c, 0x8b, 0x92, 0x33, 0xba, 0x71,
-	0x10, 0x27, 0xd1, 0x41, 0x8a, 0x30, 0x6c, 0x5a, 0x97, 0x4a, 0x5d, 0x01, 0xdb, 0xdf, 0x94, 0x5e,
-	0xe8, 0xaa, 0x1f, 0xde, 0x4b, 0xb1, 0xef, 0x60, 0x18, 0x1e, 0x69, 0x2c, 0x7c, 0x4c, 0x6f, 0x5d,
-	0x6a, 0xfe, 0x85, 0x9b, 0xb2, 0x65, 0x49, 0x10, 0x6f, 0x0e, 0xfc, 0x75, 0xc4, 0x47, 0xcf, 0xce,
-	0x56, 0x7c, 0xf4, 0x6b, 0xc5, 0x47, 0x17, 0x2b, 0x4e, 0xbe, 0x74, 0x9c, 0x7c, 0xef, 0x38, 0xf9,
-	0xd1, 0x71, 0x72, 0xd6, 0x71, 0x72, 0xde, 0x71, 0xf2, 0xb7, 0xe3, 0xa3, 0x8b, 0x8e, 0x93, 0xaf,
-	0xbf, 0xf9, 0xe8, 0xdf, 0xcf, 0x3f, 0xdf, 0xb6, 0x48, 0x31, 0x0e, 0x0f, 0xf7, 0xfc, 0x7f, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0xe5, 0x6b, 0x7d, 0xba, 0xc9, 0x02, 0x00, 0x00,
+	// 590 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x93, 0xbf, 0x6f, 0xd3, 0x40,
+	0x14, 0xc7, 0xe3, 0xfe, 0x48, 0xe9, 0xb5, 0x69, 0x82, 0x01, 0x61, 0x75, 0x30, 0xa5, 0x08, 0xa9,
+	0x48, 0x34, 0x86, 0xb0, 0x20, 0x24, 0x86, 0xb6, 0x42, 0x02, 0x51, 0x44, 0x14, 0x2a, 0x24, 0x26,
+	0xeb, 0x6c, 0xbf, 0x34, 0x27, 0xce, 0xbe, 0xe3, 0xee, 0x1c, 0xd5, 0x4c, 0xfc, 0x09, 0x48, 0xfc,
+	0x13, 0xac, 0xfc, 0x17, 0x8c, 0x15, 0x13, 0x23, 0x31, 0x0c, 0x8c, 0x1d, 0x19, 0xd1, 0xf9, 0xec,
+	0x34, 0xae, 0x2a, 0x50, 0xb7, 0xdc, 0x37, 0x9f, 0xf7, 0x79, 0xef, 0x2e, 0x2f, 0x28, 0x0e, 0xf0,
+	0x7b, 0xa0, 0xdb, 0x2c, 0x55, 0x1e, 0x65, 0x21, 0xa6, 0xdb, 0x43, 0x2c, 0x55, 0x90, 0x12, 0x1a,
+	0x79, 0x87, 0x90, 0x0c, 0x09, 0x05, 0xe9, 0xc5, 0xe4, 0x08, 0x84, 0x87, 0x23, 0xcc, 0x15, 0x08,
+	0x4f, 0x8e, 0xc3, 0x50, 0x09, 0xea, 0x29, 0x88, 0x39, 0xc5, 0x0a, 0xaa, 0x40, 0x00, 0x67, 0x42,
+	0x79, 0x87, 0xcc, 0x8f, 0x60, 0x88, 0x53, 0xaa, 0x7c, 0x4a, 0x02, 0x81, 0x45, 0xe6, 0xab, 0x98,
+	0xd3, 0x2e, 0x17, 0x4c, 0x31, 0xbb, 0x55, 0x83, 0xd7, 0x37, 0x8d, 0x7a, 0x7c, 0xff, 0xd4, 0x06,
+	0x47, 0x0a, 0x12, 0x49, 0x58, 0x22, 0x4d, 0xc9, 0x66, 0x13, 0x2d, 0x1c, 0x64, 0x1c, 0x36, 0xbf,
+	0x2c, 0xa1, 0xd6, 0xb3, 0x44, 0x2a, 0x9c, 0x84, 0xd0, 0xc7, 0x02, 0xc7, 0xf6, 0x0d, 0xb4, 0x82,
+	0x39, 0xf1, 0xc7, 0x20, 0x34, 0xef, 0x58, 0x1b, 0xd6, 0xd6, 0xf2, 0x00, 0x61, 0x4e, 0x5e, 0x9b,
+	0xc4, 0xbe, 0x85, 0x5a, 0x1a, 0x60, 0x1c, 0x04, 0x56, 0x1a, 0x99, 0x2b, 0x90, 0x55, 0xcc, 0xc9,
+	0xcb, 0x2a, 0xb3, 0x6f, 0x22, 0x7d, 0xf6, 0x8b, 0x66, 0x21, 0xa3, 0xce, 0x7c, 0xc1, 0x68, 0x73,
+	0xbf, 0x8c, 0xaa, 0x46, 0x12, 0xc4, 0x98, 0x84, 0xe0, 0x2c, 0x4c, 0x1b, 0xbd, 0x32, 0x89, 0x7d,
+	0x1d, 0x2d, 0x69, 0xe0, 0x2d, 0x64, 0xce, 0x62, 0xf1, 0x65, 0x13, 0x73, 0xf2, 0x1c, 0x32, 0x2d,
+	0x17, 0xf0, 0x2e, 0x05, 0xa9, 0x7c, 0x45, 0x62, 0x70, 0x9a, 0x46, 0x5e, 0x66, 0x07, 0x24, 0x06,
+	0xfb, 0x36, 0x5a, 0xab, 0x90, 0x18, 0xd4, 0x88, 0x45, 0xce, 0x52, 0x01, 0xb5, 0xca, 0xf4, 0x45,
+	0x11, 0xce, 0x9a, 0x38, 0x56, 0x23, 0xe7, 0x52, 0xcd, 0xd4, 0xc7, 0x6a, 0xa4, 0xaf, 0x5b, 0x21,
+	0x41, 0xa6, 0x40, 0x3a, 0xcb, 0xe6, 0xba, 0x65, 0xb8, 0xab, 0x33, 0x03, 0x49, 0xce, 0x12, 0x09,
+	0x66, 0x24, 0x54, 0x41, 0x26, 0x2c, 0x66, 0x9a, 0x85, 0x42, 0x16, 0x81, 0xb3, 0x52, 0x87, 0xf6,
+	0x58, 0x54, 0x0e, 0x5e, 0x42, 0xa6, 0xdf, 0x6a, 0x35, 0xb8, 0x49, 0x4d, 0xc3, 0x3b, 0xa8, 0x33,
+	0xc5, 0xf4, 0x2f, 0x9c, 0x84, 0x99, 0xd3, 0x2a, 0xc0, 0x76, 0x95, 0xef, 0x9b, 0xd8, 0xde, 0x47,
+	0x08, 0x2b, 0x25, 0x48, 0x90, 0x6a, 0xdb, 0xda, 0xc6, 0xfc, 0xd6, 0x4a, 0xef, 0x6e, 0xb7, 0xb6,
+	0x32, 0xdd, 0xda, 0x0a, 0x74, 0x77, 0xa6, 0xf8, 0x93, 0x44, 0x89, 0x6c, 0x30, 0x53, 0x6f, 0xbf,
+	0x41, 0xed, 0xea, 0x39, 0x46, 0x80, 0x23, 0x10, 0xd2, 0x69, 0x17, 0xca, 0x7b, 0xff, 0x54, 0x0e,
+	0x4c, 0xcd, 0x53, 0x53, 0x62, 0xb4, 0x6b, 0xa2, 0x16, 0xda, 0x3e, 0xba, 0x7c, 0xfa, 0x88, 0x02,
+	0x13, 0xaa, 0xe5, 0x9d, 0x42, 0xde, 0xfb, 0x8f, 0xbc, 0x7c, 0xe5, 0xb2, 0xc8, 0xe8, 0x3b, 0xe2,
+	0x4c, 0xbc, 0xfe, 0x18, 0xb5, 0xcf, 0x5c, 0xcd, 0xee, 0xa0, 0x79, 0xbd, 0x5f, 0x66, 0xcb, 0xf5,
+	0x47, 0xfb, 0x2a, 0x5a, 0x1c, 0x63, 0x9a, 0x42, 0xb9, 0xd6, 0xe6, 0xf0, 0x68, 0xee, 0xa1, 0xb5,
+	0xbe, 0x83, 0xae, 0x9c, 0x73, 0x8d, 0x0b, 0x29, 0xf6, 0xd0, 0xb5, 0x73, 0x87, 0xbd, 0x88, 0x64,
+	0xb7, 0x77, 0x3c, 0x71, 0x1b, 0xdf, 0x27, 0x6e, 0xe3, 0x64, 0xe2, 0x5a, 0x1f, 0x72, 0xd7, 0xfa,
+	0x9c, 0xbb, 0xd6, 0xd7, 0xdc, 0xb5, 0x8e, 0x73, 0xd7, 0xfa, 0x91, 0xbb, 0xd6, 0xef, 0xdc, 0x6d,
+	0x9c, 0xe4, 0xae, 0xf5, 0xf1, 0xa7, 0xdb, 0xf8, 0xf3, 0xed, 0xd7, 0xa7, 0x39, 0x2b, 0x68, 0x16,
+	0xff, 0xc4, 0x07, 0x7f, 0x07, 0x00, 0x57, 0x56, 0x04, 0x26, 0x9a, 0x04, 0x00, 0x00,
 }
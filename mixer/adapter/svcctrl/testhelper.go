@@ -15,10 +15,13 @@
 package svcctrl
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"reflect"
 
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	sc "google.golang.org/api/servicecontrol/v1"
 )
 
@@ -26,16 +29,24 @@ type mockSvcctrlClient struct {
 	serviceName           string
 	checkRequest          *sc.CheckRequest
 	checkResponse         *sc.CheckResponse
+	checkError            error
 	reportRequest         *sc.ReportRequest
 	reportResponse        *sc.ReportResponse
+	reportError           error
 	allocateQuotaRequest  *sc.AllocateQuotaRequest
 	allocateQuotaResponse *sc.AllocateQuotaResponse
-	done                  chan struct{}
+	allocateQuotaError    error
+	// AllocateQuota blocks until allocateQuotaBlock is closed or its context is done if it's set.
+	allocateQuotaBlock chan struct{}
+	done               chan struct{}
 }
 
 func (c *mockSvcctrlClient) Check(serviceName string, request *sc.CheckRequest) (*sc.CheckResponse, error) {
 	c.serviceName = serviceName
 	c.checkRequest = request
+	if c.checkError != nil {
+		return nil, c.checkError
+	}
 	if c.checkResponse != nil {
 		return c.checkResponse, nil
 	}
@@ -45,16 +56,29 @@ func (c *mockSvcctrlClient) Check(serviceName string, request *sc.CheckRequest)
 func (c *mockSvcctrlClient) Report(serviceName string, request *sc.ReportRequest) (*sc.ReportResponse, error) {
 	c.serviceName = serviceName
 	c.reportRequest = request
+	if c.reportError != nil {
+		return nil, c.reportError
+	}
 	if c.reportResponse != nil {
 		return c.reportResponse, nil
 	}
 	return nil, errors.New("injected error")
 }
 
-func (c *mockSvcctrlClient) AllocateQuota(serviceName string,
+func (c *mockSvcctrlClient) AllocateQuota(ctx context.Context, serviceName string,
 	request *sc.AllocateQuotaRequest) (*sc.AllocateQuotaResponse, error) {
 	c.serviceName = serviceName
 	c.allocateQuotaRequest = request
+	if c.allocateQuotaBlock != nil {
+		select {
+		case <-c.allocateQuotaBlock:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if c.allocateQuotaError != nil {
+		return nil, c.allocateQuotaError
+	}
 	if c.allocateQuotaResponse != nil {
 		return c.allocateQuotaResponse, nil
 	}
@@ -102,3 +126,35 @@ func compareJSON(s1, s2 string) bool {
 
 	return reflect.DeepEqual(o1, o2)
 }
+
+func getHistogramSampleCount(histogram *prometheus.HistogramVec, labels ...string) uint64 {
+	m := &dto.Metric{}
+	if err := histogram.WithLabelValues(labels...).(prometheus.Histogram).Write(m); err != nil {
+		return 0
+	}
+	return m.Histogram.GetSampleCount()
+}
+
+func getHistogramSampleSum(histogram *prometheus.HistogramVec, labels ...string) float64 {
+	m := &dto.Metric{}
+	if err := histogram.WithLabelValues(labels...).(prometheus.Histogram).Write(m); err != nil {
+		return 0
+	}
+	return m.Histogram.GetSampleSum()
+}
+
+func getCounterValue(counter *prometheus.CounterVec, labels ...string) float64 {
+	m := &dto.Metric{}
+	if err := counter.WithLabelValues(labels...).Write(m); err != nil {
+		return 0
+	}
+	return m.Counter.GetValue()
+}
+
+func getGaugeValue(gauge *prometheus.GaugeVec, labels ...string) float64 {
+	m := &dto.Metric{}
+	if err := gauge.WithLabelValues(labels...).Write(m); err != nil {
+		return 0
+	}
+	return m.Gauge.GetValue()
+}
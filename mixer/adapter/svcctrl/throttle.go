@@ -0,0 +1,183 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"math/rand"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	rpc "github.com/googleapis/googleapis/google/rpc"
+	"google.golang.org/api/googleapi"
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+)
+
+const (
+	// Some report calls are always sent while throttled, so that recovery can be detected.
+	minReportSendFraction = 0.01
+
+	// Maximum number of operations of report calls not sent while throttled, which are
+	// deferred to later report calls. Operations beyond it are dropped.
+	maxDeferredReportOperations = 10000
+)
+
+// throttlingExemption is a compiled config.ThrottlingExemption, nil patterns match anything.
+type throttlingExemption struct {
+	operation  *regexp.Regexp
+	label      string
+	labelValue *regexp.Regexp
+}
+
+// matches returns true if the operation is exempted.
+func (e *throttlingExemption) matches(op *sc.Operation) bool {
+	if e.operation != nil && !e.operation.MatchString(op.OperationName) {
+		return false
+	}
+	if e.label == "" {
+		return true
+	}
+	value, found := op.Labels[e.label]
+	return found && (e.labelValue == nil || e.labelValue.MatchString(value))
+}
+
+// reportThrottle adapts the fraction of report calls sent to Google ServiceControl to
+// throttling, multiplicative decrease on throttled calls and additive increase on successful calls.
+// Operations of report calls not sent are deferred to later report calls.
+type reportThrottle struct {
+	backoffFactor float64
+	recoveryStep  float64
+	rand          func() float64
+	exemptions    []throttlingExemption
+
+	lock         sync.Mutex // guards sendFraction and deferred
+	sendFraction float64
+	deferred     []*sc.Operation
+}
+
+// allow returns true if a report call should be sent.
+func (t *reportThrottle) allow() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.sendFraction >= 1 || t.rand() < t.sendFraction
+}
+
+// record adjusts the fraction of report calls sent from the error of a report call, returns
+// true if the call is throttled.
+func (t *reportThrottle) record(err error) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	throttled := isThrottled(err)
+	if throttled {
+		t.sendFraction *= t.backoffFactor
+		if t.sendFraction < minReportSendFraction {
+			t.sendFraction = minReportSendFraction
+		}
+	} else if err == nil {
+		t.sendFraction += t.recoveryStep
+		if t.sendFraction > 1 {
+			t.sendFraction = 1
+		}
+	}
+	return throttled
+}
+
+// deferOperations keeps operations for later report calls, returns the number of operations
+// dropped since maxDeferredReportOperations are already deferred.
+func (t *reportThrottle) deferOperations(operations []*sc.Operation) int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	dropped := 0
+	if room := maxDeferredReportOperations - len(t.deferred); len(operations) > room {
+		dropped = len(operations) - room
+		operations = operations[:room]
+	}
+	t.deferred = append(t.deferred, operations...)
+	return dropped
+}
+
+// takeDeferred returns up to max deferred operations, oldest first, and stops deferring them.
+func (t *reportThrottle) takeDeferred(max int) []*sc.Operation {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if len(t.deferred) < max {
+		max = len(t.deferred)
+	}
+	operations := append([]*sc.Operation(nil), t.deferred[:max]...)
+	t.deferred = append(t.deferred[:0], t.deferred[max:]...)
+	return operations
+}
+
+// isExempt returns true if an operation should be reported even while throttled.
+func (t *reportThrottle) isExempt(op *sc.Operation) bool {
+	for i := range t.exemptions {
+		if t.exemptions[i].matches(op) {
+			return true
+		}
+	}
+	return false
+}
+
+// isThrottled returns true if a report call is rejected as a whole by Google ServiceControl API
+// rate limit, with HTTP 429 or RESOURCE_EXHAUSTED. Operations rejected with RESOURCE_EXHAUSTED in
+// ReportErrors are quota rejections, other operations of the call are accepted.
+func isThrottled(err error) bool {
+	apiErr, ok := err.(*googleapi.Error)
+	return ok && (apiErr.Code == http.StatusTooManyRequests ||
+		strings.Contains(apiErr.Message, rpc.RESOURCE_EXHAUSTED.String()) ||
+		strings.Contains(apiErr.Body, rpc.RESOURCE_EXHAUSTED.String()))
+}
+
+// compileThrottlingExemption compiles the patterns of an exemption, which must match whole values.
+func compileThrottlingExemption(cfg *config.ThrottlingExemption) (throttlingExemption, error) {
+	exemption := throttlingExemption{label: cfg.Label}
+	var err error
+	if cfg.OperationPattern != "" {
+		if exemption.operation, err = regexp.Compile("^(?:" + cfg.OperationPattern + ")$"); err != nil {
+			return exemption, err
+		}
+	}
+	if cfg.LabelValuePattern != "" {
+		if exemption.labelValue, err = regexp.Compile("^(?:" + cfg.LabelValuePattern + ")$"); err != nil {
+			return exemption, err
+		}
+	}
+	return exemption, nil
+}
+
+// newReportThrottle creates reportThrottle from config, returns nil if it's not configured.
+func newReportThrottle(cfg *config.AdaptiveReportThrottling) (*reportThrottle, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	exemptions := make([]throttlingExemption, 0, len(cfg.Exemptions))
+	for _, exemptionCfg := range cfg.Exemptions {
+		exemption, err := compileThrottlingExemption(exemptionCfg)
+		if err != nil {
+			return nil, err
+		}
+		exemptions = append(exemptions, exemption)
+	}
+	return &reportThrottle{
+		backoffFactor: cfg.BackoffFactor,
+		recoveryStep:  cfg.RecoveryStep,
+		rand:          rand.Float64,
+		exemptions:    exemptions,
+		sendFraction:  1,
+	}, nil
+}
@@ -0,0 +1,248 @@
+// Copyright 2017 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcctrl
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+
+	rpc "github.com/googleapis/googleapis/google/rpc"
+	"google.golang.org/api/googleapi"
+	sc "google.golang.org/api/servicecontrol/v1"
+
+	"istio.io/istio/mixer/adapter/svcctrl/config"
+	"istio.io/istio/mixer/adapter/svcctrl/template/svcctrlreport"
+)
+
+func TestReportThrottle(t *testing.T) {
+	throttle, _ := newReportThrottle(&config.AdaptiveReportThrottling{
+		BackoffFactor: 0.5,
+		RecoveryStep:  0.25,
+	})
+	throttledErr := &googleapi.Error{Code: http.StatusTooManyRequests}
+
+	testCases := []struct {
+		err              error
+		throttled        bool
+		expectedFraction float64
+	}{
+		{throttledErr, true, 0.5},
+		{&googleapi.Error{Code: http.StatusForbidden, Message: "RESOURCE_EXHAUSTED: quota exceeded"}, true, 0.25},
+		// Other errors don't change the fraction.
+		{&googleapi.Error{Code: http.StatusInternalServerError}, false, 0.25},
+		{errors.New("connection refused"), false, 0.25},
+		{nil, false, 0.5},
+		{nil, false, 0.75},
+		{nil, false, 1},
+		{nil, false, 1},
+	}
+	for _, tc := range testCases {
+		if throttled := throttle.record(tc.err); throttled != tc.throttled {
+			t.Errorf(`expect %v to be throttled %v, but get %v`, tc.err, tc.throttled, throttled)
+		}
+		if math.Abs(throttle.sendFraction-tc.expectedFraction) > 1e-9 {
+			t.Errorf(`expect fraction %v after %v, but get %v`, tc.expectedFraction, tc.err, throttle.sendFraction)
+		}
+	}
+
+	// The fraction never drops below the minimum.
+	for i := 0; i < 20; i++ {
+		throttle.record(throttledErr)
+	}
+	if throttle.sendFraction != minReportSendFraction {
+		t.Errorf(`expect minimum fraction %v, but get %v`, minReportSendFraction, throttle.sendFraction)
+	}
+
+	if throttle, _ := newReportThrottle(nil); throttle != nil {
+		t.Error(`expect nil reportThrottle when not configured`)
+	}
+}
+
+func TestProcessReportThrottled(t *testing.T) {
+	test := reportProcessorTestSetup(t)
+	randValue := 0.6
+	throttle, _ := newReportThrottle(&config.AdaptiveReportThrottling{
+		BackoffFactor: 0.5,
+		RecoveryStep:  0.5,
+	})
+	throttle.rand = func() float64 { return randValue }
+	test.reportProc.throttle = throttle
+	instances := []*svcctrlreport.Instance{getTestReportInstance()}
+
+	test.mockClient.reportError = &googleapi.Error{Code: http.StatusTooManyRequests}
+	if err := test.reportProc.ProcessReport(context.Background(), instances); err != nil {
+		t.Errorf(`expect throttled report to be deferred, but get %v`, err)
+	}
+
+	// Half of report calls are sent after throttled, operations of others are deferred.
+	dropped := getCounterValue(droppedOperationCount, gcpServiceName, dropReasonThrottled)
+	test.mockClient.reset()
+	if err := test.reportProc.ProcessReport(context.Background(), instances); err != nil {
+		t.Errorf(`expect deferred report to succeed, but get %v`, err)
+	}
+	if test.mockClient.reportRequest != nil {
+		t.Error(`expect report not to be sent while throttled`)
+	}
+	if actual := getCounterValue(droppedOperationCount, gcpServiceName, dropReasonThrottled); actual != dropped {
+		t.Errorf(`expect no operation to be dropped, but get %v`, actual-dropped)
+	}
+
+	// Successful calls restore report calls, and carry deferred operations.
+	randValue = 0.4
+	test.mockClient.setReportResponse(&sc.ReportResponse{})
+	if err := test.reportProc.ProcessReport(context.Background(), instances); err != nil {
+		t.Errorf(`ProcessReport() failed with %v`, err)
+	}
+	if test.mockClient.reportRequest == nil || len(test.mockClient.reportRequest.Operations) != 3 {
+		t.Errorf(`expect report with the deferred operations to be sent, but get %v`, test.mockClient.reportRequest)
+	}
+	if throttle.sendFraction != 1 {
+		t.Errorf(`expect report to recover, but send fraction is %v`, throttle.sendFraction)
+	}
+}
+
+func TestProcessReportThrottledDeliveredOnce(t *testing.T) {
+	test := reportProcessorTestSetup(t)
+	throttle, _ := newReportThrottle(&config.AdaptiveReportThrottling{
+		BackoffFactor: 0.5,
+		RecoveryStep:  1,
+	})
+	throttle.rand = func() float64 { return 0 }
+	test.reportProc.throttle = throttle
+	delivered := make(map[string]int)
+	report := func(reportError error, response *sc.ReportResponse) error {
+		test.mockClient.reset()
+		test.mockClient.reportError = reportError
+		test.mockClient.reportResponse = response
+		err := test.reportProc.ProcessReport(context.Background(),
+			[]*svcctrlreport.Instance{getTestReportInstance()})
+		if reportError == nil {
+			for _, op := range test.mockClient.reportRequest.Operations {
+				delivered[op.OperationId]++
+			}
+		}
+		return err
+	}
+
+	if err := report(&googleapi.Error{Code: http.StatusTooManyRequests}, nil); err != nil {
+		t.Fatalf(`expect throttled report to be deferred, but get %v`, err)
+	}
+	if len(throttle.deferred) != 1 {
+		t.Fatalf(`expect operation of the throttled report to be deferred, but get %v`, throttle.deferred)
+	}
+	// Quota rejections of operations aren't throttling, other operations of the call are accepted.
+	if err := report(nil, &sc.ReportResponse{
+		ReportErrors: []*sc.ReportError{{
+			OperationId: "rejected",
+			Status:      &sc.Status{Code: int64(rpc.RESOURCE_EXHAUSTED), Message: "quota exceeded"},
+		}},
+	}); err == nil {
+		t.Error(`expect error of the rejected operation`)
+	}
+	if throttle.sendFraction != 1 {
+		t.Errorf(`expect quota rejection not to throttle reports, but send fraction is %v`, throttle.sendFraction)
+	}
+	if err := report(nil, &sc.ReportResponse{}); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+	if len(delivered) != 3 || len(throttle.deferred) != 0 {
+		t.Errorf(`expect 3 operations to be delivered, but get %v, %d deferred`, delivered, len(throttle.deferred))
+	}
+	for id, count := range delivered {
+		if count != 1 {
+			t.Errorf(`expect operation %s to be delivered once, but get %d`, id, count)
+		}
+	}
+}
+
+func TestProcessReportThrottlingExemptions(t *testing.T) {
+	test := reportProcessorTestSetup(t)
+	throttle, err := newReportThrottle(&config.AdaptiveReportThrottling{
+		BackoffFactor: 0.5,
+		RecoveryStep:  0.5,
+		Exemptions: []*config.ThrottlingExemption{
+			{OperationPattern: "billing.*"},
+			{Label: "/error_type", LabelValuePattern: "5xx"},
+		},
+	})
+	if err != nil {
+		t.Fatalf(`newReportThrottle() failed with %v`, err)
+	}
+	// Throttle all non exempted operations.
+	throttle.rand = func() float64 { return 1 }
+	throttle.sendFraction = minReportSendFraction
+	test.reportProc.throttle = throttle
+
+	newInstance := func(operation string, responseCode int64) *svcctrlreport.Instance {
+		instance := getTestReportInstance()
+		instance.ApiOperation = operation
+		instance.ResponseCode = responseCode
+		return instance
+	}
+	instances := []*svcctrlreport.Instance{
+		newInstance("echo", 200),
+		newInstance("billing.charge", 200),
+		newInstance("echo", 503),
+		newInstance("echo", 404),
+	}
+
+	dropped := getCounterValue(droppedOperationCount, gcpServiceName, dropReasonThrottled)
+	test.mockClient.setReportResponse(&sc.ReportResponse{})
+	if err := test.reportProc.ProcessReport(context.Background(), instances); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+	ops := test.mockClient.reportRequest.Operations
+	if len(ops) != 2 || ops[0].OperationName != "billing.charge" || ops[1].Labels["/error_type"] != "5xx" {
+		t.Errorf(`expect only exempted operations to be reported, but get %v`, ops)
+	}
+	if len(throttle.deferred) != 2 {
+		t.Errorf(`expect 2 deferred operations, but get %v`, throttle.deferred)
+	}
+
+	// Nothing is sent when no operation is exempted.
+	test.mockClient.reset()
+	if err := test.reportProc.ProcessReport(context.Background(), instances[:1]); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+	if test.mockClient.reportRequest != nil {
+		t.Errorf(`expect report to be deferred, but get %v`, *test.mockClient.reportRequest)
+	}
+
+	// Operations are dropped once too many are deferred.
+	filler := make([]*sc.Operation, maxDeferredReportOperations-len(throttle.deferred))
+	for i := range filler {
+		filler[i] = &sc.Operation{OperationName: "echo"}
+	}
+	throttle.deferOperations(filler)
+	if err := test.reportProc.ProcessReport(context.Background(), instances[:1]); err != nil {
+		t.Fatalf(`ProcessReport() failed with %v`, err)
+	}
+	if actual := getCounterValue(droppedOperationCount, gcpServiceName, dropReasonThrottled); actual != dropped+1 {
+		t.Errorf(`expect %v throttled operations, but get %v`, dropped+1, actual)
+	}
+
+	// Deferred operations are flushed on close.
+	throttle.takeDeferred(maxDeferredReportOperations - 3)
+	test.mockClient.setReportResponse(&sc.ReportResponse{})
+	if err := test.reportProc.Close(); err != nil {
+		t.Fatalf(`Close() failed with %v`, err)
+	}
+	if len(test.mockClient.reportRequest.Operations) != 3 || len(throttle.deferred) != 0 {
+		t.Errorf(`expect deferred operations to be flushed, but get %v`, test.mockClient.reportRequest.Operations)
+	}
+}
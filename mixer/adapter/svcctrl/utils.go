@@ -18,6 +18,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"time"
 
 	pbtypes "github.com/gogo/protobuf/types"
@@ -25,11 +27,20 @@ import (
 )
 
 const (
-	apiKeyPrefix = "api_key:"
+	apiKeyPrefix  = "api_key:"
+	projectPrefix = "project:"
 
 	logDebug = 4
 )
 
+// Names of attributes read from svcctrlreport attributes, e.g. "request.state".
+var attributeNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_\-]+(\.[a-zA-Z0-9_\-]+)*$`)
+
+// isValidAttributeName returns true if name is a valid name of a svcctrlreport attribute.
+func isValidAttributeName(name string) bool {
+	return attributeNameRegexp.MatchString(name)
+}
+
 func toRPCCode(responseCode int) rpc.Code {
 	switch responseCode {
 	case 200:
@@ -105,6 +116,29 @@ func serviceControlErrorToRPCCode(errorCode string) rpc.Code {
 	return rpc.UNKNOWN
 }
 
+// isDenialCheckError returns true if a check error code rejects the consumer, e.g. invalid API
+// keys and services not activated, rather than reporting an unavailable backend.
+func isDenialCheckError(errorCode string) bool {
+	switch serviceControlErrorToRPCCode(errorCode) {
+	case rpc.INVALID_ARGUMENT, rpc.PERMISSION_DENIED, rpc.RESOURCE_EXHAUSTED, rpc.NOT_FOUND:
+		return true
+	}
+	switch errorCode {
+	case "SERVICE_NOT_ACTIVATED",
+		"VISIBILITY_DENIED",
+		"BILLING_DISABLED",
+		"PROJECT_DELETED",
+		"PROJECT_INVALID",
+		"IP_ADDRESS_BLOCKED",
+		"REFERER_BLOCKED",
+		"CLIENT_APP_BLOCKED",
+		"API_TARGET_BLOCKED",
+		"LOAS_PROJECT_DISABLED":
+		return true
+	}
+	return false
+}
+
 func toDuration(durationProto *pbtypes.Duration) time.Duration {
 	duration, err := pbtypes.DurationFromProto(durationProto)
 	if err != nil {
@@ -123,6 +157,24 @@ func generateConsumerIDFromAPIKey(apiKey string) string {
 	return apiKeyPrefix + apiKey
 }
 
+func generateConsumerIDFromProject(projectID string) string {
+	return projectPrefix + projectID
+}
+
+// alignTime aligns t down to a multiple of alignment, returns t if alignment is not positive.
+func alignTime(t time.Time, alignment time.Duration) time.Time {
+	if alignment <= 0 {
+		return t
+	}
+	return t.Truncate(alignment)
+}
+
+// isTruthy returns true if an attribute value is true, e.g. "true" or "1".
+func isTruthy(value string) bool {
+	truthy, err := strconv.ParseBool(value)
+	return err == nil && truthy
+}
+
 func toFormattedJSON(marshaller json.Marshaler) (string, error) {
 	value, err := marshaller.MarshalJSON()
 	if err != nil {
@@ -148,3 +148,20 @@ func TestToFormattedJSON(t *testing.T) {
 		t.Errorf(`expected formatted JSON, expect '%s', but get '%s'`, expected, formattedJSON)
 	}
 }
+
+func TestIsValidAttributeName(t *testing.T) {
+	testCases := map[string]bool{
+		"request.state":        true,
+		"source.workload-name": true,
+		"x_header":             true,
+		"":                     false,
+		"request state":        false,
+		"request..state":       false,
+		".request":             false,
+	}
+	for name, expected := range testCases {
+		if actual := isValidAttributeName(name); actual != expected {
+			t.Errorf(`isValidAttributeName(%q) = %v, but expect %v`, name, actual, expected)
+		}
+	}
+}
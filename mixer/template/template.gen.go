@@ -207,6 +207,33 @@ var (
 					return nil, fmt.Errorf("error type checking for field ResponseLatency: Evaluated expression type %v want %v", t, istio_mixer_v1_config_descriptor.DURATION)
 				}
 
+				for _, v := range cpb.Attributes {
+					if t, e := tEvalFn(v); e != nil || t != istio_mixer_v1_config_descriptor.STRING {
+						if e != nil {
+							return nil, fmt.Errorf("failed to evaluate expression for field Attributes: %v", e)
+						}
+						return nil, fmt.Errorf("error type checking for field Attributes: Evaluated expression type %v want %v", t, istio_mixer_v1_config_descriptor.STRING)
+					}
+				}
+
+				for _, v := range cpb.RequestHeaders {
+					if t, e := tEvalFn(v); e != nil || t != istio_mixer_v1_config_descriptor.STRING {
+						if e != nil {
+							return nil, fmt.Errorf("failed to evaluate expression for field RequestHeaders: %v", e)
+						}
+						return nil, fmt.Errorf("error type checking for field RequestHeaders: Evaluated expression type %v want %v", t, istio_mixer_v1_config_descriptor.STRING)
+					}
+				}
+
+				for _, v := range cpb.ResponseTrailers {
+					if t, e := tEvalFn(v); e != nil || t != istio_mixer_v1_config_descriptor.STRING {
+						if e != nil {
+							return nil, fmt.Errorf("failed to evaluate expression for field ResponseTrailers: %v", e)
+						}
+						return nil, fmt.Errorf("error type checking for field ResponseTrailers: Evaluated expression type %v want %v", t, istio_mixer_v1_config_descriptor.STRING)
+					}
+				}
+
 				_ = cpb
 				return infrdType, err
 			},
@@ -331,6 +358,30 @@ var (
 						return errors.New(msg)
 					}
 
+					Attributes, err := template.EvalAll(md.Attributes, attrs, mapper)
+
+					if err != nil {
+						msg := fmt.Sprintf("failed to eval Attributes for instance '%s': %v", name, err)
+						glog.Error(msg)
+						return errors.New(msg)
+					}
+
+					RequestHeaders, err := template.EvalAll(md.RequestHeaders, attrs, mapper)
+
+					if err != nil {
+						msg := fmt.Sprintf("failed to eval RequestHeaders for instance '%s': %v", name, err)
+						glog.Error(msg)
+						return errors.New(msg)
+					}
+
+					ResponseTrailers, err := template.EvalAll(md.ResponseTrailers, attrs, mapper)
+
+					if err != nil {
+						msg := fmt.Sprintf("failed to eval ResponseTrailers for instance '%s': %v", name, err)
+						glog.Error(msg)
+						return errors.New(msg)
+					}
+
 					instances = append(instances, &svcctrlreport.Instance{
 						Name: name,
 
@@ -359,6 +410,30 @@ var (
 						ResponseBytes: ResponseBytes.(int64),
 
 						ResponseLatency: ResponseLatency.(time.Duration),
+
+						Attributes: func(m map[string]interface{}) map[string]string {
+							res := make(map[string]string, len(m))
+							for k, v := range m {
+								res[k] = v.(string)
+							}
+							return res
+						}(Attributes),
+
+						RequestHeaders: func(m map[string]interface{}) map[string]string {
+							res := make(map[string]string, len(m))
+							for k, v := range m {
+								res[k] = v.(string)
+							}
+							return res
+						}(RequestHeaders),
+
+						ResponseTrailers: func(m map[string]interface{}) map[string]string {
+							res := make(map[string]string, len(m))
+							for k, v := range m {
+								res[k] = v.(string)
+							}
+							return res
+						}(ResponseTrailers),
 					})
 					_ = md
 				}